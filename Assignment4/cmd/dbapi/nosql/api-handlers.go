@@ -2,73 +2,283 @@ package main
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
 	"time"
 
 	"github.com/adarshsrinivasan/DS_S24/library/common"
 	libProto "github.com/adarshsrinivasan/DS_S24/library/proto"
+	"github.com/adarshsrinivasan/DS_S24/library/raft"
+	"github.com/adarshsrinivasan/DS_S24/library/replication"
 	log "github.com/sirupsen/logrus"
-	"google.golang.org/protobuf/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// noSQLServer serves the reads and membership APIs of NOSQLService. Its
+// product writes are replicated by the interceptor of commands instead (see
+// replication.go).
 type noSQLServer struct {
 	libProto.UnimplementedNOSQLServiceServer
 }
 
 func (server *noSQLServer) GetLeader(ctx context.Context, request *libProto.GetLeaderRequest) (*libProto.GetLeaderResponse, error) {
-	for raftServer.cm.leaderID == "" {
-		log.Warnf("GetLeader(%s): LeaderID Empty. Waiting for leader to be assigned.", nodeName)
-		time.Sleep(100 * time.Millisecond)
+	if raftEngine == nil {
+		// The sequencer has no leader; every node serves every request.
+		return &libProto.GetLeaderResponse{
+			LeaderNodeName: nodeName,
+			Err:            nil,
+			LeaderAddress:  net.JoinHostPort(nodeName, strconv.Itoa(serverPort)),
+		}, nil
 	}
+	ctx, cancel := context.WithTimeout(ctx, getLeaderTimeout)
+	defer cancel()
+	ticker := time.NewTicker(getLeaderPollInterval)
+	defer ticker.Stop()
+
+	config, leaderID := raftEngine.Server().CM().Configuration()
+	for leaderID == "" {
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			err := status.Errorf(codes.Unavailable, "exception while waiting for a leader to be elected. %v", ctx.Err())
+			log.Errorf("GetLeader: %v\n", err)
+			return &libProto.GetLeaderResponse{
+				Err: common.ConvertErrorToProtoError(err),
+			}, err
+		}
+		config, leaderID = raftEngine.Server().CM().Configuration()
+	}
+	leader, _ := config.Member(leaderID)
 	return &libProto.GetLeaderResponse{
-		LeaderNodeName: raftServer.cm.leaderID,
+		LeaderNodeName: leaderID,
 		Err:            nil,
+		LeaderAddress:  leader.ClientAddr,
 	}, nil
 }
 
-func (server *noSQLServer) CreateProduct(ctx context.Context, request *libProto.CreateProductRequest) (*libProto.CreateProductResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := CreateProduct
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := noSQLServerHandlers{}
-	return handler.CreateProduct(ctx, request)
+func (server *noSQLServer) ClusterStatus(ctx context.Context, request *libProto.ClusterStatusRequest) (*libProto.ClusterStatusResponse, error) {
+	if raftEngine == nil {
+		return &libProto.ClusterStatusResponse{
+			StatusCode: int32(http.StatusOK),
+			Err:        nil,
+			NodeName:   nodeName,
+			State:      replication.EngineSequencer,
+			Members:    convertConfigurationToProtoMemberModels(ctx, bootstrapConfiguration(peerNodeNames, peerNodePorts)),
+		}, nil
+	}
+	raftStatus := raftEngine.Server().CM().Status()
+	response := &libProto.ClusterStatusResponse{
+		StatusCode:     int32(http.StatusOK),
+		Err:            nil,
+		NodeName:       raftStatus.ID,
+		State:          raftStatus.State,
+		Term:           int64(raftStatus.Term),
+		LeaderNodeName: raftStatus.LeaderID,
+		CommitIndex:    int64(raftStatus.CommitIndex),
+		LastApplied:    int64(raftStatus.LastApplied),
+		SnapshotIndex:  int64(raftStatus.SnapshotIndex),
+		LastLogIndex:   int64(raftStatus.LastLogIndex),
+		LogSize:        int64(raftStatus.LogSize),
+		Members:        convertConfigurationToProtoMemberModels(ctx, raftStatus.Config),
+		Followers:      convertFollowerStatusesToProtoFollowerStatusModels(ctx, raftStatus.Followers),
+	}
+	return response, nil
+}
+
+func (server *noSQLServer) AddNode(ctx context.Context, request *libProto.AddNodeRequest) (*libProto.AddNodeResponse, error) {
+	member := convertProtoMemberModelToMember(ctx, request.RequestModel)
+	statusCode, err := proposeConfigChange(ctx, func(requestID string) error {
+		return raftEngine.Server().CM().AddMember(requestID, member)
+	})
+	if err == nil && !member.Learner {
+		// The node joins as a learner; make it the voter requested once it
+		// has caught up.
+		statusCode, err = promoteWhenCaughtUp(ctx, member.ID)
+	}
+	if raftEngine == nil {
+		return &libProto.AddNodeResponse{
+			StatusCode: int32(statusCode),
+			Err:        common.ConvertErrorToProtoError(err),
+		}, err
+	}
+	config, _ := raftEngine.Server().CM().Configuration()
+	response := &libProto.AddNodeResponse{
+		StatusCode:    int32(statusCode),
+		Err:           common.ConvertErrorToProtoError(err),
+		ResponseModel: convertConfigurationToProtoMemberModels(ctx, config),
+	}
+	return response, err
+}
+
+func (server *noSQLServer) RemoveNode(ctx context.Context, request *libProto.RemoveNodeRequest) (*libProto.RemoveNodeResponse, error) {
+	member := convertProtoMemberModelToMember(ctx, request.RequestModel)
+	statusCode, err := proposeConfigChange(ctx, func(requestID string) error {
+		return raftEngine.Server().CM().RemoveMember(requestID, member.ID)
+	})
+	if raftEngine == nil {
+		return &libProto.RemoveNodeResponse{
+			StatusCode: int32(statusCode),
+			Err:        common.ConvertErrorToProtoError(err),
+		}, err
+	}
+	config, _ := raftEngine.Server().CM().Configuration()
+	response := &libProto.RemoveNodeResponse{
+		StatusCode:    int32(statusCode),
+		Err:           common.ConvertErrorToProtoError(err),
+		ResponseModel: convertConfigurationToProtoMemberModels(ctx, config),
+	}
+	return response, err
+}
+
+func (server *noSQLServer) PromoteNode(ctx context.Context, request *libProto.PromoteNodeRequest) (*libProto.PromoteNodeResponse, error) {
+	member := convertProtoMemberModelToMember(ctx, request.RequestModel)
+	statusCode, err := proposeConfigChange(ctx, func(requestID string) error {
+		return raftEngine.Server().CM().PromoteMember(requestID, member.ID)
+	})
+	if raftEngine == nil {
+		return &libProto.PromoteNodeResponse{
+			StatusCode: int32(statusCode),
+			Err:        common.ConvertErrorToProtoError(err),
+		}, err
+	}
+	config, _ := raftEngine.Server().CM().Configuration()
+	response := &libProto.PromoteNodeResponse{
+		StatusCode:    int32(statusCode),
+		Err:           common.ConvertErrorToProtoError(err),
+		ResponseModel: convertConfigurationToProtoMemberModels(ctx, config),
+	}
+	return response, err
+}
+
+func (server *noSQLServer) ListMembers(ctx context.Context, request *libProto.ListMembersRequest) (*libProto.ListMembersResponse, error) {
+	if raftEngine == nil {
+		return &libProto.ListMembersResponse{
+			StatusCode:    int32(http.StatusOK),
+			Err:           nil,
+			ResponseModel: convertConfigurationToProtoMemberModels(ctx, bootstrapConfiguration(peerNodeNames, peerNodePorts)),
+		}, nil
+	}
+	config, leaderID := raftEngine.Server().CM().Configuration()
+	response := &libProto.ListMembersResponse{
+		StatusCode:     int32(http.StatusOK),
+		Err:            nil,
+		ResponseModel:  convertConfigurationToProtoMemberModels(ctx, config),
+		LeaderNodeName: leaderID,
+	}
+	return response, nil
+}
+
+func (server *noSQLServer) TransferLeadership(ctx context.Context, request *libProto.TransferLeadershipRequest) (*libProto.TransferLeadershipResponse, error) {
+	if raftEngine == nil {
+		return &libProto.TransferLeadershipResponse{
+			StatusCode: int32(http.StatusBadRequest),
+			Err:        common.ConvertErrorToProtoError(errRaftOnly),
+		}, errRaftOnly
+	}
+	leaderClient, err := getLeaderClient(ctx)
+	if err != nil {
+		return &libProto.TransferLeadershipResponse{
+			StatusCode: int32(http.StatusServiceUnavailable),
+			Err:        common.ConvertErrorToProtoError(err),
+		}, err
+	}
+	if leaderClient != nil {
+		return leaderClient.TransferLeadership(forwardContext(ctx), request)
+	}
+	leaderID, err := raftEngine.Server().CM().TransferLeadership(ctx, request.GetTargetNodeName())
+	if err != nil {
+		err = fmt.Errorf("exception while transferring leadership. %w", err)
+		log.Errorf("TransferLeadership: %v\n", err)
+		statusCode := http.StatusServiceUnavailable
+		if errors.Is(err, raft.ErrInvalidTransferTarget) {
+			statusCode = http.StatusBadRequest
+		}
+		return &libProto.TransferLeadershipResponse{
+			StatusCode: int32(statusCode),
+			Err:        common.ConvertErrorToProtoError(err),
+		}, err
+	}
+	config, _ := raftEngine.Server().CM().Configuration()
+	leader, _ := config.Member(leaderID)
+	return &libProto.TransferLeadershipResponse{
+		StatusCode:     int32(http.StatusOK),
+		Err:            nil,
+		LeaderNodeName: leaderID,
+		LeaderAddress:  leader.ClientAddr,
+	}, nil
 }
 
 func (server *noSQLServer) GetProductByID(ctx context.Context, request *libProto.GetProductByIDRequest) (*libProto.GetProductByIDResponse, error) {
+	if request.GetConsistency() == libProto.CONSISTENCY_LEADER {
+		leaderClient, err := getLeaderClient(ctx)
+		if err != nil {
+			return &libProto.GetProductByIDResponse{
+				StatusCode: int32(http.StatusServiceUnavailable),
+				Err:        common.ConvertErrorToProtoError(err),
+			}, err
+		}
+		if leaderClient != nil {
+			return leaderClient.GetProductByID(forwardContext(ctx), request)
+		}
+	}
+	if statusCode, err := waitForConsistentRead(ctx, request.GetConsistency()); err != nil {
+		return &libProto.GetProductByIDResponse{
+			StatusCode: int32(statusCode),
+			Err:        common.ConvertErrorToProtoError(err),
+		}, err
+	}
 	handler := noSQLServerHandlers{}
 	return handler.GetProductByID(ctx, request)
 }
 func (server *noSQLServer) ListProductsByKeyWordsAndCategory(ctx context.Context, request *libProto.ListProductsByKeyWordsAndCategoryRequest) (*libProto.ListProductsByKeyWordsAndCategoryResponse, error) {
+	if request.GetConsistency() == libProto.CONSISTENCY_LEADER {
+		leaderClient, err := getLeaderClient(ctx)
+		if err != nil {
+			return &libProto.ListProductsByKeyWordsAndCategoryResponse{
+				StatusCode: int32(http.StatusServiceUnavailable),
+				Err:        common.ConvertErrorToProtoError(err),
+			}, err
+		}
+		if leaderClient != nil {
+			return leaderClient.ListProductsByKeyWordsAndCategory(forwardContext(ctx), request)
+		}
+	}
+	if statusCode, err := waitForConsistentRead(ctx, request.GetConsistency()); err != nil {
+		return &libProto.ListProductsByKeyWordsAndCategoryResponse{
+			StatusCode: int32(statusCode),
+			Err:        common.ConvertErrorToProtoError(err),
+		}, err
+	}
 	handler := noSQLServerHandlers{}
 	return handler.ListProductsByKeyWordsAndCategory(ctx, request)
 }
 func (server *noSQLServer) ListProductsBySellerID(ctx context.Context, request *libProto.ListProductsBySellerIDRequest) (*libProto.ListProductsBySellerIDResponse, error) {
+	if request.GetConsistency() == libProto.CONSISTENCY_LEADER {
+		leaderClient, err := getLeaderClient(ctx)
+		if err != nil {
+			return &libProto.ListProductsBySellerIDResponse{
+				StatusCode: int32(http.StatusServiceUnavailable),
+				Err:        common.ConvertErrorToProtoError(err),
+			}, err
+		}
+		if leaderClient != nil {
+			return leaderClient.ListProductsBySellerID(forwardContext(ctx), request)
+		}
+	}
+	if statusCode, err := waitForConsistentRead(ctx, request.GetConsistency()); err != nil {
+		return &libProto.ListProductsBySellerIDResponse{
+			StatusCode: int32(statusCode),
+			Err:        common.ConvertErrorToProtoError(err),
+		}, err
+	}
 	handler := noSQLServerHandlers{}
 	return handler.ListProductsBySellerID(ctx, request)
 }
-func (server *noSQLServer) UpdateProductByID(ctx context.Context, request *libProto.UpdateProductByIDRequest) (*libProto.UpdateProductByIDResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := UpdateProductByID
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := noSQLServerHandlers{}
-	return handler.UpdateProductByID(ctx, request)
-}
-func (server *noSQLServer) DeleteProductByID(ctx context.Context, request *libProto.DeleteProductByIDRequest) (*libProto.DeleteProductByIDResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := DeleteProductByID
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := noSQLServerHandlers{}
-	return handler.DeleteProductByID(ctx, request)
-}
 
 type noSQLServerHandlers struct {
 }
@@ -178,3 +388,60 @@ func convertProductTableModelToProtoProductModel(ctx context.Context, productTab
 		UpdatedAt:          timestamppb.New(productTableModel.UpdatedAt),
 	}
 }
+
+func convertProtoMemberModelToMember(ctx context.Context, protoMemberModel *libProto.MemberModel) raft.Member {
+	return raft.Member{
+		ID:         protoMemberModel.GetNodeName(),
+		RaftAddr:   protoMemberModel.GetRaftAddress(),
+		ClientAddr: protoMemberModel.GetClientAddress(),
+		Learner:    protoMemberModel.GetLearner(),
+	}
+}
+
+func convertConfigurationToProtoMemberModels(ctx context.Context, config raft.Configuration) []*libProto.MemberModel {
+	var protoMemberModels []*libProto.MemberModel
+	for _, member := range config.Members {
+		protoMemberModels = append(protoMemberModels, &libProto.MemberModel{
+			NodeName:      member.ID,
+			RaftAddress:   member.RaftAddr,
+			ClientAddress: member.ClientAddr,
+			Learner:       member.Learner,
+		})
+	}
+	return protoMemberModels
+}
+
+func convertFollowerStatusesToProtoFollowerStatusModels(ctx context.Context, followers []raft.FollowerStatus) []*libProto.FollowerStatusModel {
+	var protoFollowerStatusModels []*libProto.FollowerStatusModel
+	for _, follower := range followers {
+		millisSinceLastAck := int64(-1)
+		if follower.SinceLastAck >= 0 {
+			millisSinceLastAck = follower.SinceLastAck.Milliseconds()
+		}
+		protoFollowerStatusModels = append(protoFollowerStatusModels, &libProto.FollowerStatusModel{
+			NodeName:           follower.ID,
+			NextIndex:          int64(follower.NextIndex),
+			MatchIndex:         int64(follower.MatchIndex),
+			Probing:            follower.Probing,
+			Inflight:           int64(follower.Inflight),
+			MillisSinceLastAck: millisSinceLastAck,
+		})
+	}
+	return protoFollowerStatusModels
+}
+
+// statusCodeFromError maps the error a replicated write failed with to an
+// HTTP status code.
+func statusCodeFromError(err error) int {
+	if _, ok := common.GetNotLeaderDetails(err); ok {
+		return http.StatusServiceUnavailable
+	}
+	switch status.Code(err) {
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
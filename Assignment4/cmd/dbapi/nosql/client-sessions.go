@@ -0,0 +1,131 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"sync"
+
+	libProto "github.com/adarshsrinivasan/DS_S24/library/proto"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+)
+
+// MaxClientSessions bounds the number of client sessions the state machine
+// remembers. When it's exceeded, the least recently used session is dropped;
+// a retry of that client's last write would then be applied again.
+const MaxClientSessions = 10000
+
+// clientSession is what the state machine remembers about a client: its last
+// applied write and the response it produced.
+type clientSession struct {
+	SequenceNum int64
+	Response    []byte
+
+	// LastUsed orders sessions for eviction. It's a logical clock rather than
+	// a timestamp so that every replica evicts the same sessions.
+	LastUsed int64
+}
+
+// clientSessions is the deduplication table for replicated writes. It's
+// updated only while applying committed entries, in log order, so it's
+// identical on all replicas, and it's part of the product snapshot.
+type clientSessions struct {
+	mu       sync.Mutex
+	clock    int64
+	sessions map[string]clientSession
+}
+
+// clientSessionsSnapshot is the gob-encoded form of clientSessions.
+type clientSessionsSnapshot struct {
+	Clock    int64
+	Sessions map[string]clientSession
+}
+
+var sessions = newClientSessions()
+
+func newClientSessions() *clientSessions {
+	return &clientSessions{sessions: make(map[string]clientSession)}
+}
+
+// applyOnce applies a write identified by info through apply, unless that
+// write was applied before, in which case apply is skipped and the response
+// it produced then is unmarshalled into cached and returned instead. Writes
+// without client info are always applied.
+func (s *clientSessions) applyOnce(info *libProto.ClientRequestInfo, cached proto.Message, apply func() (proto.Message, error)) (proto.Message, error) {
+	if info.GetClientID() == "" {
+		return apply()
+	}
+
+	s.mu.Lock()
+	session, found := s.sessions[info.GetClientID()]
+	s.mu.Unlock()
+	if found && info.GetSequenceNum() == session.SequenceNum {
+		log.Infof("applyOnce(%s): request %d of client %s already applied. Returning cached response.", nodeName, info.GetSequenceNum(), info.GetClientID())
+		if err := proto.Unmarshal(session.Response, cached); err != nil {
+			err = fmt.Errorf("exception while unmarshalling cached response. %v", err)
+			log.Errorf("applyOnce: %v\n", err)
+			return nil, err
+		}
+		return cached, cachedResponseError(cached)
+	}
+	if found && info.GetSequenceNum() < session.SequenceNum {
+		err := fmt.Errorf("stale request %d of client %s. already applied %d", info.GetSequenceNum(), info.GetClientID(), session.SequenceNum)
+		log.Errorf("applyOnce: %v\n", err)
+		return nil, err
+	}
+
+	response, err := apply()
+	responseData, mErr := proto.Marshal(response)
+	if mErr != nil {
+		log.Fatalf("applyOnce(%s): exception while marshalling response. %v", nodeName, mErr)
+	}
+	s.record(info.GetClientID(), info.GetSequenceNum(), responseData)
+	return response, err
+}
+
+// record remembers response as the result of the client's write sequenceNum.
+func (s *clientSessions) record(clientID string, sequenceNum int64, response []byte) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock++
+	s.sessions[clientID] = clientSession{SequenceNum: sequenceNum, Response: response, LastUsed: s.clock}
+	if len(s.sessions) <= MaxClientSessions {
+		return
+	}
+	oldestID, oldest := "", int64(0)
+	for id, session := range s.sessions {
+		if oldestID == "" || session.LastUsed < oldest {
+			oldestID, oldest = id, session.LastUsed
+		}
+	}
+	delete(s.sessions, oldestID)
+}
+
+func (s *clientSessions) snapshot() clientSessionsSnapshot {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	snapshot := clientSessionsSnapshot{Clock: s.clock, Sessions: make(map[string]clientSession, len(s.sessions))}
+	for id, session := range s.sessions {
+		snapshot.Sessions[id] = session
+	}
+	return snapshot
+}
+
+func (s *clientSessions) restore(snapshot clientSessionsSnapshot) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.clock = snapshot.Clock
+	s.sessions = make(map[string]clientSession, len(snapshot.Sessions))
+	for id, session := range snapshot.Sessions {
+		s.sessions[id] = session
+	}
+}
+
+// cachedResponseError rebuilds the error a cached response was returned with.
+func cachedResponseError(response proto.Message) error {
+	withErr, ok := response.(interface{ GetErr() *libProto.Error })
+	if !ok || withErr.GetErr() == nil {
+		return nil
+	}
+	return errors.New(withErr.GetErr().GetMessage())
+}
@@ -0,0 +1,132 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+	"sync"
+	"time"
+
+	"github.com/adarshsrinivasan/DS_S24/library/common"
+	libProto "github.com/adarshsrinivasan/DS_S24/library/proto"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// forwardedByKey is the metadata key a follower sets on requests it forwards
+// to the leader. A node never forwards a request twice; if the leader has
+// moved on in the meantime it returns a NotLeader error instead.
+const forwardedByKey = "x-forwarded-by"
+
+const (
+	// getLeaderTimeout bounds how long GetLeader waits for a leader to be
+	// elected before failing with Unavailable.
+	getLeaderTimeout      = 3 * time.Second
+	getLeaderPollInterval = 100 * time.Millisecond
+)
+
+var (
+	leaderConnsMu sync.Mutex
+	leaderConns   = make(map[string]*grpc.ClientConn)
+)
+
+// notLeaderError returns the typed NotLeader error carrying this node's best
+// guess of the current leader.
+func notLeaderError() error {
+	config, leaderID := raftEngine.Server().CM().Configuration()
+	leader, _ := config.Member(leaderID)
+	return common.NewNotLeaderError(nodeName, leaderID, leader.ClientAddr)
+}
+
+// getLeaderConn decides where a request that has to be served by the leader
+// goes. It returns nil if this node is the leader, or runs on the sequencer,
+// and should serve it itself, or a connection to the leader otherwise.
+func getLeaderConn(ctx context.Context) (*grpc.ClientConn, error) {
+	if raftEngine == nil {
+		return nil, nil
+	}
+	if _, _, isLeader := raftEngine.Server().CM().Report(); isLeader {
+		return nil, nil
+	}
+	if md, ok := metadata.FromIncomingContext(ctx); ok && len(md.Get(forwardedByKey)) > 0 {
+		err := notLeaderError()
+		log.Errorf("getLeaderConn: request forwarded by %v. %v\n", md.Get(forwardedByKey), err)
+		return nil, err
+	}
+	config, leaderID := raftEngine.Server().CM().Configuration()
+	leader, found := config.Member(leaderID)
+	if !found || leader.ClientAddr == "" {
+		err := notLeaderError()
+		log.Errorf("getLeaderConn: %v\n", err)
+		return nil, err
+	}
+
+	leaderConnsMu.Lock()
+	defer leaderConnsMu.Unlock()
+	conn, found := leaderConns[leader.ClientAddr]
+	if !found {
+		var err error
+		conn, err = grpc.Dial(leader.ClientAddr, grpc.WithTransportCredentials(insecure.NewCredentials()))
+		if err != nil {
+			err = fmt.Errorf("exception while connecting to leader %s at %s. %v", leaderID, leader.ClientAddr, err)
+			log.Errorf("getLeaderConn: %v\n", err)
+			return nil, err
+		}
+		leaderConns[leader.ClientAddr] = conn
+	}
+	log.Infof("getLeaderConn(%s): forwarding to leader %s at %s", nodeName, leaderID, leader.ClientAddr)
+	return conn, nil
+}
+
+// getLeaderClient is getLeaderConn for a typed client.
+func getLeaderClient(ctx context.Context) (libProto.NOSQLServiceClient, error) {
+	conn, err := getLeaderConn(ctx)
+	if conn == nil {
+		return nil, err
+	}
+	return libProto.NewNOSQLServiceClient(conn), nil
+}
+
+// productWriteInterceptor runs before a write is replicated by the interceptor
+// of commands. A follower forwards the write to the leader as is; the leader
+// fixes the values all replicas must agree on.
+func productWriteInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	request, ok := req.(proto.Message)
+	if !ok || !commands.Registered(request) {
+		return handler(ctx, req)
+	}
+	conn, err := getLeaderConn(ctx)
+	if err != nil {
+		return commands.ErrorResponse(request, http.StatusServiceUnavailable, err), err
+	}
+	if conn != nil {
+		response := commands.NewResponse(request)
+		err := conn.Invoke(forwardContext(ctx), info.FullMethod, request, response)
+		return response, err
+	}
+
+	switch msg := request.(type) {
+	case *libProto.CreateProductRequest:
+		// Assign the ID and timestamps before replicating so all replicas
+		// agree on them.
+		if msg.RequestModel.ID == "" {
+			msg.RequestModel.ID = common.GenerateUUID()
+		}
+		msg.RequestModel.CreatedAt = timestamppb.Now()
+		msg.RequestModel.UpdatedAt = msg.RequestModel.CreatedAt
+	case *libProto.UpdateProductByIDRequest:
+		// Stamp the update before replicating so all replicas agree on it.
+		msg.RequestModel.UpdatedAt = timestamppb.Now()
+	}
+	return handler(ctx, req)
+}
+
+// forwardContext marks ctx as forwarded by this node for the outgoing call to
+// the leader.
+func forwardContext(ctx context.Context) context.Context {
+	return metadata.AppendToOutgoingContext(ctx, forwardedByKey, nodeName)
+}
@@ -2,39 +2,52 @@ package main
 
 import (
 	"context"
+	"encoding/json"
 	"fmt"
 	"net"
+	"net/http"
 	"strconv"
 
 	"github.com/adarshsrinivasan/DS_S24/library/common"
 	"github.com/adarshsrinivasan/DS_S24/library/db/nosql"
 	"github.com/adarshsrinivasan/DS_S24/library/proto"
+	"github.com/adarshsrinivasan/DS_S24/library/raft"
+	"github.com/adarshsrinivasan/DS_S24/library/replication"
+	"github.com/adarshsrinivasan/DS_S24/library/totem"
 	log "github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
 )
 
 const (
-	ServerHostEnv                 = "SERVER_HOST"
-	ServerPortEnv                 = "SERVER_PORT"
-	NOSQLSchemaNameEnv            = "MONGO_DB"
-	ServiceName                   = "server"
-	ProductDBNodeNameBase         = "product-db"
-	Connect_retry_count           = 5
-	Connect_retry_cooloff_seconds = 5
+	ServerHostEnv         = "SERVER_HOST"
+	ServerPortEnv         = "SERVER_PORT"
+	NOSQLSchemaNameEnv    = "MONGO_DB"
+	ServiceName           = "server"
+	ProductDBNodeNameBase = "product-db"
 )
 
 var (
-	err             error
-	ctx             context.Context
-	serverHost      = common.GetEnv(ServerHostEnv, "localhost")
-	serverPort, _   = strconv.Atoi(common.GetEnv(ServerPortEnv, "50001"))
-	nosqlSchemaName = common.GetEnv(NOSQLSchemaNameEnv, "marketplace")
-	syncHost        = common.GetEnv(common.SyncHostEnv, "localhost")
-	syncPort, _     = strconv.Atoi(common.GetEnv(common.SyncPortEnv, "60003"))
-	nodeName        = common.GetEnv(common.NodeNameEnv, fmt.Sprintf("%s1", ProductDBNodeNameBase))
-	peerNodeNames   = common.SplitCSV(common.GetEnv(common.PeerNodeNamesEnv, fmt.Sprintf("%s1,%s2,%s3,%s4,%s5", ProductDBNodeNameBase, ProductDBNodeNameBase, ProductDBNodeNameBase, ProductDBNodeNameBase, ProductDBNodeNameBase)))
-	peerNodePorts   = common.SplitCSV(common.GetEnv(common.PeerNodePortsEnv, fmt.Sprintf("%d,%d,%d,%d,%d", syncPort, syncPort, syncPort, syncPort, syncPort)))
-	raftServer      *Server
+	err                      error
+	ctx                      context.Context
+	serverHost               = common.GetEnv(ServerHostEnv, "localhost")
+	serverPort, _            = strconv.Atoi(common.GetEnv(ServerPortEnv, "50001"))
+	nosqlSchemaName          = common.GetEnv(NOSQLSchemaNameEnv, "marketplace")
+	syncHost                 = common.GetEnv(common.SyncHostEnv, "localhost")
+	syncPort, _              = strconv.Atoi(common.GetEnv(common.SyncPortEnv, "60003"))
+	raftStorageDir           = common.GetEnv(common.RaftStorageDirEnv, "")
+	raftSnapshotThreshold, _ = strconv.Atoi(common.GetEnv(common.RaftSnapshotThresholdEnv, "1000"))
+	raftJoin, _              = strconv.ParseBool(common.GetEnv(common.RaftJoinEnv, "false"))
+	raftDebugPort            = common.GetEnv(common.RaftDebugPortEnv, "60004")
+	replicationEngineName    = common.GetEnv(common.ReplicationEngineEnv, replication.EngineRaft)
+	nodeName                 = common.GetEnv(common.NodeNameEnv, fmt.Sprintf("%s1", ProductDBNodeNameBase))
+	peerNodeNames            = common.SplitCSV(common.GetEnv(common.PeerNodeNamesEnv, fmt.Sprintf("%s1,%s2,%s3,%s4,%s5", ProductDBNodeNameBase, ProductDBNodeNameBase, ProductDBNodeNameBase, ProductDBNodeNameBase, ProductDBNodeNameBase)))
+	peerNodePorts            = common.SplitCSV(common.GetEnv(common.PeerNodePortsEnv, fmt.Sprintf("%d,%d,%d,%d,%d", syncPort, syncPort, syncPort, syncPort, syncPort)))
+	replicationEngine        replication.Engine
+
+	// raftEngine and sequencerEngine are set for the engine in use, for the
+	// APIs specific to it.
+	raftEngine      *raft.Engine
+	sequencerEngine *totem.Engine
 )
 
 func initializeNOSQLDB(ctx context.Context, serviceName, schemaName string) error {
@@ -100,6 +113,29 @@ func verifyDBConnections(ctx context.Context) error {
 	return nil
 }
 
+// initRaftDebugServer serves the state of the Consensus Module as JSON at
+// /debug/raft on raftDebugPort. It's disabled if raftDebugPort is empty or
+// this node runs on the sequencer.
+func initRaftDebugServer(ctx context.Context) {
+	if raftDebugPort == "" || raftEngine == nil {
+		return
+	}
+	mux := http.NewServeMux()
+	mux.HandleFunc("/debug/raft", func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", "application/json")
+		if err := json.NewEncoder(w).Encode(raftEngine.Server().CM().Status()); err != nil {
+			log.Errorf("initRaftDebugServer: exception while encoding raft status. %v\n", err)
+		}
+	})
+	addr := fmt.Sprintf("%s:%s", serverHost, raftDebugPort)
+	go func() {
+		log.Infof("initRaftDebugServer: serving raft status at http://%s/debug/raft\n", addr)
+		if err := http.ListenAndServe(addr, mux); err != nil {
+			log.Errorf("initRaftDebugServer: exception while serving raft status. %v\n", err)
+		}
+	}()
+}
+
 func main() {
 	ctx = context.Background()
 
@@ -113,7 +149,8 @@ func main() {
 		log.Panicf("main: %v\n", err)
 	}
 
-	initRaftServer(ctx, nodeName, peerNodeNames, peerNodePorts)
+	initReplicationEngine(ctx, nodeName, peerNodeNames, peerNodePorts)
+	initRaftDebugServer(ctx)
 
 	log.Println("Server Listening ...")
 	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", serverHost, serverPort))
@@ -121,7 +158,7 @@ func main() {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	server := grpc.NewServer()
+	server := grpc.NewServer(grpc.ChainUnaryInterceptor(productWriteInterceptor, commands.UnaryServerInterceptor(replicationEngine)))
 	proto.RegisterNOSQLServiceServer(server, &noSQLServer{})
 
 	if err := server.Serve(lis); err != nil {
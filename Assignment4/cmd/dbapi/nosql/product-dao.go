@@ -109,6 +109,56 @@ func CreateProductTable(ctx context.Context) error {
 	return nosql.Client.CreateCollection(ctx, ProductTableName)
 }
 
+// ResetProductTable drops and recreates the product table. It is used when the
+// table is about to be rebuilt by replaying the replicated log.
+func ResetProductTable(ctx context.Context) error {
+	if err := nosql.VerifyNOSQLDatabaseConnection(ctx, nosql.Client); err != nil {
+		err := fmt.Errorf("exception while resetting %s table. %v", ProductTableName, err)
+		logrus.Errorf("ResetProductTable: %v\n", err)
+		return err
+	}
+	if err := nosql.Client.DropCollection(ctx, ProductTableName); err != nil {
+		err := fmt.Errorf("exception while dropping %s table. %v", ProductTableName, err)
+		logrus.Errorf("ResetProductTable: %v\n", err)
+		return err
+	}
+	return nosql.Client.CreateCollection(ctx, ProductTableName)
+}
+
+// ListAllProducts returns every document in the product table.
+func ListAllProducts(ctx context.Context) ([]ProductTableModel, int, error) {
+	if err := nosql.VerifyNOSQLDatabaseConnection(ctx, nosql.Client); err != nil {
+		err := fmt.Errorf("exception while listing %s table. %v", ProductTableName, err)
+		logrus.Errorf("ListAllProducts: %v\n", err)
+		return nil, http.StatusInternalServerError, err
+	}
+	var result []ProductTableModel
+	if statusCode, err := nosql.Client.FindMany(ctx, ProductTableName, nil, &result); err != nil {
+		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Read", ProductTableName, err)
+		logrus.Errorf("ListAllProducts: %v\n", err)
+		return nil, statusCode, err
+	}
+	return result, http.StatusOK, nil
+}
+
+// RestoreProductTable replaces the contents of the product table with
+// products.
+func RestoreProductTable(ctx context.Context, products []ProductTableModel) error {
+	if err := ResetProductTable(ctx); err != nil {
+		return err
+	}
+	documents := make([]interface{}, 0, len(products))
+	for _, product := range products {
+		documents = append(documents, product)
+	}
+	if _, err := nosql.Client.InsertMany(ctx, ProductTableName, documents); err != nil {
+		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Restore", ProductTableName, err)
+		logrus.Errorf("RestoreProductTable: %v\n", err)
+		return err
+	}
+	return nil
+}
+
 func (product *ProductTableModel) CreateProduct(ctx context.Context) (int, error) {
 	if err := nosql.VerifyNOSQLDatabaseConnection(ctx, nosql.Client); err != nil {
 		err := fmt.Errorf("exception while creating %s table. %v", ProductTableName, err)
@@ -116,9 +166,12 @@ func (product *ProductTableModel) CreateProduct(ctx context.Context) (int, error
 		return http.StatusInternalServerError, err
 	}
 
-	product.ID = uuid.New().String()
-	product.CreatedAt = time.Now()
-	product.UpdatedAt = time.Now()
+	// The ID and timestamps are assigned before the request is replicated so
+	// that every replica (and every replay of the log) inserts the same
+	// document.
+	if product.ID == "" {
+		product.ID = uuid.New().String()
+	}
 
 	return nosql.Client.InsertOne(ctx, ProductTableName, *product)
 }
@@ -210,7 +263,6 @@ func (product *ProductTableModel) UpdateProductByID(ctx context.Context) (int, e
 			ColumnValue:  product.ID,
 		},
 	}
-	product.UpdatedAt = time.Now()
 	if statusCode, err := nosql.Client.UpdateOne(ctx, ProductTableName, whereClause, *product); err != nil {
 		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Read", ProductTableName, err)
 		logrus.Errorf("GetProductBySellerID: %v\n", err)
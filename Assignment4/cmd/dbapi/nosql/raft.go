@@ -1,872 +0,0 @@
-// Core Raft implementation - Consensus Module.
-//
-// Eli Bendersky [https://eli.thegreenplace.net]
-// This code is in the public domain.
-package main
-
-import (
-	"bytes"
-	"context"
-	"encoding/gob"
-	"fmt"
-	"math/rand"
-	"net"
-	"os"
-	"sync"
-	"time"
-
-	"github.com/adarshsrinivasan/DS_S24/library/common"
-	libProto "github.com/adarshsrinivasan/DS_S24/library/proto"
-	log "github.com/sirupsen/logrus"
-	"google.golang.org/protobuf/proto"
-)
-
-var (
-	responseTrackers = make(map[string]chan bool)
-)
-
-type opsType int
-
-const (
-	CreateProduct opsType = iota
-	UpdateProductByID
-	DeleteProductByID
-)
-
-var opsTypeToStr = map[opsType]string{
-	CreateProduct:     "CreateProduct",
-	UpdateProductByID: "UpdateProductByID",
-	DeleteProductByID: "DeleteProductByID",
-}
-
-const DebugCM = 1
-
-// CommitEntry is the data reported by Raft to the commit channel. Each commit
-// entry notifies the client that consensus was reached on a command, and it can
-// be applied to the client's state machine.
-type CommitEntry struct {
-	// ID is used to uniquely identify a request
-	ID string
-
-	// Command is the client command being committed.
-	Command opsType
-
-	// Payload for the command.
-	Payload []byte
-
-	// Index is the log index at which the client command is committed.
-	Index int
-
-	// Term is the Raft term at which the client command is committed.
-	Term int
-}
-
-type CMState int
-
-const (
-	Follower CMState = iota
-	Candidate
-	Leader
-	Dead
-)
-
-func (s CMState) String() string {
-	switch s {
-	case Follower:
-		return "Follower"
-	case Candidate:
-		return "Candidate"
-	case Leader:
-		return "Leader"
-	case Dead:
-		return "Dead"
-	default:
-		panic("unreachable")
-	}
-}
-
-type LogEntry struct {
-	ID      string
-	Command opsType
-	Payload []byte
-	Term    int
-}
-
-// ConsensusModule (CM) implements a single node of Raft consensus.
-type ConsensusModule struct {
-	// mu protects concurrent access to a CM.
-	mu sync.Mutex
-
-	// id is the server ID of this CM.
-	id string
-
-	// peerIds lists the IDs of our peers in the cluster.
-	peerIds []string
-
-	// server is the server containing this CM. It's used to issue RPC calls
-	// to peers.
-	server *Server
-
-	// storage is used to persist state.
-	storage Storage
-
-	// commitChan is the channel where this CM is going to report committed log
-	// entries. It's passed in by the client during construction.
-	commitChan chan<- CommitEntry
-
-	// newCommitReadyChan is an internal notification channel used by goroutines
-	// that commit new entries to the log to notify that these entries may be sent
-	// on commitChan.
-	newCommitReadyChan chan struct{}
-
-	// triggerAEChan is an internal notification channel used to trigger
-	// sending new AEs to followers when interesting changes occurred.
-	triggerAEChan chan struct{}
-
-	// Persistent Raft state on all servers
-	currentTerm int
-	votedFor    string
-	log         []LogEntry
-
-	// Volatile Raft state on all servers
-	commitIndex        int
-	lastApplied        int
-	state              CMState
-	electionResetEvent time.Time
-
-	// Volatile Raft state on leaders
-	nextIndex  map[string]int
-	matchIndex map[string]int
-
-	// LeaderID of the leader for the current term
-	leaderID string
-}
-
-// NewConsensusModule creates a new CM with the given ID, list of peer IDs and
-// server. The ready channel signals the CM that all peers are connected, and
-// it's safe to start its state machine. commitChan is going to be used by the
-// CM to send log entries that have been committed by the Raft cluster.
-func NewConsensusModule(id string, peerIds []string, server *Server, storage Storage, ready <-chan interface{}, commitChan chan<- CommitEntry) *ConsensusModule {
-	cm := new(ConsensusModule)
-	cm.id = id
-	cm.peerIds = peerIds
-	cm.server = server
-	cm.storage = storage
-	cm.commitChan = commitChan
-	cm.newCommitReadyChan = make(chan struct{}, 16)
-	cm.triggerAEChan = make(chan struct{}, 1)
-	cm.state = Follower
-	cm.votedFor = ""
-	cm.commitIndex = -1
-	cm.lastApplied = -1
-	cm.nextIndex = make(map[string]int)
-	cm.matchIndex = make(map[string]int)
-
-	if cm.storage.HasData() {
-		cm.restoreFromStorage()
-	}
-
-	go func() {
-		// The CM is dormant until ready is signaled; then, it starts a countdown
-		// for leader election.
-		<-ready
-		cm.mu.Lock()
-		cm.electionResetEvent = time.Now()
-		cm.mu.Unlock()
-		cm.runElectionTimer()
-	}()
-
-	go cm.commitChanSender()
-	return cm
-}
-
-// Report reports the state of this CM.
-func (cm *ConsensusModule) Report() (id string, term int, isLeader bool) {
-	cm.mu.Lock()
-	defer cm.mu.Unlock()
-	return cm.id, cm.currentTerm, cm.state == Leader
-}
-
-// Submit submits a new command to the CM. This function doesn't block; clients
-// read the commit channel passed in the constructor to be notified of new
-// committed entries. It returns true iff this CM is the leader - in which case
-// the command is accepted. If false is returned, the client will have to find
-// a different CM to submit this command to.
-func (cm *ConsensusModule) Submit(id string, command opsType, payload []byte) bool {
-	cm.mu.Lock()
-	cm.dlog("Submit received by %v: %v", cm.state, command)
-	if cm.state == Leader {
-		cm.log = append(cm.log, LogEntry{ID: id, Command: command, Payload: payload, Term: cm.currentTerm})
-		cm.persistToStorage()
-		cm.dlog("... log=%v", cm.log)
-		cm.mu.Unlock()
-		cm.triggerAEChan <- struct{}{}
-		return true
-	}
-
-	cm.mu.Unlock()
-	return false
-}
-
-// Stop stops this CM, cleaning up its state. This method returns quickly, but
-// it may take a bit of time (up to ~election timeout) for all goroutines to
-// exit.
-func (cm *ConsensusModule) Stop() {
-	cm.mu.Lock()
-	defer cm.mu.Unlock()
-	cm.state = Dead
-	cm.dlog("becomes Dead")
-	close(cm.newCommitReadyChan)
-}
-
-// restoreFromStorage restores the persistent state of this CM from storage.
-// It should be called during constructor, before any concurrency concerns.
-func (cm *ConsensusModule) restoreFromStorage() {
-	if termData, found := cm.storage.Get("currentTerm"); found {
-		d := gob.NewDecoder(bytes.NewBuffer(termData))
-		if err := d.Decode(&cm.currentTerm); err != nil {
-			log.Fatal(err)
-		}
-	} else {
-		log.Fatal("currentTerm not found in storage")
-	}
-	if votedData, found := cm.storage.Get("votedFor"); found {
-		d := gob.NewDecoder(bytes.NewBuffer(votedData))
-		if err := d.Decode(&cm.votedFor); err != nil {
-			log.Fatal(err)
-		}
-	} else {
-		log.Fatal("votedFor not found in storage")
-	}
-	if logData, found := cm.storage.Get("log"); found {
-		d := gob.NewDecoder(bytes.NewBuffer(logData))
-		if err := d.Decode(&cm.log); err != nil {
-			log.Fatal(err)
-		}
-	} else {
-		log.Fatal("log not found in storage")
-	}
-}
-
-// persistToStorage saves all of CM's persistent state in cm.storage.
-// Expects cm.mu to be locked.
-func (cm *ConsensusModule) persistToStorage() {
-	var termData bytes.Buffer
-	if err := gob.NewEncoder(&termData).Encode(cm.currentTerm); err != nil {
-		log.Fatal(err)
-	}
-	cm.storage.Set("currentTerm", termData.Bytes())
-
-	var votedData bytes.Buffer
-	if err := gob.NewEncoder(&votedData).Encode(cm.votedFor); err != nil {
-		log.Fatal(err)
-	}
-	cm.storage.Set("votedFor", votedData.Bytes())
-
-	var logData bytes.Buffer
-	if err := gob.NewEncoder(&logData).Encode(cm.log); err != nil {
-		log.Fatal(err)
-	}
-	cm.storage.Set("log", logData.Bytes())
-}
-
-// dlog logs a debugging message is DebugCM > 0.
-func (cm *ConsensusModule) dlog(format string, args ...interface{}) {
-	if DebugCM > 0 {
-		format = fmt.Sprintf("[%s] ", cm.id) + format
-		log.Printf(format, args...)
-	}
-}
-
-// RequestVoteArgs See figure 2 in the paper.
-type RequestVoteArgs struct {
-	Term         int
-	CandidateId  string
-	LastLogIndex int
-	LastLogTerm  int
-}
-
-type RequestVoteReply struct {
-	Term        int
-	VoteGranted bool
-}
-
-// RequestVote RPC.
-func (cm *ConsensusModule) RequestVote(args RequestVoteArgs, reply *RequestVoteReply) error {
-	cm.mu.Lock()
-	defer cm.mu.Unlock()
-	if cm.state == Dead {
-		return nil
-	}
-	lastLogIndex, lastLogTerm := cm.lastLogIndexAndTerm()
-	cm.dlog("RequestVote: %+v [currentTerm=%d, votedFor=%s, log index/term=(%d, %d)]", args, cm.currentTerm, cm.votedFor, lastLogIndex, lastLogTerm)
-
-	if args.Term > cm.currentTerm {
-		cm.dlog("... term out of date in RequestVote")
-		cm.becomeFollower(args.Term)
-	}
-
-	if cm.currentTerm == args.Term &&
-		(cm.votedFor == "" || cm.votedFor == args.CandidateId) &&
-		(args.LastLogTerm > lastLogTerm ||
-			(args.LastLogTerm == lastLogTerm && args.LastLogIndex >= lastLogIndex)) {
-		reply.VoteGranted = true
-		cm.votedFor = args.CandidateId
-		cm.electionResetEvent = time.Now()
-	} else {
-		reply.VoteGranted = false
-	}
-	reply.Term = cm.currentTerm
-	cm.persistToStorage()
-	cm.dlog("... RequestVote reply: %+v", reply)
-	return nil
-}
-
-// AppendEntriesArgs See figure 2 in the paper.
-type AppendEntriesArgs struct {
-	Term     int
-	LeaderId string
-
-	PrevLogIndex int
-	PrevLogTerm  int
-	Entries      []LogEntry
-	LeaderCommit int
-}
-
-type AppendEntriesReply struct {
-	Term    int
-	Success bool
-
-	// Faster conflict resolution optimization (described near the end of section
-	// 5.3 in the paper.)
-	ConflictIndex int
-	ConflictTerm  int
-}
-
-func (cm *ConsensusModule) AppendEntries(args AppendEntriesArgs, reply *AppendEntriesReply) error {
-	cm.mu.Lock()
-	defer cm.mu.Unlock()
-	if cm.state == Dead {
-		return nil
-	}
-	//cm.dlog("AppendEntries: %+v", args)
-
-	if args.Term > cm.currentTerm {
-		cm.dlog("... term out of date in AppendEntries")
-		cm.becomeFollower(args.Term)
-	}
-
-	reply.Success = false
-	if args.Term == cm.currentTerm {
-		cm.leaderID = args.LeaderId
-		if cm.state != Follower {
-			cm.becomeFollower(args.Term)
-		}
-		cm.electionResetEvent = time.Now()
-
-		// Does our log contain an entry at PrevLogIndex whose term matches
-		// PrevLogTerm? Note that in the extreme case of PrevLogIndex=-1 this is
-		// vacuously true.
-		if args.PrevLogIndex == -1 ||
-			(args.PrevLogIndex < len(cm.log) && args.PrevLogTerm == cm.log[args.PrevLogIndex].Term) {
-			reply.Success = true
-
-			// Find an insertion point - where there's a term mismatch between
-			// the existing log starting at PrevLogIndex+1 and the new entries sent
-			// in the RPC.
-			logInsertIndex := args.PrevLogIndex + 1
-			newEntriesIndex := 0
-
-			for {
-				if logInsertIndex >= len(cm.log) || newEntriesIndex >= len(args.Entries) {
-					break
-				}
-				if cm.log[logInsertIndex].Term != args.Entries[newEntriesIndex].Term {
-					break
-				}
-				logInsertIndex++
-				newEntriesIndex++
-			}
-			// At the end of this loop:
-			// - logInsertIndex points at the end of the log, or an index where the
-			//   term mismatches with an entry from the leader
-			// - newEntriesIndex points at the end of Entries, or an index where the
-			//   term mismatches with the corresponding log entry
-			if newEntriesIndex < len(args.Entries) {
-				cm.dlog("... inserting entries %v from index %d", args.Entries[newEntriesIndex:], logInsertIndex)
-				cm.log = append(cm.log[:logInsertIndex], args.Entries[newEntriesIndex:]...)
-				cm.dlog("... log is now: %v", cm.log)
-			}
-
-			// Set commit index.
-			if args.LeaderCommit > cm.commitIndex {
-				cm.commitIndex = min(args.LeaderCommit, len(cm.log)-1)
-				cm.dlog("... setting commitIndex=%d", cm.commitIndex)
-				cm.newCommitReadyChan <- struct{}{}
-			}
-		} else {
-			// No match for PrevLogIndex/PrevLogTerm. Populate
-			// ConflictIndex/ConflictTerm to help the leader bring us up to date
-			// quickly.
-			if args.PrevLogIndex >= len(cm.log) {
-				reply.ConflictIndex = len(cm.log)
-				reply.ConflictTerm = -1
-			} else {
-				// PrevLogIndex points within our log, but PrevLogTerm doesn't match
-				// cm.log[PrevLogIndex].
-				reply.ConflictTerm = cm.log[args.PrevLogIndex].Term
-
-				var i int
-				for i = args.PrevLogIndex - 1; i >= 0; i-- {
-					if cm.log[i].Term != reply.ConflictTerm {
-						break
-					}
-				}
-				reply.ConflictIndex = i + 1
-			}
-		}
-	}
-
-	reply.Term = cm.currentTerm
-	cm.persistToStorage()
-	//cm.dlog("AppendEntries reply: %+v", *reply)
-	return nil
-}
-
-// electionTimeout generates a pseudo-random election timeout duration.
-func (cm *ConsensusModule) electionTimeout() time.Duration {
-	// If RAFT_FORCE_MORE_REELECTION is set, stress-test by deliberately
-	// generating a hard-coded number very often. This will create collisions
-	// between different servers and force more re-elections.
-	if len(os.Getenv("RAFT_FORCE_MORE_REELECTION")) > 0 && rand.Intn(3) == 0 {
-		return time.Duration(150) * time.Millisecond
-	} else {
-		return time.Duration(150+rand.Intn(150)) * time.Millisecond
-	}
-}
-
-// runElectionTimer implements an election timer. It should be launched whenever
-// we want to start a timer towards becoming a candidate in a new election.
-//
-// This function is blocking and should be launched in a separate goroutine;
-// it's designed to work for a single (one-shot) election timer, as it exits
-// whenever the CM state changes from follower/candidate or the term changes.
-func (cm *ConsensusModule) runElectionTimer() {
-	timeoutDuration := cm.electionTimeout()
-	cm.mu.Lock()
-	termStarted := cm.currentTerm
-	cm.mu.Unlock()
-	cm.dlog("election timer started (%v), term=%d", timeoutDuration, termStarted)
-
-	// This loops until either:
-	// - we discover the election timer is no longer needed, or
-	// - the election timer expires and this CM becomes a candidate
-	// In a follower, this typically keeps running in the background for the
-	// duration of the CM's lifetime.
-	ticker := time.NewTicker(10 * time.Millisecond)
-	defer ticker.Stop()
-	for {
-		<-ticker.C
-
-		cm.mu.Lock()
-		if cm.state != Candidate && cm.state != Follower {
-			cm.dlog("in election timer state=%s, bailing out", cm.state)
-			cm.mu.Unlock()
-			return
-		}
-
-		if termStarted != cm.currentTerm {
-			cm.dlog("in election timer term changed from %d to %d, bailing out", termStarted, cm.currentTerm)
-			cm.mu.Unlock()
-			return
-		}
-
-		// Start an election if we haven't heard from a leader or haven't voted for
-		// someone for the duration of the timeout.
-		if elapsed := time.Since(cm.electionResetEvent); elapsed >= timeoutDuration {
-			cm.startElection()
-			cm.mu.Unlock()
-			return
-		}
-		cm.mu.Unlock()
-	}
-}
-
-// startElection starts a new election with this CM as a candidate.
-// Expects cm.mu to be locked.
-func (cm *ConsensusModule) startElection() {
-	cm.state = Candidate
-	cm.leaderID = ""
-	cm.currentTerm += 1
-	savedCurrentTerm := cm.currentTerm
-	cm.electionResetEvent = time.Now()
-	cm.votedFor = cm.id
-	cm.dlog("becomes Candidate (currentTerm=%d); log=%v", savedCurrentTerm, cm.log)
-
-	votesReceived := 1
-
-	// Send RequestVote RPCs to all other servers concurrently.
-	for _, peerId := range cm.peerIds {
-		go func(peerId string) {
-			cm.mu.Lock()
-			savedLastLogIndex, savedLastLogTerm := cm.lastLogIndexAndTerm()
-			cm.mu.Unlock()
-
-			args := RequestVoteArgs{
-				Term:         savedCurrentTerm,
-				CandidateId:  cm.id,
-				LastLogIndex: savedLastLogIndex,
-				LastLogTerm:  savedLastLogTerm,
-			}
-
-			cm.dlog("sending RequestVote to %s: %+v", peerId, args)
-			var reply RequestVoteReply
-			if err := cm.server.Call(peerId, "ConsensusModule.RequestVote", args, &reply); err == nil {
-				cm.mu.Lock()
-				defer cm.mu.Unlock()
-				cm.dlog("received RequestVoteReply %+v", reply)
-
-				if cm.state != Candidate {
-					cm.dlog("while waiting for reply, state = %v", cm.state)
-					return
-				}
-
-				if reply.Term > savedCurrentTerm {
-					cm.dlog("term out of date in RequestVoteReply")
-					cm.becomeFollower(reply.Term)
-					return
-				} else if reply.Term == savedCurrentTerm {
-					if reply.VoteGranted {
-						votesReceived += 1
-						if votesReceived*2 > len(cm.peerIds)+1 {
-							// Won the election!
-							cm.leaderID = nodeName
-							cm.dlog("wins election with %d votes", votesReceived)
-							cm.startLeader()
-							return
-						}
-
-					}
-				}
-			}
-		}(peerId)
-	}
-
-	// Run another election timer, in case this election is not successful.
-	go cm.runElectionTimer()
-}
-
-// becomeFollower makes cm a follower and resets its state.
-// Expects cm.mu to be locked.
-func (cm *ConsensusModule) becomeFollower(term int) {
-	cm.dlog("becomes Follower with term=%d; log=%v", term, cm.log)
-	cm.state = Follower
-	cm.currentTerm = term
-	cm.votedFor = ""
-	cm.electionResetEvent = time.Now()
-
-	go cm.runElectionTimer()
-}
-
-// startLeader switches cm into a leader state and begins process of heartbeats.
-// Expects cm.mu to be locked.
-func (cm *ConsensusModule) startLeader() {
-	cm.state = Leader
-
-	for _, peerId := range cm.peerIds {
-		cm.nextIndex[peerId] = len(cm.log)
-		cm.matchIndex[peerId] = -1
-	}
-	cm.dlog("becomes Leader; term=%d, nextIndex=%v, matchIndex=%v; log=%v", cm.currentTerm, cm.nextIndex, cm.matchIndex, cm.log)
-
-	// This goroutine runs in the background and sends AEs to peers:
-	// * Whenever something is sent on triggerAEChan
-	// * ... Or every 50 ms, if no events occur on triggerAEChan
-	go func(heartbeatTimeout time.Duration) {
-		// Immediately send AEs to peers.
-		cm.leaderSendAEs()
-
-		t := time.NewTimer(heartbeatTimeout)
-		defer t.Stop()
-		for {
-			doSend := false
-			select {
-			case <-t.C:
-				doSend = true
-
-				// Reset timer to fire again after heartbeatTimeout.
-				t.Stop()
-				t.Reset(heartbeatTimeout)
-			case _, ok := <-cm.triggerAEChan:
-				if ok {
-					doSend = true
-				} else {
-					return
-				}
-
-				// Reset timer for heartbeatTimeout.
-				if !t.Stop() {
-					<-t.C
-				}
-				t.Reset(heartbeatTimeout)
-			}
-
-			if doSend {
-				// If this isn't a leader anymore, stop the heartbeat loop.
-				cm.mu.Lock()
-				if cm.state != Leader {
-					cm.mu.Unlock()
-					return
-				}
-				cm.mu.Unlock()
-				cm.leaderSendAEs()
-			}
-		}
-	}(50 * time.Millisecond)
-}
-
-// leaderSendAEs sends a round of AEs to all peers, collects their
-// replies and adjusts cm's state.
-func (cm *ConsensusModule) leaderSendAEs() {
-	cm.mu.Lock()
-	if cm.state != Leader {
-		cm.mu.Unlock()
-		return
-	}
-	savedCurrentTerm := cm.currentTerm
-	cm.mu.Unlock()
-
-	for _, peerId := range cm.peerIds {
-		go func(peerId string) {
-			cm.mu.Lock()
-			ni := cm.nextIndex[peerId]
-			prevLogIndex := ni - 1
-			prevLogTerm := -1
-			if prevLogIndex >= 0 {
-				prevLogTerm = cm.log[prevLogIndex].Term
-			}
-			entries := cm.log[ni:]
-
-			args := AppendEntriesArgs{
-				Term:         savedCurrentTerm,
-				LeaderId:     cm.id,
-				PrevLogIndex: prevLogIndex,
-				PrevLogTerm:  prevLogTerm,
-				Entries:      entries,
-				LeaderCommit: cm.commitIndex,
-			}
-			cm.mu.Unlock()
-			//cm.dlog("sending AppendEntries to %v: ni=%d, args=%+v", peerId, ni, args)
-			var reply AppendEntriesReply
-			if err := cm.server.Call(peerId, "ConsensusModule.AppendEntries", args, &reply); err == nil {
-				cm.mu.Lock()
-				defer cm.mu.Unlock()
-				if reply.Term > cm.currentTerm {
-					cm.dlog("term out of date in heartbeat reply")
-					cm.becomeFollower(reply.Term)
-					return
-				}
-
-				if cm.state == Leader && savedCurrentTerm == reply.Term {
-					if reply.Success {
-						cm.nextIndex[peerId] = ni + len(entries)
-						cm.matchIndex[peerId] = cm.nextIndex[peerId] - 1
-
-						savedCommitIndex := cm.commitIndex
-						for i := cm.commitIndex + 1; i < len(cm.log); i++ {
-							if cm.log[i].Term == cm.currentTerm {
-								matchCount := 1
-								for _, peerId := range cm.peerIds {
-									if cm.matchIndex[peerId] >= i {
-										matchCount++
-									}
-								}
-								if matchCount*2 > len(cm.peerIds)+1 {
-									cm.commitIndex = i
-								}
-							}
-						}
-						//cm.dlog("AppendEntries reply from %s success: nextIndex := %v, matchIndex := %v; commitIndex := %d", peerId, cm.nextIndex, cm.matchIndex, cm.commitIndex)
-						if cm.commitIndex != savedCommitIndex {
-							cm.dlog("leader sets commitIndex := %d", cm.commitIndex)
-							// Commit index changed: the leader considers new entries to be
-							// committed. Send new entries on the commit channel to this
-							// leader's clients, and notify followers by sending them AEs.
-							cm.newCommitReadyChan <- struct{}{}
-							cm.triggerAEChan <- struct{}{}
-						}
-					} else {
-						if reply.ConflictTerm >= 0 {
-							lastIndexOfTerm := -1
-							for i := len(cm.log) - 1; i >= 0; i-- {
-								if cm.log[i].Term == reply.ConflictTerm {
-									lastIndexOfTerm = i
-									break
-								}
-							}
-							if lastIndexOfTerm >= 0 {
-								cm.nextIndex[peerId] = lastIndexOfTerm + 1
-							} else {
-								cm.nextIndex[peerId] = reply.ConflictIndex
-							}
-						} else {
-							cm.nextIndex[peerId] = reply.ConflictIndex
-						}
-						cm.dlog("AppendEntries reply from %s !success: nextIndex := %d", peerId, ni-1)
-					}
-				}
-			}
-		}(peerId)
-	}
-}
-
-// lastLogIndexAndTerm returns the last log index and the last log entry's term
-// (or -1 if there's no log) for this server.
-// Expects cm.mu to be locked.
-func (cm *ConsensusModule) lastLogIndexAndTerm() (int, int) {
-	if len(cm.log) > 0 {
-		lastIndex := len(cm.log) - 1
-		return lastIndex, cm.log[lastIndex].Term
-	} else {
-		return -1, -1
-	}
-}
-
-// commitChanSender is responsible for sending committed entries on
-// cm.commitChan. It watches newCommitReadyChan for notifications and calculates
-// which new entries are ready to be sent. This method should run in a separate
-// background goroutine; cm.commitChan may be buffered and will limit how fast
-// the client consumes new committed entries. Returns when newCommitReadyChan is
-// closed.
-func (cm *ConsensusModule) commitChanSender() {
-	for range cm.newCommitReadyChan {
-		// Find which entries we have to apply.
-		cm.mu.Lock()
-		savedTerm := cm.currentTerm
-		savedLastApplied := cm.lastApplied
-		var entries []LogEntry
-		if cm.commitIndex > cm.lastApplied {
-			entries = cm.log[cm.lastApplied+1 : cm.commitIndex+1]
-			cm.lastApplied = cm.commitIndex
-		}
-		cm.mu.Unlock()
-		cm.dlog("commitChanSender entries=%v, savedLastApplied=%d", entries, savedLastApplied)
-
-		for i, entry := range entries {
-			cm.commitChan <- CommitEntry{
-				ID:      entry.ID,
-				Command: entry.Command,
-				Payload: entry.Payload,
-				Index:   savedLastApplied + i + 1,
-				Term:    savedTerm,
-			}
-		}
-	}
-	cm.dlog("commitChanSender done")
-}
-
-func sendRequestToPeers(ctx context.Context, opsType opsType, payload []byte) (string, <-chan bool) {
-	requestID := common.GenerateUUID()
-	responseChan := make(chan bool)
-	responseTrackers[requestID] = responseChan
-	raftServer.cm.Submit(requestID, opsType, payload)
-	return requestID, responseChan
-}
-
-func handleCommit(ctx context.Context, commitChan <-chan CommitEntry) {
-	for commitEntry := range commitChan {
-		log.Infof("handleCommit(%s) got %+v", nodeName, commitEntry)
-		var noSQLRPCServer noSQLServerHandlers
-		if responseTracker, ok := responseTrackers[commitEntry.ID]; ok {
-			log.Infof("handleCommit(%s) found a tracker", nodeName)
-			responseTracker <- true
-			delete(responseTrackers, commitEntry.ID)
-			continue
-		}
-		log.Infof("handleCommit(%s) did not find a tracker", nodeName)
-		switch commitEntry.Command {
-		case CreateProduct:
-			msg := &libProto.CreateProductRequest{}
-			if err := proto.Unmarshal(commitEntry.Payload, msg); err != nil {
-				err = fmt.Errorf("exception while Unmarshalling %s Msg: %v", opsTypeToStr[commitEntry.Command], err)
-				log.Infof("handleCommit(%s): exception committing %s request. %v", nodeName, commitEntry.ID, err)
-				continue
-			}
-			if _, err := noSQLRPCServer.CreateProduct(ctx, msg); err != nil {
-				err = fmt.Errorf("exception while invoking %s operation: %v", opsTypeToStr[commitEntry.Command], err)
-				log.Infof("handleCommit(%s): exception committing %s request. %v", nodeName, commitEntry.ID, err)
-				continue
-			}
-		case UpdateProductByID:
-			msg := &libProto.UpdateProductByIDRequest{}
-			if err := proto.Unmarshal(commitEntry.Payload, msg); err != nil {
-				err = fmt.Errorf("exception while Unmarshalling %s Msg: %v", opsTypeToStr[commitEntry.Command], err)
-				log.Infof("handleCommit(%s): exception committing %s request. %v", nodeName, commitEntry.ID, err)
-				continue
-			}
-			if _, err := noSQLRPCServer.UpdateProductByID(ctx, msg); err != nil {
-				err = fmt.Errorf("exception while invoking %s operation: %v", opsTypeToStr[commitEntry.Command], err)
-				log.Infof("handleCommit(%s): exception committing %s request. %v", nodeName, commitEntry.ID, err)
-				continue
-			}
-		case DeleteProductByID:
-			msg := &libProto.DeleteProductByIDRequest{}
-			if err := proto.Unmarshal(commitEntry.Payload, msg); err != nil {
-				err = fmt.Errorf("exception while Unmarshalling %s Msg: %v", opsTypeToStr[commitEntry.Command], err)
-				log.Infof("handleCommit(%s): exception committing %s request. %v", nodeName, commitEntry.ID, err)
-				continue
-			}
-			if _, err := noSQLRPCServer.DeleteProductByID(ctx, msg); err != nil {
-				err = fmt.Errorf("exception while invoking %s operation: %v", opsTypeToStr[commitEntry.Command], err)
-				log.Infof("handleCommit(%s): exception committing %s request. %v", nodeName, commitEntry.ID, err)
-				continue
-			}
-		default:
-			log.Infof("handleCommit(%s): unknown OPSType: %d", nodeName, commitEntry.Command)
-			continue
-		}
-	}
-}
-
-func getNodeName(id int) string {
-	return fmt.Sprintf("%s%d", ProductDBNodeNameBase, id)
-}
-
-func initRaftServer(ctx context.Context, id string, peerNodeNames, peerNodePorts []string) {
-	storage := NewMapStorage()
-	commitChans := make(chan CommitEntry)
-	ready := make(chan interface{})
-	raftServer = NewServer(id, peerNodeNames, storage, ready, commitChans)
-	raftServer.Serve()
-	connected := map[string]bool{}
-	// Connect all peers to each other.
-	for j := 0; j < Connect_retry_count; j++ {
-		for i := 0; i < len(peerNodeNames); i++ {
-			if getNodeName(i+1) != id && !connected[getNodeName(i+1)] {
-				addr := net.JoinHostPort(peerNodeNames[i], peerNodePorts[i])
-				if err := raftServer.ConnectToPeer(getNodeName(i+1), "tcp", addr); err != nil {
-					log.Errorf("initRaftServer(%s): exception while connecting to %s. %v", id, addr, err)
-				} else {
-					connected[getNodeName(i+1)] = true
-					log.Infof("initRaftServer(%s): connected successfully to %s", id, addr)
-					break
-				}
-			}
-		}
-		if len(connected) == (len(peerNodeNames) - 1) {
-			log.Infof("initRaftServer(%s): connected successfully to all peers!", id)
-			break
-		}
-		log.Warnf("initRaftServer(%s): not connedted to all peers. Retry no. %d out of %d", id, j+1, Connect_retry_count)
-		time.Sleep(Connect_retry_cooloff_seconds * time.Second)
-	}
-	close(ready)
-	go handleCommit(ctx, commitChans)
-}
-
-func min(a, b int) int {
-	if a < b {
-		return a
-	}
-	return b
-}
@@ -0,0 +1,262 @@
+// Replication of the product table through a replication.Engine: the Raft
+// Consensus Module in library/raft by default, or the rotating sequencer in
+// library/totem. Product writes are proposed to the engine and executed
+// through commands once the engine has ordered them.
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/gob"
+	"errors"
+	"fmt"
+	"net"
+	"net/http"
+	"strconv"
+	"time"
+
+	"github.com/adarshsrinivasan/DS_S24/library/common"
+	"github.com/adarshsrinivasan/DS_S24/library/peerauth"
+	libProto "github.com/adarshsrinivasan/DS_S24/library/proto"
+	"github.com/adarshsrinivasan/DS_S24/library/raft"
+	"github.com/adarshsrinivasan/DS_S24/library/replication"
+	"github.com/adarshsrinivasan/DS_S24/library/totem"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+// Product commands. Their values are stored in the logs of the engines, so
+// they must not change.
+const (
+	CreateProduct replication.Op = iota
+	UpdateProductByID
+	DeleteProductByID
+)
+
+// commands maps every write of NOSQLService to its op and to the handler
+// executing it. The writes are served by its interceptor (see main) and
+// executed by commands.Apply once the engine has ordered them.
+var commands = replication.NewRegistry(statusCodeFromError)
+
+func init() {
+	handler := &noSQLServerHandlers{}
+	replication.Register(commands, CreateProduct, applyOnce(handler.CreateProduct))
+	replication.Register(commands, UpdateProductByID, applyOnce(handler.UpdateProductByID))
+	replication.Register(commands, DeleteProductByID, applyOnce(handler.DeleteProductByID))
+}
+
+// clientWrite is a write request that identifies the client sending it.
+type clientWrite interface {
+	proto.Message
+	GetClientRequestInfo() *libProto.ClientRequestInfo
+}
+
+// applyOnce wraps apply so that a write that was retried by its client is
+// applied only once; the response of the first application is returned for
+// the retries.
+func applyOnce[Req clientWrite, Resp proto.Message](apply func(ctx context.Context, request Req) (Resp, error)) func(ctx context.Context, request Req) (Resp, error) {
+	return func(ctx context.Context, request Req) (Resp, error) {
+		var cached Resp
+		cached = cached.ProtoReflect().New().Interface().(Resp)
+		response, err := sessions.applyOnce(request.GetClientRequestInfo(), cached, func() (proto.Message, error) {
+			return apply(ctx, request)
+		})
+		typed, _ := response.(Resp)
+		return typed, err
+	}
+}
+
+// waitForConsistentRead blocks until the local product table may serve a read
+// with the requested consistency level. On the sequencer, where there is no
+// leader, leader reads are linearizable.
+func waitForConsistentRead(ctx context.Context, consistency libProto.CONSISTENCY) (int, error) {
+	switch {
+	case consistency == libProto.CONSISTENCY_STALE:
+		return http.StatusOK, nil
+	case consistency == libProto.CONSISTENCY_LEADER && raftEngine != nil:
+		if _, _, isLeader := raftEngine.Server().CM().Report(); !isLeader {
+			err := notLeaderError()
+			log.Errorf("waitForConsistentRead: %v\n", err)
+			return http.StatusServiceUnavailable, err
+		}
+		return http.StatusOK, nil
+	}
+
+	if err := replicationEngine.Barrier(ctx); err != nil {
+		log.Errorf("waitForConsistentRead: %v\n", err)
+		return http.StatusServiceUnavailable, err
+	}
+	return http.StatusOK, nil
+}
+
+// errRaftOnly is returned by the membership APIs, which need Raft, when this
+// node runs on the sequencer.
+var errRaftOnly = status.Errorf(codes.FailedPrecondition, "only supported with %s=%s", common.ReplicationEngineEnv, replication.EngineRaft)
+
+// proposeConfigChange hands a membership change to the CM through propose and
+// waits for it to commit.
+func proposeConfigChange(ctx context.Context, propose func(requestID string) error) (int, error) {
+	if raftEngine == nil {
+		return http.StatusBadRequest, errRaftOnly
+	}
+	if err := raftEngine.ProposeConfigChange(ctx, propose); err != nil {
+		if errors.Is(err, raft.ErrNotLeader) {
+			return http.StatusServiceUnavailable, err
+		}
+		if status.Code(err) == codes.Unknown {
+			return http.StatusBadRequest, err
+		}
+		return statusCodeFromError(err), err
+	}
+	return http.StatusOK, nil
+}
+
+// promotionRetryInterval is how often promoteWhenCaughtUp retries promoting a
+// learner that hasn't caught up yet.
+const promotionRetryInterval = 200 * time.Millisecond
+
+// promoteWhenCaughtUp makes the learner memberID a voter, retrying until it
+// has caught up with the leader's log or ctx is done.
+func promoteWhenCaughtUp(ctx context.Context, memberID string) (int, error) {
+	ticker := time.NewTicker(promotionRetryInterval)
+	defer ticker.Stop()
+	for {
+		statusCode, err := proposeConfigChange(ctx, func(requestID string) error {
+			return raftEngine.Server().CM().PromoteMember(requestID, memberID)
+		})
+		if !errors.Is(err, raft.ErrNotCaughtUp) {
+			return statusCode, err
+		}
+		select {
+		case <-ctx.Done():
+			err = fmt.Errorf("exception while promoting %s. %v", memberID, ctx.Err())
+			log.Errorf("promoteWhenCaughtUp: %v\n", err)
+			return http.StatusServiceUnavailable, err
+		case <-ticker.C:
+		}
+	}
+}
+
+// productSnapshot is the state machine image handed to the engine: the full
+// product table and client sessions as of the snapshot.
+type productSnapshot struct {
+	Products []ProductTableModel
+	Sessions clientSessionsSnapshot
+}
+
+func takeProductSnapshot(ctx context.Context) ([]byte, error) {
+	products, _, err := ListAllProducts(ctx)
+	if err != nil {
+		err = fmt.Errorf("exception while listing products. %v", err)
+		log.Errorf("takeProductSnapshot: %v\n", err)
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(productSnapshot{Products: products, Sessions: sessions.snapshot()}); err != nil {
+		err = fmt.Errorf("exception while encoding snapshot. %v", err)
+		log.Errorf("takeProductSnapshot: %v\n", err)
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func restoreProductSnapshot(ctx context.Context, data []byte) error {
+	var snapshot productSnapshot
+	if err := gob.NewDecoder(bytes.NewBuffer(data)).Decode(&snapshot); err != nil {
+		err = fmt.Errorf("exception while decoding snapshot. %v", err)
+		log.Errorf("restoreProductSnapshot: %v\n", err)
+		return err
+	}
+	if err := RestoreProductTable(ctx, snapshot.Products); err != nil {
+		err = fmt.Errorf("exception while restoring product table. %v", err)
+		log.Errorf("restoreProductSnapshot: %v\n", err)
+		return err
+	}
+	sessions.restore(snapshot.Sessions)
+	return nil
+}
+
+// bootstrapConfiguration builds the initial configuration from the peer node
+// names and ports this node was started with. Every peer is assumed to serve
+// NOSQLService on the same port as this node.
+func bootstrapConfiguration(peerNodeNames, peerNodePorts []string) raft.Configuration {
+	var config raft.Configuration
+	for i := 0; i < len(peerNodeNames); i++ {
+		config.Members = append(config.Members, raft.Member{
+			ID:         peerNodeNames[i],
+			RaftAddr:   net.JoinHostPort(peerNodeNames[i], peerNodePorts[i]),
+			ClientAddr: net.JoinHostPort(peerNodeNames[i], strconv.Itoa(serverPort)),
+		})
+	}
+	return config
+}
+
+func newRaftEngine(ctx context.Context, id string, peerNodeNames, peerNodePorts []string, keyring *peerauth.Keyring) *raft.Engine {
+	config := bootstrapConfiguration(peerNodeNames, peerNodePorts)
+	if raftJoin {
+		// We're joining a running cluster; the leader brings us up to date,
+		// including the configuration, once we're added with AddNode.
+		log.Infof("newRaftEngine(%s): joining an existing cluster. Waiting to be added.", id)
+		config = raft.Configuration{}
+	}
+	peers := map[string]string{}
+	for i := 0; i < len(peerNodeNames); i++ {
+		peers[peerNodeNames[i]] = net.JoinHostPort(peerNodeNames[i], peerNodePorts[i])
+	}
+	return raft.NewEngine(raft.EngineConfig{
+		ID:                id,
+		ListenAddr:        fmt.Sprintf("%s:%d", syncHost, syncPort),
+		Bootstrap:         config,
+		Peers:             peers,
+		StorageDir:        raftStorageDir,
+		SnapshotThreshold: raftSnapshotThreshold,
+		Snapshot:          takeProductSnapshot,
+		Restore:           restoreProductSnapshot,
+		Reset:             ResetProductTable,
+		Keyring:           keyring,
+	})
+}
+
+// newSequencerEngine returns the sequencer of this node. The delivery log
+// isn't kept on disk: a restarted node catches up from its peers, through a
+// state transfer once they no longer keep the messages it missed.
+func newSequencerEngine(ctx context.Context, id string, peerNodeNames, peerNodePorts []string, keyring *peerauth.Keyring) *totem.Engine {
+	return totem.NewEngine(totem.Config{
+		NodeName:      id,
+		PeerNodeNames: peerNodeNames,
+		PeerNodePorts: peerNodePorts,
+		ListenAddr:    fmt.Sprintf("%s:%d", syncHost, syncPort),
+		FetchSnapshot: installProductSnapshotFromPeer,
+		Keyring:       keyring,
+	})
+}
+
+// initReplicationEngine starts the engine selected with REPLICATION_ENGINE.
+func initReplicationEngine(ctx context.Context, id string, peerNodeNames, peerNodePorts []string) {
+	if err := replication.ValidateEngineName(replicationEngineName); err != nil {
+		log.Fatalf("initReplicationEngine(%s): %v", id, err)
+	}
+	log.Infof("initReplicationEngine(%s): replicating the product table with the %s engine", id, replicationEngineName)
+	keyring, err := peerauth.KeyringFromEnv()
+	if err != nil {
+		log.Fatalf("initReplicationEngine(%s): %v", id, err)
+	}
+	if keyring == nil {
+		log.Warnf("initReplicationEngine(%s): %s is not set. Peer traffic is not authenticated.", id, common.PeerAuthKeysEnv)
+	} else {
+		log.Infof("initReplicationEngine(%s): authenticating peer traffic with keys %v", id, keyring.KeyIDs())
+	}
+	switch replicationEngineName {
+	case replication.EngineRaft:
+		raftEngine = newRaftEngine(ctx, id, peerNodeNames, peerNodePorts, keyring)
+		replicationEngine = raftEngine
+	case replication.EngineSequencer:
+		sequencerEngine = newSequencerEngine(ctx, id, peerNodeNames, peerNodePorts, keyring)
+		replicationEngine = sequencerEngine
+	}
+	if err := replicationEngine.Start(ctx, commands.Apply); err != nil {
+		log.Fatalf("initReplicationEngine(%s): exception while starting %s engine. %v", id, replicationEngineName, err)
+	}
+}
@@ -0,0 +1,85 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"net/http"
+
+	"github.com/adarshsrinivasan/DS_S24/library/common"
+	libProto "github.com/adarshsrinivasan/DS_S24/library/proto"
+	"github.com/adarshsrinivasan/DS_S24/library/totem"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// stateTransferMaxMessageSize is the largest snapshot a node on the sequencer
+// receives from a peer.
+const stateTransferMaxMessageSize = 256 << 20
+
+// StateTransfer sends a snapshot of the product table and client sessions,
+// taken between two deliveries, to a peer on the sequencer that fell too far
+// behind. The product table is read on the event loop of the sequencer, so
+// nothing is delivered meanwhile.
+func (server *noSQLServer) StateTransfer(ctx context.Context, request *libProto.StateTransferRequest) (*libProto.ProductStateTransferResponse, error) {
+	if sequencerEngine == nil {
+		err := status.Errorf(codes.FailedPrecondition, "state transfer is only supported on the sequencer")
+		log.Errorf("StateTransfer: %v\n", err)
+		return &libProto.ProductStateTransferResponse{
+			StatusCode: int32(http.StatusBadRequest),
+			Err:        common.ConvertErrorToProtoError(err),
+		}, err
+	}
+	log.Infof("StateTransfer(%s): Sending snapshot to %s\n", nodeName, request.RequestNodeName)
+	var data []byte
+	snapshot, err := sequencerEngine.AtDeliveredSeqNum(ctx, func() error {
+		var err error
+		data, err = takeProductSnapshot(ctx)
+		return err
+	})
+	if err == nil && snapshot.GlobalSeqNum < request.MinGlobalSeqNum {
+		err = fmt.Errorf("snapshot at sequence msg %d is older than %d", snapshot.GlobalSeqNum, request.MinGlobalSeqNum)
+	}
+	if err != nil {
+		err = fmt.Errorf("exception while taking snapshot for %s. %v", request.RequestNodeName, err)
+		log.Errorf("StateTransfer: %v\n", err)
+		return &libProto.ProductStateTransferResponse{
+			StatusCode: int32(http.StatusServiceUnavailable),
+			Err:        common.ConvertErrorToProtoError(err),
+		}, err
+	}
+	return &libProto.ProductStateTransferResponse{
+		StatusCode:        int32(http.StatusOK),
+		GlobalSeqNum:      snapshot.GlobalSeqNum,
+		RequestWatermarks: totem.ConvertRequestWatermarksToProto(snapshot.DeliveredRequests),
+		Snapshot:          data,
+	}, nil
+}
+
+// installProductSnapshotFromPeer replaces the product table and client
+// sessions with a snapshot from peerNodeName. It's the totem.SnapshotFetcher
+// of this service.
+func installProductSnapshotFromPeer(ctx context.Context, peerNodeName string, minGlobalSeqNum int32) (totem.SnapshotPoint, error) {
+	rpcClient, conn, err := common.NewNOSQLRPCClient(ctx, peerNodeName, serverPort)
+	if err != nil {
+		return totem.SnapshotPoint{}, err
+	}
+	defer conn.Close()
+
+	response, err := rpcClient.StateTransfer(ctx, &libProto.StateTransferRequest{RequestNodeName: nodeName, MinGlobalSeqNum: minGlobalSeqNum}, grpc.MaxCallRecvMsgSize(stateTransferMaxMessageSize))
+	if err != nil {
+		return totem.SnapshotPoint{}, fmt.Errorf("exception while requesting snapshot. %v", err)
+	}
+	if response.GlobalSeqNum < minGlobalSeqNum {
+		return totem.SnapshotPoint{}, fmt.Errorf("snapshot at sequence msg %d is older than %d", response.GlobalSeqNum, minGlobalSeqNum)
+	}
+	if err := restoreProductSnapshot(ctx, response.Snapshot); err != nil {
+		return totem.SnapshotPoint{}, err
+	}
+	log.Infof("installProductSnapshotFromPeer(%s): Received %d bytes at sequence msg %d from %s\n", nodeName, len(response.Snapshot), response.GlobalSeqNum, peerNodeName)
+	return totem.SnapshotPoint{
+		GlobalSeqNum:      response.GlobalSeqNum,
+		DeliveredRequests: totem.ConvertProtoRequestWatermarks(response.RequestWatermarks),
+	}, nil
+}
@@ -2,28 +2,22 @@ package main
 
 import (
 	"context"
-	"github.com/golang/protobuf/proto"
+	"net/http"
 
 	"github.com/adarshsrinivasan/DS_S24/library/common"
 	libProto "github.com/adarshsrinivasan/DS_S24/library/proto"
-	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
+// sqlServer serves the reads of SQLService from the local database. Its
+// writes are replicated by the interceptor of commands instead (see
+// replication.go).
 type sqlServer struct {
 	libProto.UnimplementedSQLServiceServer
 }
 
-func (server *sqlServer) CreateBuyer(ctx context.Context, request *libProto.CreateBuyerRequest) (*libProto.CreateBuyerResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := CreateBuyer
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := sqlServerHandlers{}
-	return handler.CreateBuyer(ctx, request)
-}
 func (server *sqlServer) GetBuyerByID(ctx context.Context, request *libProto.GetBuyerByIDRequest) (*libProto.GetBuyerByIDResponse, error) {
 	handler := sqlServerHandlers{}
 	return handler.GetBuyerByID(ctx, request)
@@ -32,26 +26,6 @@ func (server *sqlServer) GetBuyerByUserName(ctx context.Context, request *libPro
 	handler := sqlServerHandlers{}
 	return handler.GetBuyerByUserName(ctx, request)
 }
-func (server *sqlServer) UpdateBuyerByID(ctx context.Context, request *libProto.UpdateBuyerByIDRequest) (*libProto.UpdateBuyerByIDResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := UpdateBuyerByID
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := sqlServerHandlers{}
-	return handler.UpdateBuyerByID(ctx, request)
-}
-func (server *sqlServer) CreateCart(ctx context.Context, request *libProto.CreateCartRequest) (*libProto.CreateCartResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := CreateCart
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := sqlServerHandlers{}
-	return handler.CreateCart(ctx, request)
-}
 func (server *sqlServer) GetCartByID(ctx context.Context, request *libProto.GetCartByIDRequest) (*libProto.GetCartByIDResponse, error) {
 	handler := sqlServerHandlers{}
 	return handler.GetCartByID(ctx, request)
@@ -60,36 +34,6 @@ func (server *sqlServer) GetCartByBuyerID(ctx context.Context, request *libProto
 	handler := sqlServerHandlers{}
 	return handler.GetCartByBuyerID(ctx, request)
 }
-func (server *sqlServer) UpdateCartByID(ctx context.Context, request *libProto.UpdateCartByIDRequest) (*libProto.UpdateCartByIDResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := UpdateCartByID
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := sqlServerHandlers{}
-	return handler.UpdateCartByID(ctx, request)
-}
-func (server *sqlServer) DeleteCartByID(ctx context.Context, request *libProto.DeleteCartByIDRequest) (*libProto.DeleteCartByIDResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := DeleteCartByID
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := sqlServerHandlers{}
-	return handler.DeleteCartByID(ctx, request)
-}
-func (server *sqlServer) CreateCartItem(ctx context.Context, request *libProto.CreateCartItemRequest) (*libProto.CreateCartItemResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := CreateCartItem
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := sqlServerHandlers{}
-	return handler.CreateCartItem(ctx, request)
-}
 func (server *sqlServer) GetCartItemByID(ctx context.Context, request *libProto.GetCartItemByIDRequest) (*libProto.GetCartItemByIDResponse, error) {
 	handler := sqlServerHandlers{}
 	return handler.GetCartItemByID(ctx, request)
@@ -102,56 +46,6 @@ func (server *sqlServer) ListCartItemByCartID(ctx context.Context, request *libP
 	handler := sqlServerHandlers{}
 	return handler.ListCartItemByCartID(ctx, request)
 }
-func (server *sqlServer) UpdateCartItem(ctx context.Context, request *libProto.UpdateCartItemRequest) (*libProto.UpdateCartItemResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := UpdateCartItem
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := sqlServerHandlers{}
-	return handler.UpdateCartItem(ctx, request)
-}
-func (server *sqlServer) DeleteCartItemByCartIDAndProductID(ctx context.Context, request *libProto.DeleteCartItemByCartIDAndProductIDRequest) (*libProto.DeleteCartItemByCartIDAndProductIDResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := DeleteCartItemByCartIDAndProductID
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := sqlServerHandlers{}
-	return handler.DeleteCartItemByCartIDAndProductID(ctx, request)
-}
-func (server *sqlServer) DeleteCartItemByCartID(ctx context.Context, request *libProto.DeleteCartItemByCartIDRequest) (*libProto.DeleteCartItemByCartIDResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := DeleteCartItemByCartID
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := sqlServerHandlers{}
-	return handler.DeleteCartItemByCartID(ctx, request)
-}
-func (server *sqlServer) DeleteCartItemByProductID(ctx context.Context, request *libProto.DeleteCartItemByProductIDRequest) (*libProto.DeleteCartItemByProductIDResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := DeleteCartItemByProductID
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := sqlServerHandlers{}
-	return handler.DeleteCartItemByProductID(ctx, request)
-}
-func (server *sqlServer) CreateSeller(ctx context.Context, request *libProto.CreateSellerRequest) (*libProto.CreateSellerResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := CreateSeller
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := sqlServerHandlers{}
-	return handler.CreateSeller(ctx, request)
-}
 func (server *sqlServer) GetSellerByID(ctx context.Context, request *libProto.GetSellerByIDRequest) (*libProto.GetSellerByIDResponse, error) {
 	handler := sqlServerHandlers{}
 	return handler.GetSellerByID(ctx, request)
@@ -160,26 +54,6 @@ func (server *sqlServer) GetSellerByUserName(ctx context.Context, request *libPr
 	handler := sqlServerHandlers{}
 	return handler.GetSellerByUserName(ctx, request)
 }
-func (server *sqlServer) UpdateSellerByID(ctx context.Context, request *libProto.UpdateSellerByIDRequest) (*libProto.UpdateSellerByIDResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := UpdateSellerByID
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := sqlServerHandlers{}
-	return handler.UpdateSellerByID(ctx, request)
-}
-func (server *sqlServer) CreateSession(ctx context.Context, request *libProto.CreateSessionRequest) (*libProto.CreateSessionResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := CreateSession
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := sqlServerHandlers{}
-	return handler.CreateSession(ctx, request)
-}
 func (server *sqlServer) GetSessionByID(ctx context.Context, request *libProto.GetSessionByIDRequest) (*libProto.GetSessionByIDResponse, error) {
 	handler := sqlServerHandlers{}
 	return handler.GetSessionByID(ctx, request)
@@ -188,26 +62,6 @@ func (server *sqlServer) GetSessionByUserID(ctx context.Context, request *libPro
 	handler := sqlServerHandlers{}
 	return handler.GetSessionByUserID(ctx, request)
 }
-func (server *sqlServer) DeleteSessionByID(ctx context.Context, request *libProto.DeleteSessionByIDRequest) (*libProto.DeleteSessionByIDResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := DeleteSessionByID
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := sqlServerHandlers{}
-	return handler.DeleteSessionByID(ctx, request)
-}
-func (server *sqlServer) CreateTransaction(ctx context.Context, request *libProto.CreateTransactionRequest) (*libProto.CreateTransactionResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := CreateTransaction
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := sqlServerHandlers{}
-	return handler.CreateTransaction(ctx, request)
-}
 func (server *sqlServer) ListTransactionsBySellerID(ctx context.Context, request *libProto.ListTransactionsBySellerIDRequest) (*libProto.ListTransactionsBySellerIDResponse, error) {
 	handler := sqlServerHandlers{}
 	return handler.ListTransactionsBySellerID(ctx, request)
@@ -220,36 +74,6 @@ func (server *sqlServer) ListTransactionsByCartID(ctx context.Context, request *
 	handler := sqlServerHandlers{}
 	return handler.ListTransactionsByCartID(ctx, request)
 }
-func (server *sqlServer) DeleteTransactionsByCartID(ctx context.Context, request *libProto.DeleteTransactionsByCartIDRequest) (*libProto.DeleteTransactionsByCartIDResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := DeleteTransactionsByCartID
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := sqlServerHandlers{}
-	return handler.DeleteTransactionsByCartID(ctx, request)
-}
-func (server *sqlServer) DeleteTransactionsByBuyerID(ctx context.Context, request *libProto.DeleteTransactionsByBuyerIDRequest) (*libProto.DeleteTransactionsByBuyerIDResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := DeleteTransactionsByBuyerID
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := sqlServerHandlers{}
-	return handler.DeleteTransactionsByBuyerID(ctx, request)
-}
-func (server *sqlServer) DeleteTransactionsBySellerID(ctx context.Context, request *libProto.DeleteTransactionsBySellerIDRequest) (*libProto.DeleteTransactionsBySellerIDResponse, error) {
-	payload, _ := proto.Marshal(request)
-	opsType := DeleteTransactionsBySellerID
-	requestID, respChan := sendRequestToPeers(ctx, opsType, payload)
-	log.Infof("%s: Waiting. for requestID: %s to complete.\n", opsTypeToStr[opsType], requestID)
-	<-respChan
-	log.Infof("%s: requestID: %s completed!\n", opsTypeToStr[opsType], requestID)
-	handler := sqlServerHandlers{}
-	return handler.DeleteTransactionsBySellerID(ctx, request)
-}
 
 type sqlServerHandlers struct {
 }
@@ -742,3 +566,19 @@ func convertProtoTransactionModelToTransactionTableModel(ctx context.Context, pr
 		UpdatedAt: protoTransactionModel.UpdatedAt.AsTime(),
 	}
 }
+
+// statusCodeFromError maps the error a replicated write failed with to an
+// HTTP status code.
+func statusCodeFromError(err error) int {
+	if _, ok := common.GetNotLeaderDetails(err); ok {
+		return http.StatusServiceUnavailable
+	}
+	switch status.Code(err) {
+	case codes.Unavailable:
+		return http.StatusServiceUnavailable
+	case codes.DeadlineExceeded:
+		return http.StatusGatewayTimeout
+	default:
+		return http.StatusInternalServerError
+	}
+}
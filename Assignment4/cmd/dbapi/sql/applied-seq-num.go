@@ -0,0 +1,113 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+
+	"github.com/adarshsrinivasan/DS_S24/library/db/sql"
+	"github.com/adarshsrinivasan/DS_S24/library/replication"
+	"github.com/adarshsrinivasan/DS_S24/library/totem"
+	log "github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+)
+
+const AppliedSeqNumTableName = "applied_seq_num_data"
+
+// AppliedSeqNumTableModel holds a single row: the sequence number of the last
+// message of the sequencer whose write is in the tables, written in the same
+// transaction as that write.
+type AppliedSeqNumTableModel struct {
+	schema.BaseModel `bun:"table:applied_seq_num_data,alias:applied_seq_num"`
+	ID               int   `json:"id" bson:"id" bun:"id,pk"`
+	GlobalSeqNum     int32 `json:"globalSeqNum" bson:"globalSeqNum" bun:"globalSeqNum,notnull"`
+}
+
+func CreateAppliedSeqNumTable(ctx context.Context) error {
+	client, err := sql.NewSQLClient(ctx, serviceName, schemaName)
+	if err != nil {
+		err = fmt.Errorf("exception while creating SQLDB client. %v", err)
+		log.Errorf("CreateAppliedSeqNumTable: %v\n", err)
+		return err
+	}
+	defer client.Close(ctx)
+
+	tableSchemaPtr := reflect.New(reflect.TypeOf(AppliedSeqNumTableModel{}))
+
+	if err := client.CreateTable(ctx, tableSchemaPtr.Interface(), AppliedSeqNumTableName, nil); err != nil {
+		err := fmt.Errorf("exception while creating table %s. %v", AppliedSeqNumTableName, err)
+		log.Errorf("CreateAppliedSeqNumTable: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// applyInTx is the ApplyFunc of the sequencer. It applies a write through
+// commands and records its sequence number in the same transaction, so that
+// after a crash the sequencer applies again exactly the writes the tables
+// are missing. A write that fails is rolled back to a savepoint, and its
+// sequence number recorded all the same: it failed on every replica. A
+// replica that can't commit the transaction stops, and applies the write
+// again on restart.
+func applyInTx(ctx context.Context, requestID string, op replication.Op, payload []byte) replication.Result {
+	globalSeqNum, ok := totem.GlobalSeqNumFromContext(ctx)
+	if !ok {
+		log.Fatalf("applyInTx(%s): Request %s was not delivered by the sequencer\n", nodeName, requestID)
+	}
+	client, err := sql.NewSQLClient(ctx, serviceName, schemaName)
+	if err != nil {
+		log.Fatalf("applyInTx(%s): exception while creating SQLDB client. %v\n", nodeName, err)
+	}
+	defer client.Close(ctx)
+
+	var result replication.Result
+	err = client.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if _, err := tx.ExecContext(ctx, "SAVEPOINT apply"); err != nil {
+			return fmt.Errorf("exception while creating savepoint. %v", err)
+		}
+		result = commands.Apply(sql.WithTx(ctx, tx), requestID, op, payload)
+		if result.Err != nil {
+			if _, err := tx.ExecContext(ctx, "ROLLBACK TO SAVEPOINT apply"); err != nil {
+				return fmt.Errorf("exception while rolling back to savepoint. %v", err)
+			}
+		}
+		return recordAppliedSeqNum(ctx, tx, globalSeqNum)
+	})
+	if err != nil {
+		log.Fatalf("applyInTx(%s): exception while applying request %s at sequence msg %d. %v\n", nodeName, requestID, globalSeqNum, err)
+	}
+	return result
+}
+
+// recordAppliedSeqNum records globalSeqNum as the last message applied.
+func recordAppliedSeqNum(ctx context.Context, tx bun.Tx, globalSeqNum int32) error {
+	applied := &AppliedSeqNumTableModel{ID: 1, GlobalSeqNum: globalSeqNum}
+	if _, err := tx.NewInsert().Model(applied).On("CONFLICT (id) DO UPDATE").
+		Set("? = EXCLUDED.?", bun.Ident("globalSeqNum"), bun.Ident("globalSeqNum")).
+		Exec(ctx); err != nil {
+		return fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Insert", AppliedSeqNumTableName, err)
+	}
+	return nil
+}
+
+// readAppliedSeqNum returns the sequence number of the last message applied,
+// or false if none was recorded. It's the totem.Config.AppliedSeqNum of this
+// service.
+func readAppliedSeqNum(ctx context.Context) (int32, bool, error) {
+	client, err := sql.NewSQLClient(ctx, serviceName, schemaName)
+	if err != nil {
+		return 0, false, fmt.Errorf("exception while creating SQLDB client. %v", err)
+	}
+	defer client.Close(ctx)
+
+	var applied []AppliedSeqNumTableModel
+	if _, err := client.Read(ctx, AppliedSeqNumTableName, nil, nil, nil, nil, nil, false, &applied); err != nil {
+		return 0, false, fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Read", AppliedSeqNumTableName, err)
+	}
+	if len(applied) == 0 {
+		return 0, false, nil
+	}
+	return applied[0].GlobalSeqNum, true, nil
+}
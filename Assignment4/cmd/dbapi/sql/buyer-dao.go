@@ -9,7 +9,6 @@ import (
 
 	"github.com/adarshsrinivasan/DS_S24/library/db"
 	"github.com/adarshsrinivasan/DS_S24/library/db/sql"
-	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/uptrace/bun/schema"
 )
@@ -54,10 +53,19 @@ func CreateBuyerTable(ctx context.Context) error {
 		return err
 	}
 
+	return seedAdminBuyer(ctx)
+}
+
+// seedAdminBuyer inserts the admin buyer, with the same ID and timestamps on
+// every replica, unless it exists already.
+func seedAdminBuyer(ctx context.Context) error {
 	buyer := BuyerTableModel{
-		Name:     "admin",
-		UserName: "admin",
-		Password: "admin",
+		Id:        seedID(BuyerTableName, "admin"),
+		Name:      "admin",
+		UserName:  "admin",
+		Password:  "admin",
+		CreatedAt: seedTime,
+		UpdatedAt: seedTime,
 	}
 
 	if statusCode, err := buyer.CreateBuyer(ctx); err != nil {
@@ -65,7 +73,7 @@ func CreateBuyerTable(ctx context.Context) error {
 		if statusCode == http.StatusBadRequest {
 			return nil
 		}
-		logrus.Errorf("CreateBuyerTable: %v. StatusCode: %v\n", statusCode, err)
+		logrus.Errorf("seedAdminBuyer: %v. StatusCode: %v\n", statusCode, err)
 		return err
 	}
 
@@ -81,10 +89,9 @@ func (buyer *BuyerTableModel) CreateBuyer(ctx context.Context) (int, error) {
 	}
 	defer client.Close(ctx)
 
-	buyer.Id = uuid.New().String()
+	// The ID and timestamps come with the write, so that every replica
+	// inserts the same row (see stampWriteInterceptor).
 	buyer.Version = 0
-	buyer.CreatedAt = time.Now()
-	buyer.UpdatedAt = time.Now()
 
 	if existingBuyer, _, _ := buyer.getByColumn(ctx, "userName", buyer.UserName); existingBuyer != nil && existingBuyer.UserName == buyer.UserName {
 		err := fmt.Errorf("exception while verifying Buyer data. userName alredy taken")
@@ -144,8 +151,7 @@ func (buyer *BuyerTableModel) UpdateBuyerByID(ctx context.Context) (int, error)
 	}
 	defer client.Close(ctx)
 
-	buyer.UpdatedAt = time.Now()
-
+	// UpdatedAt comes with the write (see stampWriteInterceptor).
 	if err := client.Update(ctx, buyer, BuyerTableName, true); err != nil {
 		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Update", BuyerTableName, err)
 		logrus.Errorf("UpdateBuyerByID: %v\n", err)
@@ -9,7 +9,6 @@ import (
 
 	"github.com/adarshsrinivasan/DS_S24/library/db"
 	"github.com/adarshsrinivasan/DS_S24/library/db/sql"
-	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/uptrace/bun/schema"
 )
@@ -76,10 +75,9 @@ func (cart *CartTableModel) CreateCart(ctx context.Context) (int, error) {
 	}
 	defer client.Close(ctx)
 
-	cart.ID = uuid.New().String()
+	// The ID and timestamps come with the write, so that every replica
+	// inserts the same row (see stampWriteInterceptor).
 	cart.Version = 0
-	cart.CreatedAt = time.Now()
-	cart.UpdatedAt = time.Now()
 
 	if err := client.Insert(ctx, cart, CartTableName); err != nil {
 		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Insert", BuyerTableName, err)
@@ -133,8 +131,7 @@ func (cart *CartTableModel) UpdateCartByID(ctx context.Context) (int, error) {
 	}
 	defer client.Close(ctx)
 
-	cart.UpdatedAt = time.Now()
-
+	// UpdatedAt comes with the write (see stampWriteInterceptor).
 	if err := client.Update(ctx, cart, CartTableName, true); err != nil {
 		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Update", CartTableName, err)
 		logrus.Errorf("UpdateCartByID: %v\n", err)
@@ -9,7 +9,6 @@ import (
 
 	"github.com/adarshsrinivasan/DS_S24/library/db"
 	"github.com/adarshsrinivasan/DS_S24/library/db/sql"
-	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/uptrace/bun/schema"
 )
@@ -80,10 +79,9 @@ func (cartItem *CartItemTableModel) CreateCartItem(ctx context.Context) (int, er
 	}
 	defer client.Close(ctx)
 
-	cartItem.ID = uuid.New().String()
+	// The ID and timestamps come with the write, so that every replica
+	// inserts the same row (see stampWriteInterceptor).
 	cartItem.Version = 0
-	cartItem.CreatedAt = time.Now()
-	cartItem.UpdatedAt = time.Now()
 
 	if err := client.Insert(ctx, cartItem, CartItemTableName); err != nil {
 		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Insert", CartItemTableName, err)
@@ -180,8 +178,7 @@ func (cartItem *CartItemTableModel) UpdateCartItem(ctx context.Context) (int, er
 	}
 	defer client.Close(ctx)
 
-	cartItem.UpdatedAt = time.Now()
-
+	// UpdatedAt comes with the write (see stampWriteInterceptor).
 	if err := client.Update(ctx, cartItem, CartItemTableName, true); err != nil {
 		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Update", CartItemTableName, err)
 		logrus.Errorf("UpdateCartItem: %v\n", err)
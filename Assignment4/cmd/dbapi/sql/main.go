@@ -10,6 +10,9 @@ import (
 
 	"github.com/adarshsrinivasan/DS_S24/library/common"
 	"github.com/adarshsrinivasan/DS_S24/library/db/sql"
+	"github.com/adarshsrinivasan/DS_S24/library/peerauth"
+	"github.com/adarshsrinivasan/DS_S24/library/replication"
+	"github.com/adarshsrinivasan/DS_S24/library/totem"
 	log "github.com/sirupsen/logrus"
 )
 
@@ -33,6 +36,20 @@ var (
 	peerNodePorts = common.SplitCSV(common.GetEnv(common.PeerNodePortsEnv, fmt.Sprintf("%d,%d,%d,%d,%d", syncPort, syncPort, syncPort, syncPort, syncPort)))
 	serviceName   string
 	schemaName    = common.GetEnv(SQLSchemaNameEnv, "marketplace")
+
+	totemStorageDir          = common.GetEnv(common.TotemStorageDirEnv, "")
+	raftStorageDir           = common.GetEnv(common.RaftStorageDirEnv, "")
+	raftSnapshotThreshold, _ = strconv.Atoi(common.GetEnv(common.RaftSnapshotThresholdEnv, "1000"))
+	replicationEngineName    = common.GetEnv(common.ReplicationEngineEnv, replication.EngineSequencer)
+	replicationEngine        replication.Engine
+
+	// sequencerEngine is set when the sequencer is in use, for state
+	// transfers.
+	sequencerEngine *totem.Engine
+
+	// peerKeyring authenticates the state transfers between the nodes. It's
+	// nil when PEER_AUTH_KEYS is not set.
+	peerKeyring *peerauth.Keyring
 )
 
 func initializeSQLDB(ctx context.Context, serviceName, schemaName string) error {
@@ -81,6 +98,16 @@ func initializeSQLDB(ctx context.Context, serviceName, schemaName string) error
 		log.Errorf("initializeSQLDB: %v\n", err)
 		return err
 	}
+	if err := CreateStateTransferTable(ctx); err != nil {
+		err = fmt.Errorf("exception while creating stateTransfer tabel. %v", err)
+		log.Errorf("initializeSQLDB: %v\n", err)
+		return err
+	}
+	if err := CreateAppliedSeqNumTable(ctx); err != nil {
+		err = fmt.Errorf("exception while creating appliedSeqNum tabel. %v", err)
+		log.Errorf("initializeSQLDB: %v\n", err)
+		return err
+	}
 	log.Infof("initializeSQLDB: Initialized SQLDB Successfully!\n")
 	return nil
 }
@@ -142,7 +169,7 @@ func main() {
 		log.Panicf("main: %v\n", err)
 	}
 
-	go listenFromPeers(ctx)
+	initReplicationEngine(ctx, nodeName, peerNodeNames, peerNodePorts)
 
 	log.Println("Server Listening ...")
 	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", serverHost, serverPort))
@@ -150,7 +177,12 @@ func main() {
 		log.Fatalf("failed to listen: %v", err)
 	}
 
-	server := grpc.NewServer()
+	server := grpc.NewServer(
+		grpc.ChainUnaryInterceptor(stampWriteInterceptor, commands.UnaryServerInterceptor(replicationEngine)),
+		// StateTransfer, the only stream, streams the whole database to
+		// another node.
+		grpc.StreamInterceptor(peerKeyring.StreamServerInterceptor()),
+	)
 	proto.RegisterSQLServiceServer(server, &sqlServer{})
 
 	if err := server.Serve(lis); err != nil {
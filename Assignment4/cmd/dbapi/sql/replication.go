@@ -0,0 +1,313 @@
+// Replication of the customer-db tables through a replication.Engine: the
+// rotating sequencer in library/totem by default, or the Raft Consensus
+// Module in library/raft. Writes are proposed to the engine and executed
+// through commands once the engine has ordered them.
+package main
+
+import (
+	"bytes"
+	"context"
+	stdsql "database/sql"
+	"encoding/gob"
+	"fmt"
+	"net"
+	"strconv"
+	"time"
+
+	"github.com/adarshsrinivasan/DS_S24/library/common"
+	"github.com/adarshsrinivasan/DS_S24/library/db/sql"
+	"github.com/adarshsrinivasan/DS_S24/library/peerauth"
+	libProto "github.com/adarshsrinivasan/DS_S24/library/proto"
+	"github.com/adarshsrinivasan/DS_S24/library/raft"
+	"github.com/adarshsrinivasan/DS_S24/library/replication"
+	"github.com/adarshsrinivasan/DS_S24/library/totem"
+	"github.com/google/uuid"
+	log "github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+	"google.golang.org/grpc"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/types/known/timestamppb"
+)
+
+// Write requests. Their values are stored in the logs of the engines, so they
+// must not change.
+const (
+	CreateBuyer replication.Op = iota
+	UpdateBuyerByID
+	CreateCart
+	UpdateCartByID
+	DeleteCartByID
+	CreateCartItem
+	UpdateCartItem
+	DeleteCartItemByCartIDAndProductID
+	DeleteCartItemByCartID
+	DeleteCartItemByProductID
+	CreateSeller
+	UpdateSellerByID
+	CreateSession
+	DeleteSessionByID
+	CreateTransaction
+	DeleteTransactionsByCartID
+	DeleteTransactionsByBuyerID
+	DeleteTransactionsBySellerID
+)
+
+// commands maps every write of SQLService to its op and to the handler
+// executing it. The writes are served by its interceptor (see
+// initReplicationEngine) and executed by commands.Apply once the engine has
+// ordered them, on the node that proposed them and on the others alike.
+var commands = replication.NewRegistry(statusCodeFromError)
+
+// seedTime stamps the admin rows every replica inserts on its own when it
+// creates the tables, so that they're the same everywhere.
+var seedTime = time.Date(2024, time.January, 1, 0, 0, 0, 0, time.UTC)
+
+// seedID returns the ID of the admin row of tableName, the same on every
+// replica.
+func seedID(tableName, userName string) string {
+	return uuid.NewSHA1(uuid.NameSpaceOID, []byte(tableName+"/"+userName)).String()
+}
+
+// stampWriteInterceptor runs before a write is replicated by the interceptor
+// of commands. It assigns the IDs and timestamps of the write once, on the
+// node that receives it, so that every replica applies the same values.
+func stampWriteInterceptor(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+	now := timestamppb.Now()
+	switch msg := req.(type) {
+	case *libProto.CreateBuyerRequest:
+		msg.RequestModel.ID = common.GenerateUUID()
+		msg.RequestModel.CreatedAt, msg.RequestModel.UpdatedAt = now, now
+	case *libProto.UpdateBuyerByIDRequest:
+		msg.RequestModel.UpdatedAt = now
+	case *libProto.CreateCartRequest:
+		msg.RequestModel.ID = common.GenerateUUID()
+		msg.RequestModel.CreatedAt, msg.RequestModel.UpdatedAt = now, now
+	case *libProto.UpdateCartByIDRequest:
+		msg.RequestModel.UpdatedAt = now
+	case *libProto.CreateCartItemRequest:
+		msg.RequestModel.ID = common.GenerateUUID()
+		msg.RequestModel.CreatedAt, msg.RequestModel.UpdatedAt = now, now
+	case *libProto.UpdateCartItemRequest:
+		msg.RequestModel.UpdatedAt = now
+	case *libProto.CreateSellerRequest:
+		msg.RequestModel.ID = common.GenerateUUID()
+		msg.RequestModel.CreatedAt, msg.RequestModel.UpdatedAt = now, now
+	case *libProto.UpdateSellerByIDRequest:
+		msg.RequestModel.UpdatedAt = now
+	case *libProto.CreateSessionRequest:
+		msg.RequestModel.ID = common.GenerateUUID()
+		msg.RequestModel.CreatedAt, msg.RequestModel.UpdatedAt = now, now
+	case *libProto.CreateTransactionRequest:
+		msg.RequestModel.ID = common.GenerateUUID()
+		msg.RequestModel.CreatedAt, msg.RequestModel.UpdatedAt = now, now
+	}
+	return handler(ctx, req)
+}
+
+func init() {
+	handler := &sqlServerHandlers{}
+	replication.Register(commands, CreateBuyer, handler.CreateBuyer)
+	replication.Register(commands, UpdateBuyerByID, handler.UpdateBuyerByID)
+	replication.Register(commands, CreateCart, handler.CreateCart)
+	replication.Register(commands, UpdateCartByID, handler.UpdateCartByID)
+	replication.Register(commands, DeleteCartByID, handler.DeleteCartByID)
+	replication.Register(commands, CreateCartItem, handler.CreateCartItem)
+	replication.Register(commands, UpdateCartItem, handler.UpdateCartItem)
+	replication.Register(commands, DeleteCartItemByCartIDAndProductID, handler.DeleteCartItemByCartIDAndProductID)
+	replication.Register(commands, DeleteCartItemByCartID, handler.DeleteCartItemByCartID)
+	replication.Register(commands, DeleteCartItemByProductID, handler.DeleteCartItemByProductID)
+	replication.Register(commands, CreateSeller, handler.CreateSeller)
+	replication.Register(commands, UpdateSellerByID, handler.UpdateSellerByID)
+	replication.Register(commands, CreateSession, handler.CreateSession)
+	replication.Register(commands, DeleteSessionByID, handler.DeleteSessionByID)
+	replication.Register(commands, CreateTransaction, handler.CreateTransaction)
+	replication.Register(commands, DeleteTransactionsByCartID, handler.DeleteTransactionsByCartID)
+	replication.Register(commands, DeleteTransactionsByBuyerID, handler.DeleteTransactionsByBuyerID)
+	replication.Register(commands, DeleteTransactionsBySellerID, handler.DeleteTransactionsBySellerID)
+}
+
+// takeSnapshot returns an image of the tables for the Raft engine: the
+// marshalled chunks a state transfer would send, read in a single repeatable
+// read transaction.
+func takeSnapshot(ctx context.Context) ([]byte, error) {
+	client, err := sql.NewSQLClient(ctx, serviceName, schemaName)
+	if err != nil {
+		err = fmt.Errorf("exception while creating SQLDB client. %v", err)
+		log.Errorf("takeSnapshot: %v\n", err)
+		return nil, err
+	}
+	defer client.Close(ctx)
+
+	var chunks [][]byte
+	txOptions := &stdsql.TxOptions{Isolation: stdsql.LevelRepeatableRead, ReadOnly: true}
+	err = client.RunInTx(ctx, txOptions, func(ctx context.Context, tx bun.Tx) error {
+		return sendSnapshotTables(ctx, tx, func(chunk *libProto.StateTransferChunk) error {
+			data, err := proto.Marshal(chunk)
+			if err != nil {
+				return fmt.Errorf("exception while marshalling chunk. %v", err)
+			}
+			chunks = append(chunks, data)
+			return nil
+		})
+	})
+	if err != nil {
+		err = fmt.Errorf("exception while reading tables. %v", err)
+		log.Errorf("takeSnapshot: %v\n", err)
+		return nil, err
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(chunks); err != nil {
+		err = fmt.Errorf("exception while encoding snapshot. %v", err)
+		log.Errorf("takeSnapshot: %v\n", err)
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// restoreSnapshot replaces the tables with an image from takeSnapshot, in a
+// single transaction.
+func restoreSnapshot(ctx context.Context, data []byte) error {
+	var chunks [][]byte
+	if err := gob.NewDecoder(bytes.NewBuffer(data)).Decode(&chunks); err != nil {
+		err = fmt.Errorf("exception while decoding snapshot. %v", err)
+		log.Errorf("restoreSnapshot: %v\n", err)
+		return err
+	}
+	client, err := sql.NewSQLClient(ctx, serviceName, schemaName)
+	if err != nil {
+		err = fmt.Errorf("exception while creating SQLDB client. %v", err)
+		log.Errorf("restoreSnapshot: %v\n", err)
+		return err
+	}
+	defer client.Close(ctx)
+
+	err = client.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := truncateTables(ctx, tx); err != nil {
+			return err
+		}
+		for _, data := range chunks {
+			chunk := &libProto.StateTransferChunk{}
+			if err := proto.Unmarshal(data, chunk); err != nil {
+				return fmt.Errorf("exception while unmarshalling chunk. %v", err)
+			}
+			if _, err := insertSnapshotChunk(ctx, tx, chunk); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		err = fmt.Errorf("exception while restoring tables. %v", err)
+		log.Errorf("restoreSnapshot: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// resetTables empties the tables, for the Raft engine to replay its log into.
+// The admin rows are inserted on every replica at startup rather than through
+// the log, so they're inserted again.
+func resetTables(ctx context.Context) error {
+	client, err := sql.NewSQLClient(ctx, serviceName, schemaName)
+	if err != nil {
+		err = fmt.Errorf("exception while creating SQLDB client. %v", err)
+		log.Errorf("resetTables: %v\n", err)
+		return err
+	}
+	defer client.Close(ctx)
+
+	return client.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := truncateTables(ctx, tx); err != nil {
+			return err
+		}
+		ctx = sql.WithTx(ctx, tx)
+		if err := seedAdminSeller(ctx); err != nil {
+			return err
+		}
+		return seedAdminBuyer(ctx)
+	})
+}
+
+// bootstrapConfiguration builds the Raft configuration from the peer node
+// names and ports this node was started with. Every peer is assumed to serve
+// SQLService on the same port as this node.
+func bootstrapConfiguration(peerNodeNames, peerNodePorts []string) raft.Configuration {
+	var config raft.Configuration
+	for i := 0; i < len(peerNodeNames); i++ {
+		config.Members = append(config.Members, raft.Member{
+			ID:         peerNodeNames[i],
+			RaftAddr:   net.JoinHostPort(peerNodeNames[i], peerNodePorts[i]),
+			ClientAddr: net.JoinHostPort(peerNodeNames[i], strconv.Itoa(serverPort)),
+		})
+	}
+	return config
+}
+
+// newRaftEngine returns the Raft engine of this node. Clients talk to a fixed
+// node, so followers forward the writes they receive to the leader.
+func newRaftEngine(ctx context.Context, id string, peerNodeNames, peerNodePorts []string, keyring *peerauth.Keyring) *raft.Engine {
+	peers := map[string]string{}
+	for i := 0; i < len(peerNodeNames); i++ {
+		peers[peerNodeNames[i]] = net.JoinHostPort(peerNodeNames[i], peerNodePorts[i])
+	}
+	return raft.NewEngine(raft.EngineConfig{
+		ID:                id,
+		ListenAddr:        fmt.Sprintf("%s:%d", syncHost, syncPort),
+		Bootstrap:         bootstrapConfiguration(peerNodeNames, peerNodePorts),
+		Peers:             peers,
+		StorageDir:        raftStorageDir,
+		SnapshotThreshold: raftSnapshotThreshold,
+		Snapshot:          takeSnapshot,
+		Restore:           restoreSnapshot,
+		Reset:             resetTables,
+		Keyring:           keyring,
+	})
+}
+
+func newSequencerEngine(ctx context.Context, id string, peerNodeNames, peerNodePorts []string, keyring *peerauth.Keyring) *totem.Engine {
+	return totem.NewEngine(totem.Config{
+		NodeName:          id,
+		PeerNodeNames:     peerNodeNames,
+		PeerNodePorts:     peerNodePorts,
+		ListenAddr:        fmt.Sprintf("%s:%d", syncHost, syncPort),
+		StorageDir:        totemStorageDir,
+		FetchSnapshot:     installSnapshotFromPeer,
+		InstalledSnapshot: readInstalledSnapshot,
+		AppliedSeqNum:     readAppliedSeqNum,
+		Keyring:           keyring,
+	})
+}
+
+// initReplicationEngine starts the engine selected with REPLICATION_ENGINE.
+func initReplicationEngine(ctx context.Context, id string, peerNodeNames, peerNodePorts []string) {
+	if err := replication.ValidateEngineName(replicationEngineName); err != nil {
+		log.Fatalf("initReplicationEngine(%s): %v", id, err)
+	}
+	log.Infof("initReplicationEngine(%s): replicating the customer-db tables with the %s engine", id, replicationEngineName)
+	keyring, err := peerauth.KeyringFromEnv()
+	if err != nil {
+		log.Fatalf("initReplicationEngine(%s): %v", id, err)
+	}
+	if keyring == nil {
+		log.Warnf("initReplicationEngine(%s): %s is not set. Peer traffic is not authenticated.", id, common.PeerAuthKeysEnv)
+	} else {
+		log.Infof("initReplicationEngine(%s): authenticating peer traffic with keys %v", id, keyring.KeyIDs())
+	}
+	peerKeyring = keyring
+	switch replicationEngineName {
+	case replication.EngineRaft:
+		replicationEngine = newRaftEngine(ctx, id, peerNodeNames, peerNodePorts, keyring)
+	case replication.EngineSequencer:
+		sequencerEngine = newSequencerEngine(ctx, id, peerNodeNames, peerNodePorts, keyring)
+		replicationEngine = sequencerEngine
+	}
+	// The sequencer records the last write applied along with it; Raft
+	// replays its log onto a reset database instead.
+	apply := commands.Apply
+	if sequencerEngine != nil {
+		apply = applyInTx
+	}
+	if err := replicationEngine.Start(ctx, apply); err != nil {
+		log.Fatalf("initReplicationEngine(%s): exception while starting %s engine. %v", id, replicationEngineName, err)
+	}
+}
@@ -9,7 +9,6 @@ import (
 
 	"github.com/adarshsrinivasan/DS_S24/library/db"
 	"github.com/adarshsrinivasan/DS_S24/library/db/sql"
-	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/uptrace/bun/schema"
 )
@@ -57,13 +56,22 @@ func CreateSellerTable(ctx context.Context) error {
 		return err
 	}
 
+	return seedAdminSeller(ctx)
+}
+
+// seedAdminSeller inserts the admin seller, with the same ID and timestamps on
+// every replica, unless it exists already.
+func seedAdminSeller(ctx context.Context) error {
 	seller := SellerTableModel{
+		Id:                 seedID(SellerTableName, "admin"),
 		Name:               "admin",
 		FeedBackThumbsUp:   0,
 		FeedBackThumbsDown: 0,
 		NumberOfItemsSold:  0,
 		UserName:           "admin",
 		Password:           "admin",
+		CreatedAt:          seedTime,
+		UpdatedAt:          seedTime,
 	}
 
 	if statusCode, err := seller.CreateSeller(ctx); err != nil {
@@ -71,7 +79,7 @@ func CreateSellerTable(ctx context.Context) error {
 		if statusCode == http.StatusBadRequest {
 			return nil
 		}
-		logrus.Errorf("CreateSellerTable: %v. StatusCode: %v\n", statusCode, err)
+		logrus.Errorf("seedAdminSeller: %v. StatusCode: %v\n", statusCode, err)
 		return err
 	}
 
@@ -87,10 +95,9 @@ func (seller *SellerTableModel) CreateSeller(ctx context.Context) (int, error) {
 	}
 	defer client.Close(ctx)
 
-	seller.Id = uuid.New().String()
+	// The ID and timestamps come with the write, so that every replica
+	// inserts the same row (see stampWriteInterceptor).
 	seller.Version = 0
-	seller.CreatedAt = time.Now()
-	seller.UpdatedAt = time.Now()
 
 	if existingSeller, _, _ := seller.getByColumn(ctx, "userName", seller.UserName); existingSeller != nil && existingSeller.UserName == seller.UserName {
 		err := fmt.Errorf("exception while verifying Seller data. userName alredy taken")
@@ -149,8 +156,8 @@ func (seller *SellerTableModel) UpdateSellerByID(ctx context.Context) (int, erro
 		return http.StatusInternalServerError, err
 	}
 	defer client.Close(ctx)
-	seller.UpdatedAt = time.Now()
 
+	// UpdatedAt comes with the write (see stampWriteInterceptor).
 	if err := client.Update(ctx, seller, SellerTableName, true); err != nil {
 		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Update", SellerTableName, err)
 		logrus.Errorf("UpdateBuyerByID: %v\n", err)
@@ -10,7 +10,6 @@ import (
 	"github.com/adarshsrinivasan/DS_S24/library/common"
 	"github.com/adarshsrinivasan/DS_S24/library/db"
 	"github.com/adarshsrinivasan/DS_S24/library/db/sql"
-	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/uptrace/bun/schema"
 )
@@ -65,10 +64,9 @@ func (session *SessionTableModel) CreateSession(ctx context.Context) (int, error
 	}
 	defer client.Close(ctx)
 
-	session.ID = uuid.New().String()
+	// The ID and timestamps come with the write, so that every replica
+	// inserts the same row (see stampWriteInterceptor).
 	session.Version = 0
-	session.CreatedAt = time.Now()
-	session.UpdatedAt = time.Now()
 
 	if err := client.Insert(ctx, session, SessionTableName); err != nil {
 		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Insert", SessionTableName, err)
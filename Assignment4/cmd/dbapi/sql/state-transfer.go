@@ -0,0 +1,344 @@
+package main
+
+import (
+	"context"
+	stdsql "database/sql"
+	"fmt"
+	"io"
+	"reflect"
+
+	"github.com/adarshsrinivasan/DS_S24/library/common"
+	"github.com/adarshsrinivasan/DS_S24/library/db/sql"
+	libProto "github.com/adarshsrinivasan/DS_S24/library/proto"
+	"github.com/adarshsrinivasan/DS_S24/library/totem"
+	log "github.com/sirupsen/logrus"
+	"github.com/uptrace/bun"
+	"github.com/uptrace/bun/schema"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	StateTransferTableName = "state_transfer_data"
+
+	// stateTransferPageSize is the most rows of a table sent in one chunk.
+	stateTransferPageSize = 500
+)
+
+// StateTransferTableModel holds a single row: where the last snapshot
+// installed was taken, written in the same transaction as the snapshot.
+type StateTransferTableModel struct {
+	schema.BaseModel  `bun:"table:state_transfer_data,alias:state_transfer"`
+	ID                int                               `json:"id" bson:"id" bun:"id,pk"`
+	GlobalSeqNum      int32                             `json:"globalSeqNum" bson:"globalSeqNum" bun:"globalSeqNum,notnull"`
+	RequestWatermarks map[string]totem.RequestWatermark `json:"requestWatermarks" bson:"requestWatermarks" bun:"requestWatermarks,type:jsonb"`
+}
+
+func CreateStateTransferTable(ctx context.Context) error {
+	client, err := sql.NewSQLClient(ctx, serviceName, schemaName)
+	if err != nil {
+		err = fmt.Errorf("exception while creating SQLDB client. %v", err)
+		log.Errorf("CreateStateTransferTable: %v\n", err)
+		return err
+	}
+	defer client.Close(ctx)
+
+	tableSchemaPtr := reflect.New(reflect.TypeOf(StateTransferTableModel{}))
+
+	if err := client.CreateTable(ctx, tableSchemaPtr.Interface(), StateTransferTableName, nil); err != nil {
+		err := fmt.Errorf("exception while creating table %s. %v", StateTransferTableName, err)
+		log.Errorf("CreateStateTransferTable: %v\n", err)
+		return err
+	}
+
+	return nil
+}
+
+// StateTransfer streams a snapshot of the tables, taken between two
+// deliveries. The first chunk holds no rows but the last request of each
+// node the snapshot covers; every chunk carries the sequence number of the
+// snapshot.
+func (server *sqlServer) StateTransfer(request *libProto.StateTransferRequest, stream libProto.SQLService_StateTransferServer) error {
+	ctx := stream.Context()
+	if sequencerEngine == nil {
+		err := status.Errorf(codes.FailedPrecondition, "state transfer is only supported on the sequencer")
+		log.Errorf("StateTransfer: %v\n", err)
+		return err
+	}
+	log.Infof("StateTransfer(%s): Sending snapshot to %s\n", nodeName, request.RequestNodeName)
+	client, err := sql.NewSQLClient(ctx, serviceName, schemaName)
+	if err != nil {
+		err = fmt.Errorf("exception while creating SQLDB client. %v", err)
+		log.Errorf("StateTransfer: %v\n", err)
+		return err
+	}
+	defer client.Close(ctx)
+
+	// Postgres takes the snapshot of a repeatable read transaction at its
+	// first query, which runs on the event loop so it matches a sequence
+	// number. The tables are then read while delivery carries on.
+	txOptions := &stdsql.TxOptions{Isolation: stdsql.LevelRepeatableRead, ReadOnly: true}
+	err = client.RunInTx(ctx, txOptions, func(ctx context.Context, tx bun.Tx) error {
+		snapshot, err := sequencerEngine.AtDeliveredSeqNum(ctx, func() error {
+			_, err := tx.ExecContext(ctx, "SELECT 1")
+			return err
+		})
+		if err != nil {
+			return fmt.Errorf("exception while taking snapshot. %v", err)
+		}
+		globalSeqNum := snapshot.GlobalSeqNum
+		if globalSeqNum < request.MinGlobalSeqNum {
+			return fmt.Errorf("snapshot at sequence msg %d is older than %d", globalSeqNum, request.MinGlobalSeqNum)
+		}
+		header := &libProto.StateTransferChunk{
+			GlobalSeqNum:      globalSeqNum,
+			RequestWatermarks: totem.ConvertRequestWatermarksToProto(snapshot.DeliveredRequests),
+		}
+		if err := stream.Send(header); err != nil {
+			return err
+		}
+		return sendSnapshotTables(ctx, tx, func(chunk *libProto.StateTransferChunk) error {
+			chunk.GlobalSeqNum = globalSeqNum
+			return stream.Send(chunk)
+		})
+	})
+	if err != nil {
+		err = fmt.Errorf("exception while sending snapshot to %s. %v", request.RequestNodeName, err)
+		log.Errorf("StateTransfer: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// sendSnapshotTables sends the rows of every table, parents before children
+// so the receiver can insert them in order.
+func sendSnapshotTables(ctx context.Context, tx bun.Tx, send func(chunk *libProto.StateTransferChunk) error) error {
+	if err := readTablePages(ctx, tx, SellerTableName, func(rows []SellerTableModel) error {
+		chunk := &libProto.StateTransferChunk{}
+		for i := range rows {
+			chunk.Sellers = append(chunk.Sellers, convertSellerTableModelToProtoSellerModel(ctx, &rows[i]))
+		}
+		return send(chunk)
+	}); err != nil {
+		return err
+	}
+	if err := readTablePages(ctx, tx, BuyerTableName, func(rows []BuyerTableModel) error {
+		chunk := &libProto.StateTransferChunk{}
+		for i := range rows {
+			chunk.Buyers = append(chunk.Buyers, convertBuyerTableModelToProtoBuyerModel(ctx, &rows[i]))
+		}
+		return send(chunk)
+	}); err != nil {
+		return err
+	}
+	if err := readTablePages(ctx, tx, SessionTableName, func(rows []SessionTableModel) error {
+		chunk := &libProto.StateTransferChunk{}
+		for i := range rows {
+			chunk.Sessions = append(chunk.Sessions, convertSessionTableModelToProtoSessionModel(ctx, &rows[i]))
+		}
+		return send(chunk)
+	}); err != nil {
+		return err
+	}
+	if err := readTablePages(ctx, tx, CartTableName, func(rows []CartTableModel) error {
+		chunk := &libProto.StateTransferChunk{}
+		for i := range rows {
+			chunk.Carts = append(chunk.Carts, convertCartTableModelToProtoCartModel(ctx, &rows[i]))
+		}
+		return send(chunk)
+	}); err != nil {
+		return err
+	}
+	if err := readTablePages(ctx, tx, CartItemTableName, func(rows []CartItemTableModel) error {
+		chunk := &libProto.StateTransferChunk{}
+		for i := range rows {
+			chunk.CartItems = append(chunk.CartItems, convertCartItemTableModelToProtoCartItemModel(ctx, &rows[i]))
+		}
+		return send(chunk)
+	}); err != nil {
+		return err
+	}
+	return readTablePages(ctx, tx, TransactionTableName, func(rows []TransactionTableModel) error {
+		chunk := &libProto.StateTransferChunk{}
+		for i := range rows {
+			chunk.Transactions = append(chunk.Transactions, convertTransactionTableModelToProtoTransactionModel(ctx, &rows[i]))
+		}
+		return send(chunk)
+	})
+}
+
+// readTablePages reads a table in pages of stateTransferPageSize rows,
+// ordered by ID, and hands each page to fn.
+func readTablePages[T any](ctx context.Context, tx bun.Tx, tableName string, fn func(rows []T) error) error {
+	for offset := 0; ; offset += stateTransferPageSize {
+		var rows []T
+		if err := tx.NewSelect().Model(&rows).Order("id").Limit(stateTransferPageSize).Offset(offset).Scan(ctx); err != nil {
+			return fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Read", tableName, err)
+		}
+		if len(rows) == 0 {
+			return nil
+		}
+		if err := fn(rows); err != nil {
+			return err
+		}
+		if len(rows) < stateTransferPageSize {
+			return nil
+		}
+	}
+}
+
+// installSnapshotFromPeer replaces the tables with a snapshot streamed from
+// peerNodeName, in a single transaction that also records where the snapshot
+// was taken, as the last message applied. It's the totem.SnapshotFetcher of this service.
+func installSnapshotFromPeer(ctx context.Context, peerNodeName string, minGlobalSeqNum int32) (totem.SnapshotPoint, error) {
+	rpcClient, conn, err := common.NewSQLRPCClient(ctx, peerNodeName, serverPort, grpc.WithStreamInterceptor(peerKeyring.StreamClientInterceptor()))
+	if err != nil {
+		return totem.SnapshotPoint{}, err
+	}
+	defer conn.Close()
+	client, err := sql.NewSQLClient(ctx, serviceName, schemaName)
+	if err != nil {
+		return totem.SnapshotPoint{}, fmt.Errorf("exception while creating SQLDB client. %v", err)
+	}
+	defer client.Close(ctx)
+
+	streamCtx, cancel := context.WithCancel(ctx)
+	defer cancel()
+	stream, err := rpcClient.StateTransfer(streamCtx, &libProto.StateTransferRequest{RequestNodeName: nodeName, MinGlobalSeqNum: minGlobalSeqNum})
+	if err != nil {
+		return totem.SnapshotPoint{}, fmt.Errorf("exception while requesting snapshot. %v", err)
+	}
+
+	globalSeqNum := int32(-1)
+	deliveredRequests := map[string]totem.RequestWatermark{}
+	err = client.RunInTx(ctx, nil, func(ctx context.Context, tx bun.Tx) error {
+		if err := truncateTables(ctx, tx); err != nil {
+			return err
+		}
+		rowCount := 0
+		for {
+			chunk, err := stream.Recv()
+			if err == io.EOF {
+				break
+			}
+			if err != nil {
+				return fmt.Errorf("exception while receiving snapshot. %v", err)
+			}
+			if globalSeqNum == -1 {
+				globalSeqNum = chunk.GlobalSeqNum
+			} else if chunk.GlobalSeqNum != globalSeqNum {
+				return fmt.Errorf("chunk of snapshot %d in snapshot %d", chunk.GlobalSeqNum, globalSeqNum)
+			}
+			for nodeName, delivered := range totem.ConvertProtoRequestWatermarks(chunk.RequestWatermarks) {
+				deliveredRequests[nodeName] = delivered
+			}
+			n, err := insertSnapshotChunk(ctx, tx, chunk)
+			if err != nil {
+				return err
+			}
+			rowCount += n
+		}
+		if globalSeqNum < minGlobalSeqNum {
+			return fmt.Errorf("snapshot at sequence msg %d is older than %d", globalSeqNum, minGlobalSeqNum)
+		}
+		installed := &StateTransferTableModel{ID: 1, GlobalSeqNum: globalSeqNum, RequestWatermarks: deliveredRequests}
+		if _, err := tx.NewInsert().Model(installed).On("CONFLICT (id) DO UPDATE").
+			Set("? = EXCLUDED.?", bun.Ident("globalSeqNum"), bun.Ident("globalSeqNum")).
+			Set("? = EXCLUDED.?", bun.Ident("requestWatermarks"), bun.Ident("requestWatermarks")).
+			Exec(ctx); err != nil {
+			return fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Insert", StateTransferTableName, err)
+		}
+		if err := recordAppliedSeqNum(ctx, tx, globalSeqNum); err != nil {
+			return err
+		}
+		log.Infof("installSnapshotFromPeer(%s): Received %d rows at sequence msg %d from %s\n", nodeName, rowCount, globalSeqNum, peerNodeName)
+		return nil
+	})
+	if err != nil {
+		return totem.SnapshotPoint{}, err
+	}
+	return totem.SnapshotPoint{GlobalSeqNum: globalSeqNum, DeliveredRequests: deliveredRequests}, nil
+}
+
+// truncateTables empties every table a snapshot covers.
+func truncateTables(ctx context.Context, tx bun.Tx) error {
+	if _, err := tx.ExecContext(ctx, "TRUNCATE TABLE ?, ?, ?, ?, ?, ? CASCADE",
+		bun.Ident(TransactionTableName), bun.Ident(CartItemTableName), bun.Ident(CartTableName),
+		bun.Ident(SessionTableName), bun.Ident(BuyerTableName), bun.Ident(SellerTableName)); err != nil {
+		return fmt.Errorf("exception while truncating tables. %v", err)
+	}
+	return nil
+}
+
+// insertSnapshotChunk inserts the rows of a chunk and returns how many there
+// were.
+func insertSnapshotChunk(ctx context.Context, tx bun.Tx, chunk *libProto.StateTransferChunk) (int, error) {
+	var sellers []*SellerTableModel
+	for _, seller := range chunk.Sellers {
+		sellers = append(sellers, convertProtoSellerModelToSellerTableModel(ctx, seller))
+	}
+	var buyers []*BuyerTableModel
+	for _, buyer := range chunk.Buyers {
+		buyers = append(buyers, convertProtoBuyerModelToBuyerTableModel(ctx, buyer))
+	}
+	var sessions []*SessionTableModel
+	for _, session := range chunk.Sessions {
+		sessions = append(sessions, convertProtoSessionModelToSessionTableModel(ctx, session))
+	}
+	var carts []*CartTableModel
+	for _, cart := range chunk.Carts {
+		carts = append(carts, convertProtoCartModelToCartTableModel(ctx, cart))
+	}
+	var cartItems []*CartItemTableModel
+	for _, cartItem := range chunk.CartItems {
+		cartItems = append(cartItems, convertProtoCartItemModelToCartItemTableModel(ctx, cartItem))
+	}
+	var transactions []*TransactionTableModel
+	for _, transaction := range chunk.Transactions {
+		transactions = append(transactions, convertProtoTransactionModelToTransactionTableModel(ctx, transaction))
+	}
+
+	tables := []struct {
+		name  string
+		rows  interface{}
+		count int
+	}{
+		{SellerTableName, &sellers, len(sellers)},
+		{BuyerTableName, &buyers, len(buyers)},
+		{SessionTableName, &sessions, len(sessions)},
+		{CartTableName, &carts, len(carts)},
+		{CartItemTableName, &cartItems, len(cartItems)},
+		{TransactionTableName, &transactions, len(transactions)},
+	}
+	rowCount := 0
+	for _, table := range tables {
+		if table.count == 0 {
+			continue
+		}
+		if _, err := tx.NewInsert().Model(table.rows).Exec(ctx); err != nil {
+			return 0, fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Insert", table.name, err)
+		}
+		rowCount += table.count
+	}
+	return rowCount, nil
+}
+
+// readInstalledSnapshot returns where the last snapshot installed in the
+// database was taken, or false if none was.
+func readInstalledSnapshot(ctx context.Context) (totem.SnapshotPoint, bool, error) {
+	client, err := sql.NewSQLClient(ctx, serviceName, schemaName)
+	if err != nil {
+		return totem.SnapshotPoint{}, false, fmt.Errorf("exception while creating SQLDB client. %v", err)
+	}
+	defer client.Close(ctx)
+
+	var installed []StateTransferTableModel
+	if _, err := client.Read(ctx, StateTransferTableName, nil, nil, nil, nil, nil, false, &installed); err != nil {
+		return totem.SnapshotPoint{}, false, fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Read", StateTransferTableName, err)
+	}
+	if len(installed) == 0 {
+		return totem.SnapshotPoint{}, false, nil
+	}
+	return totem.SnapshotPoint{GlobalSeqNum: installed[0].GlobalSeqNum, DeliveredRequests: installed[0].RequestWatermarks}, true, nil
+}
@@ -9,7 +9,6 @@ import (
 
 	"github.com/adarshsrinivasan/DS_S24/library/db"
 	"github.com/adarshsrinivasan/DS_S24/library/db/sql"
-	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"github.com/uptrace/bun/schema"
 )
@@ -87,10 +86,9 @@ func (transaction *TransactionTableModel) CreateTransaction(ctx context.Context)
 	}
 	defer client.Close(ctx)
 
-	transaction.ID = uuid.New().String()
+	// The ID and timestamps come with the write, so that every replica
+	// inserts the same row (see stampWriteInterceptor).
 	transaction.Version = 0
-	transaction.CreatedAt = time.Now()
-	transaction.UpdatedAt = time.Now()
 
 	if err := client.Insert(ctx, transaction, TransactionTableName); err != nil {
 		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Insert", TransactionTableName, err)
@@ -6,15 +6,42 @@ import (
 	"github.com/adarshsrinivasan/DS_S24/library/common"
 	"github.com/adarshsrinivasan/DS_S24/library/proto"
 	"github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
+	"math/rand"
+	"net"
 	"net/http"
 	"strconv"
+	"sync"
+	"time"
 )
 
 const (
-	ProductTableName = "product_data"
+	ProductTableName         = "product_data"
+	NOSQLWriteRetryCount     = 3
+	NOSQLWriteRetryCooloff   = 200 * time.Millisecond
+	NOSQLWriteAttemptTimeout = 5 * time.Second
+
+	// NOSQLLearnersRefreshInterval is how often the list of product-db
+	// learners that searches are routed to is refreshed.
+	NOSQLLearnersRefreshInterval = 30 * time.Second
+	NOSQLListMembersTimeout      = time.Second
 )
 
+// nosqlClientSessions identifies this server's writes to product-db, so that
+// retried writes are applied only once.
+var nosqlClientSessions = common.NewClientSessionPool()
+
+// nosqlLearners caches the NOSQLService addresses of the product-db learners.
+var nosqlLearners = &nosqlLearnerCache{}
+
+type nosqlLearnerCache struct {
+	mu        sync.Mutex
+	addrs     []string
+	refreshed time.Time
+}
+
 type CATEGORY int
 
 const (
@@ -84,40 +111,17 @@ type ProductTableOps interface {
 
 func (product *ProductModel) CreateProduct(ctx context.Context) (int, error) {
 	protoModel := convertProductModelToProtoProductModel(ctx, product)
+	session := nosqlClientSessions.Get()
+	defer nosqlClientSessions.Put(session)
 	request := &proto.CreateProductRequest{
-		RequestModel: protoModel,
-	}
-	nosqlDBClient, conn, err := common.NewNOSQLRPCClient(ctx, nosqlRPCHost, nosqlRPCPort)
-	if err != nil {
-		err = fmt.Errorf("exception while connecting to SQLDB RPC server. %v", err)
-		logrus.Errorf("GetProductByID: %v\n", err)
-		return http.StatusInternalServerError, err
-	}
-	leaderInfo, err := nosqlDBClient.GetLeader(ctx, &proto.GetLeaderRequest{})
-	if err != nil {
-		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "GetLeader", ProductTableName, err)
-		logrus.Errorf("CreateProduct: %v\n", err)
-		return http.StatusInternalServerError, err
+		RequestModel:      protoModel,
+		ClientRequestInfo: session.Next(),
 	}
-	conn.Close()
-	logrus.Errorf("CreateProduct: Leader info: %+v", leaderInfo)
-	leaderPort := 0
-	for i := 0; i < len(nosqlNodeNames); i++ {
-		if nosqlNodeNames[i] == leaderInfo.GetLeaderNodeName() {
-			leaderPort, _ = strconv.Atoi(nosqlNodePorts[i])
-			break
-		}
-	}
-
-	nosqlDBClient, conn, err = common.NewNOSQLRPCClient(ctx, leaderInfo.GetLeaderNodeName(), leaderPort)
-	if err != nil {
-		err = fmt.Errorf("exception while connecting to SQLDB RPC server. %v", err)
-		logrus.Errorf("CreateProduct: %v\n", err)
-		return http.StatusInternalServerError, err
-	}
-	defer conn.Close()
-
-	response, err := nosqlDBClient.CreateProduct(ctx, request)
+	var response *proto.CreateProductResponse
+	err := invokeNOSQLWrite(ctx, func(ctx context.Context, nosqlDBClient proto.NOSQLServiceClient) (err error) {
+		response, err = nosqlDBClient.CreateProduct(ctx, request)
+		return err
+	})
 	if err != nil {
 		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Create", ProductTableName, err)
 		logrus.Errorf("CreateProduct: %v\n", err)
@@ -156,15 +160,11 @@ func (product *ProductModel) ListProductsByKeyWordsAndCategory(ctx context.Conte
 	request := &proto.ListProductsByKeyWordsAndCategoryRequest{
 		RequestModel: protoModel,
 	}
-	nosqlDBClient, conn, err := common.NewNOSQLRPCClient(ctx, nosqlRPCHost, nosqlRPCPort)
-	if err != nil {
-		err = fmt.Errorf("exception while connecting to SQLDB RPC server. %v", err)
-		logrus.Errorf("ListProductsByKeyWordsAndCategory: %v\n", err)
-		return nil, http.StatusInternalServerError, err
-	}
-	defer conn.Close()
-
-	response, err := nosqlDBClient.ListProductsByKeyWordsAndCategory(ctx, request)
+	var response *proto.ListProductsByKeyWordsAndCategoryResponse
+	err := invokeNOSQLSearch(ctx, func(ctx context.Context, nosqlDBClient proto.NOSQLServiceClient) (err error) {
+		response, err = nosqlDBClient.ListProductsByKeyWordsAndCategory(ctx, request)
+		return err
+	})
 	if err != nil {
 		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Read", ProductTableName, err)
 		logrus.Errorf("ListProductsByKeyWordsAndCategory: %v\n", err)
@@ -182,15 +182,11 @@ func (product *ProductModel) ListProductsBySellerID(ctx context.Context) ([]Prod
 	request := &proto.ListProductsBySellerIDRequest{
 		RequestModel: protoModel,
 	}
-	nosqlDBClient, conn, err := common.NewNOSQLRPCClient(ctx, nosqlRPCHost, nosqlRPCPort)
-	if err != nil {
-		err = fmt.Errorf("exception while connecting to SQLDB RPC server. %v", err)
-		logrus.Errorf("ListProductsBySellerID: %v\n", err)
-		return nil, http.StatusInternalServerError, err
-	}
-	defer conn.Close()
-
-	response, err := nosqlDBClient.ListProductsBySellerID(ctx, request)
+	var response *proto.ListProductsBySellerIDResponse
+	err := invokeNOSQLSearch(ctx, func(ctx context.Context, nosqlDBClient proto.NOSQLServiceClient) (err error) {
+		response, err = nosqlDBClient.ListProductsBySellerID(ctx, request)
+		return err
+	})
 	if err != nil {
 		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Read", ProductTableName, err)
 		logrus.Errorf("ListProductsBySellerID: %v\n", err)
@@ -205,40 +201,17 @@ func (product *ProductModel) ListProductsBySellerID(ctx context.Context) ([]Prod
 
 func (product *ProductModel) UpdateProductByID(ctx context.Context) (int, error) {
 	protoModel := convertProductModelToProtoProductModel(ctx, product)
+	session := nosqlClientSessions.Get()
+	defer nosqlClientSessions.Put(session)
 	request := &proto.UpdateProductByIDRequest{
-		RequestModel: protoModel,
+		RequestModel:      protoModel,
+		ClientRequestInfo: session.Next(),
 	}
-	nosqlDBClient, conn, err := common.NewNOSQLRPCClient(ctx, nosqlRPCHost, nosqlRPCPort)
-	if err != nil {
-		err = fmt.Errorf("exception while connecting to SQLDB RPC server. %v", err)
-		logrus.Errorf("UpdateProductByID: %v\n", err)
-		return http.StatusInternalServerError, err
-	}
-	leaderInfo, err := nosqlDBClient.GetLeader(ctx, &proto.GetLeaderRequest{})
-	if err != nil {
-		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "GetLeader", ProductTableName, err)
-		logrus.Errorf("UpdateProductByID: %v\n", err)
-		return http.StatusInternalServerError, err
-	}
-	conn.Close()
-	logrus.Errorf("UpdateProductByID: Leader info: %+v", leaderInfo)
-	leaderPort := 0
-	for i := 0; i < len(nosqlNodeNames); i++ {
-		if nosqlNodeNames[i] == leaderInfo.GetLeaderNodeName() {
-			leaderPort, _ = strconv.Atoi(nosqlNodePorts[i])
-			break
-		}
-	}
-
-	nosqlDBClient, conn, err = common.NewNOSQLRPCClient(ctx, leaderInfo.GetLeaderNodeName(), leaderPort)
-	if err != nil {
-		err = fmt.Errorf("exception while connecting to SQLDB RPC server. %v", err)
-		logrus.Errorf("UpdateProductByID: %v\n", err)
-		return http.StatusInternalServerError, err
-	}
-	defer conn.Close()
-
-	response, err := nosqlDBClient.UpdateProductByID(ctx, request)
+	var response *proto.UpdateProductByIDResponse
+	err := invokeNOSQLWrite(ctx, func(ctx context.Context, nosqlDBClient proto.NOSQLServiceClient) (err error) {
+		response, err = nosqlDBClient.UpdateProductByID(ctx, request)
+		return err
+	})
 	if err != nil {
 		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Update", ProductTableName, err)
 		logrus.Errorf("UpdateProductByID: %v\n", err)
@@ -249,45 +222,142 @@ func (product *ProductModel) UpdateProductByID(ctx context.Context) (int, error)
 }
 func (product *ProductModel) DeleteProductByID(ctx context.Context) (int, error) {
 	protoModel := convertProductModelToProtoProductModel(ctx, product)
+	session := nosqlClientSessions.Get()
+	defer nosqlClientSessions.Put(session)
 	request := &proto.DeleteProductByIDRequest{
-		RequestModel: protoModel,
+		RequestModel:      protoModel,
+		ClientRequestInfo: session.Next(),
 	}
-	nosqlDBClient, conn, err := common.NewNOSQLRPCClient(ctx, nosqlRPCHost, nosqlRPCPort)
+	err := invokeNOSQLWrite(ctx, func(ctx context.Context, nosqlDBClient proto.NOSQLServiceClient) error {
+		_, err := nosqlDBClient.DeleteProductByID(ctx, request)
+		return err
+	})
 	if err != nil {
-		err = fmt.Errorf("exception while connecting to SQLDB RPC server. %v", err)
+		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Delete", ProductTableName, err)
 		logrus.Errorf("DeleteProductByID: %v\n", err)
 		return http.StatusInternalServerError, err
 	}
-	leaderInfo, err := nosqlDBClient.GetLeader(ctx, &proto.GetLeaderRequest{})
-	if err != nil {
-		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "GetLeader", ProductTableName, err)
-		logrus.Errorf("DeleteProductByID: %v\n", err)
-		return http.StatusInternalServerError, err
+	return http.StatusOK, nil
+}
+
+// invokeNOSQLWrite runs invoke against the NOSQL node this server was
+// configured with, retrying it until it succeeds or NOSQLWriteRetryCount
+// attempts were made. Any node forwards writes to the raft leader. A node
+// that can't (no leader yet, or leadership moved while forwarding) replies
+// with a NotLeader error, and the write is retried against the hinted leader.
+// Writes carry ClientRequestInfo, so retrying after a timeout or a lost
+// connection is safe too: product-db applies each write at most once. Those
+// are retried against another node, picked at random.
+func invokeNOSQLWrite(ctx context.Context, invoke func(ctx context.Context, nosqlDBClient proto.NOSQLServiceClient) error) error {
+	host, port := nosqlRPCHost, nosqlRPCPort
+	for attempt := 1; ; attempt++ {
+		nosqlDBClient, conn, err := common.NewNOSQLRPCClient(ctx, host, port)
+		if err != nil {
+			err = fmt.Errorf("exception while connecting to NOSQLDB RPC server. %v", err)
+			logrus.Errorf("invokeNOSQLWrite: %v\n", err)
+			return err
+		}
+		attemptCtx, cancel := context.WithTimeout(ctx, NOSQLWriteAttemptTimeout)
+		err = invoke(attemptCtx, nosqlDBClient)
+		cancel()
+		conn.Close()
+		if err == nil || attempt >= NOSQLWriteRetryCount || ctx.Err() != nil {
+			return err
+		}
+		if details, notLeader := common.GetNotLeaderDetails(err); notLeader {
+			if details.GetLeaderNodeName() != "" {
+				host, port = getNOSQLLeaderHostNameAndPort(details.GetLeaderNodeName(), details.GetLeaderAddress())
+			}
+		} else if code := status.Code(err); code == codes.Unavailable || code == codes.DeadlineExceeded {
+			host, port = getNOSQLHostNameAndPort()
+		} else {
+			return err
+		}
+		logrus.Warnf("invokeNOSQLWrite: %v. Retry no. %d out of %d against %s:%d\n", err, attempt, NOSQLWriteRetryCount, host, port)
+		time.Sleep(NOSQLWriteRetryCooloff)
 	}
-	conn.Close()
-	logrus.Errorf("DeleteProductByID: Leader info: %+v", leaderInfo)
-	leaderPort := 0
-	for i := 0; i < len(nosqlNodeNames); i++ {
-		if nosqlNodeNames[i] == leaderInfo.GetLeaderNodeName() {
-			leaderPort, _ = strconv.Atoi(nosqlNodePorts[i])
-			break
+}
+
+// invokeNOSQLSearch runs invoke against a product-db learner, if the cluster
+// has any, so that search traffic doesn't slow down the voters that commit
+// writes. If the learner can't be reached, the search falls back to the node
+// this server was configured with.
+func invokeNOSQLSearch(ctx context.Context, invoke func(ctx context.Context, nosqlDBClient proto.NOSQLServiceClient) error) error {
+	host, port := nosqlRPCHost, nosqlRPCPort
+	if addrs := nosqlLearners.get(ctx); len(addrs) > 0 {
+		if learnerHost, learnerPort, err := net.SplitHostPort(addrs[rand.Intn(len(addrs))]); err == nil {
+			host = learnerHost
+			port, _ = strconv.Atoi(learnerPort)
 		}
 	}
+	for {
+		nosqlDBClient, conn, err := common.NewNOSQLRPCClient(ctx, host, port)
+		if err != nil {
+			err = fmt.Errorf("exception while connecting to NOSQLDB RPC server. %v", err)
+			logrus.Errorf("invokeNOSQLSearch: %v\n", err)
+			return err
+		}
+		err = invoke(ctx, nosqlDBClient)
+		conn.Close()
+		if status.Code(err) != codes.Unavailable || (host == nosqlRPCHost && port == nosqlRPCPort) {
+			return err
+		}
+		logrus.Warnf("invokeNOSQLSearch: learner %s:%d unavailable. %v. Falling back to %s:%d\n", host, port, err, nosqlRPCHost, nosqlRPCPort)
+		host, port = nosqlRPCHost, nosqlRPCPort
+	}
+}
 
-	nosqlDBClient, conn, err = common.NewNOSQLRPCClient(ctx, leaderInfo.GetLeaderNodeName(), leaderPort)
+// get returns the NOSQLService addresses of the learners, asking product-db
+// for its members if the cached list is too old. The previous list is kept if
+// that fails.
+func (cache *nosqlLearnerCache) get(ctx context.Context) []string {
+	cache.mu.Lock()
+	defer cache.mu.Unlock()
+	if time.Since(cache.refreshed) < NOSQLLearnersRefreshInterval {
+		return cache.addrs
+	}
+	cache.refreshed = time.Now()
+
+	nosqlDBClient, conn, err := common.NewNOSQLRPCClient(ctx, nosqlRPCHost, nosqlRPCPort)
 	if err != nil {
-		err = fmt.Errorf("exception while connecting to SQLDB RPC server. %v", err)
-		logrus.Errorf("DeleteProductByID: %v\n", err)
-		return http.StatusInternalServerError, err
+		logrus.Errorf("nosqlLearnerCache.get: %v\n", err)
+		return cache.addrs
 	}
 	defer conn.Close()
+	listCtx, cancel := context.WithTimeout(ctx, NOSQLListMembersTimeout)
+	defer cancel()
+	response, err := nosqlDBClient.ListMembers(listCtx, &proto.ListMembersRequest{})
+	if err != nil {
+		err = fmt.Errorf("exception while listing NOSQLDB members. %v", err)
+		logrus.Errorf("nosqlLearnerCache.get: %v\n", err)
+		return cache.addrs
+	}
+	var addrs []string
+	for _, member := range response.GetResponseModel() {
+		if member.GetLearner() && member.GetClientAddress() != "" {
+			addrs = append(addrs, member.GetClientAddress())
+		}
+	}
+	cache.addrs = addrs
+	return cache.addrs
+}
 
-	if _, err := nosqlDBClient.DeleteProductByID(ctx, request); err != nil {
-		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Delete", ProductTableName, err)
-		logrus.Errorf("DeleteProductByID: %v\n", err)
-		return http.StatusInternalServerError, err
+// getNOSQLLeaderHostNameAndPort resolves a leader hint to the host and port
+// of its NOSQLService. Nodes added to the cluster after startup aren't in
+// NOSQL_NODE_NAMES, so the address advertised for the leader is used for
+// those.
+func getNOSQLLeaderHostNameAndPort(leaderNodeName, leaderAddress string) (string, int) {
+	for i := 0; i < len(nosqlNodeNames); i++ {
+		if nosqlNodeNames[i] == leaderNodeName {
+			leaderPort, _ := strconv.Atoi(nosqlNodePorts[i])
+			return leaderNodeName, leaderPort
+		}
 	}
-	return http.StatusOK, nil
+	if leaderHost, port, err := net.SplitHostPort(leaderAddress); err == nil {
+		leaderPort, _ := strconv.Atoi(port)
+		return leaderHost, leaderPort
+	}
+	return leaderNodeName, 0
 }
 
 func copyProductModelObject(from *proto.ProductModel, to *ProductModel) {
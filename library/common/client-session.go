@@ -0,0 +1,56 @@
+package common
+
+import (
+	"sync"
+
+	myproto "github.com/adarshsrinivasan/DS_S24/library/proto"
+)
+
+// ClientSession identifies a client of a replicated service, so that retries
+// of its writes are applied at most once. A session must only have a single
+// write outstanding at a time; concurrent callers take separate sessions from
+// a ClientSessionPool.
+type ClientSession struct {
+	ClientID    string
+	SequenceNum int64
+}
+
+// Next starts a new write in the session and returns the info identifying
+// it. Retries of that write must reuse the returned info.
+func (session *ClientSession) Next() *myproto.ClientRequestInfo {
+	session.SequenceNum++
+	return &myproto.ClientRequestInfo{
+		ClientID:    session.ClientID,
+		SequenceNum: session.SequenceNum,
+	}
+}
+
+// ClientSessionPool hands out client sessions to concurrent callers, creating
+// new ones when all existing sessions are in use.
+type ClientSessionPool struct {
+	mu   sync.Mutex
+	free []*ClientSession
+}
+
+func NewClientSessionPool() *ClientSessionPool {
+	return &ClientSessionPool{}
+}
+
+// Get returns a session that isn't in use by anyone else.
+func (pool *ClientSessionPool) Get() *ClientSession {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	if len(pool.free) == 0 {
+		return &ClientSession{ClientID: GenerateUUID()}
+	}
+	session := pool.free[len(pool.free)-1]
+	pool.free = pool.free[:len(pool.free)-1]
+	return session
+}
+
+// Put returns a session to the pool once its write is done.
+func (pool *ClientSessionPool) Put(session *ClientSession) {
+	pool.mu.Lock()
+	defer pool.mu.Unlock()
+	pool.free = append(pool.free, session)
+}
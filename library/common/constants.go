@@ -3,15 +3,22 @@ package common
 type UserType int
 
 const (
-	NodeNameEnv       = "NODE_NAME"
-	SQLNodeNamesEnv   = "SQL_NODE_NAMES"
-	SQLNodePortsEnv   = "SQL_NODE_PORTS"
-	NOSQLNodeNamesEnv = "NOSQL_NODE_NAMES"
-	NOSQLNodePortsEnv = "NOSQL_NODE_PORTS"
-	PeerNodeNamesEnv  = "PEER_NODE_NAMES"
-	PeerNodePortsEnv  = "PEER_NODE_PORTS"
-	SyncHostEnv       = "SYNC_HOST"
-	SyncPortEnv       = "SYNC_PORT"
+	NodeNameEnv              = "NODE_NAME"
+	SQLNodeNamesEnv          = "SQL_NODE_NAMES"
+	SQLNodePortsEnv          = "SQL_NODE_PORTS"
+	NOSQLNodeNamesEnv        = "NOSQL_NODE_NAMES"
+	NOSQLNodePortsEnv        = "NOSQL_NODE_PORTS"
+	PeerNodeNamesEnv         = "PEER_NODE_NAMES"
+	PeerNodePortsEnv         = "PEER_NODE_PORTS"
+	SyncHostEnv              = "SYNC_HOST"
+	SyncPortEnv              = "SYNC_PORT"
+	RaftStorageDirEnv        = "RAFT_STORAGE_DIR"
+	RaftSnapshotThresholdEnv = "RAFT_SNAPSHOT_THRESHOLD"
+	RaftJoinEnv              = "RAFT_JOIN"
+	RaftDebugPortEnv         = "RAFT_DEBUG_PORT"
+	TotemStorageDirEnv       = "TOTEM_STORAGE_DIR"
+	ReplicationEngineEnv     = "REPLICATION_ENGINE"
+	PeerAuthKeysEnv          = "PEER_AUTH_KEYS"
 )
 const (
 	BUYER UserType = iota
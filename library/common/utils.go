@@ -8,7 +8,9 @@ import (
 	"github.com/google/uuid"
 	"github.com/sirupsen/logrus"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/status"
 	"math/rand"
 	"os"
 	"strconv"
@@ -77,8 +79,11 @@ func NewNOSQLRPCClient(ctx context.Context, nosqlRPCHost string, nosqlRPCPort in
 	return client, conn, err
 }
 
-func NewSQLRPCClient(ctx context.Context, sqlRPCHost string, sqlRPCPort int) (myproto.SQLServiceClient, *grpc.ClientConn, error) {
-	conn, err := grpc.Dial(fmt.Sprintf("%s:%d", sqlRPCHost, sqlRPCPort), grpc.WithTransportCredentials(insecure.NewCredentials()))
+// NewSQLRPCClient connects to the SQLService at sqlRPCHost, with opts on top
+// of an insecure connection.
+func NewSQLRPCClient(ctx context.Context, sqlRPCHost string, sqlRPCPort int, opts ...grpc.DialOption) (myproto.SQLServiceClient, *grpc.ClientConn, error) {
+	opts = append([]grpc.DialOption{grpc.WithTransportCredentials(insecure.NewCredentials())}, opts...)
+	conn, err := grpc.Dial(fmt.Sprintf("%s:%d", sqlRPCHost, sqlRPCPort), opts...)
 	if err != nil {
 		err = fmt.Errorf("exception while connecting to SQLDB RPC server. %v", err)
 		logrus.Errorf("NewSQLRPCClient: %v\n", err)
@@ -88,6 +93,39 @@ func NewSQLRPCClient(ctx context.Context, sqlRPCHost string, sqlRPCPort int) (my
 	return client, conn, err
 }
 
+// NewNotLeaderError returns the error a NOSQL node replies with when it can
+// neither apply a write nor forward it to the leader. leaderNodeName is empty
+// when no leader is known, in which case the status is Unavailable.
+func NewNotLeaderError(nodeName, leaderNodeName, leaderAddress string) error {
+	st := status.New(codes.FailedPrecondition, fmt.Sprintf("%s is not the leader. leader: %s", nodeName, leaderNodeName))
+	if leaderNodeName == "" {
+		st = status.New(codes.Unavailable, fmt.Sprintf("%s is not the leader. leader unknown", nodeName))
+	}
+	detailed, err := st.WithDetails(&myproto.NotLeaderDetails{
+		LeaderNodeName: leaderNodeName,
+		LeaderAddress:  leaderAddress,
+	})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// GetNotLeaderDetails reports whether err was created by NewNotLeaderError
+// and returns the leader hint it carries.
+func GetNotLeaderDetails(err error) (*myproto.NotLeaderDetails, bool) {
+	st, ok := status.FromError(err)
+	if !ok || err == nil {
+		return nil, false
+	}
+	for _, detail := range st.Details() {
+		if details, ok := detail.(*myproto.NotLeaderDetails); ok {
+			return details, true
+		}
+	}
+	return nil, false
+}
+
 func ReturnTrueWithProbability(probability int) bool {
 	if probability < 0 {
 		probability *= -1
@@ -107,6 +107,16 @@ func (client *clientObj) CreateCollection(ctx context.Context, collectionName st
 	return nil
 }
 
+// DropCollection drops the specified collection along with all its documents.
+func (client *clientObj) DropCollection(ctx context.Context, collectionName string) error {
+	if err := client.dbClient.Collection(collectionName).Drop(ctx); err != nil {
+		err = fmt.Errorf("exception while dropping collection in mongo DB: %v", err)
+		logrus.Errorf("DropCollection: %v\n", err)
+		return err
+	}
+	return nil
+}
+
 func (client *clientObj) isCollectionPresent(ctx context.Context, collectionName string) bool {
 	coll, _ := client.dbClient.ListCollectionNames(ctx, bson.D{{"name", collectionName}})
 	return len(coll) == 1
@@ -124,6 +134,20 @@ func (client *clientObj) InsertOne(ctx context.Context, collectionName string, d
 	return http.StatusOK, nil
 }
 
+// InsertMany inserts the given documents into the specified collection.
+func (client *clientObj) InsertMany(ctx context.Context, collectionName string, documents []interface{}) (int, error) {
+	if len(documents) == 0 {
+		return http.StatusOK, nil
+	}
+	collection := client.dbClient.Collection(collectionName)
+	if _, err := collection.InsertMany(ctx, documents); err != nil {
+		err = fmt.Errorf("exception while Inserting documents in mongo DB: %v", err)
+		logrus.Errorf("InsertMany: %v\n", err)
+		return http.StatusInternalServerError, err
+	}
+	return http.StatusOK, nil
+}
+
 // FindOne finds a document in the specified collection based on the filter.
 func (client *clientObj) FindOne(ctx context.Context, collectionName string, whereClauses []db.WhereClauseType, result interface{}) (int, error) {
 	filter := whereClausesToFilter(whereClauses)
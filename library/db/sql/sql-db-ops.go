@@ -46,6 +46,23 @@ var (
 	poolObj *connPool
 )
 
+// txContextKey is the context key under which WithTx stores a transaction.
+type txContextKey struct{}
+
+// WithTx returns a copy of ctx carrying tx. Insert, Read, Update and Delete
+// called with it run in tx, whichever client they're called on.
+func WithTx(ctx context.Context, tx bun.Tx) context.Context {
+	return context.WithValue(ctx, txContextKey{}, tx)
+}
+
+// idb returns the transaction carried by ctx, if any, or the client's pool.
+func (client *clientObj) idb(ctx context.Context) bun.IDB {
+	if tx, ok := ctx.Value(txContextKey{}).(bun.Tx); ok {
+		return tx
+	}
+	return client.bunClient
+}
+
 func getSQLClient(ctx context.Context, applicationName, schemaName string) *bun.DB {
 	host := common.GetEnv(PostgresHostEnv, "localhost")
 	port := common.GetEnv(PostgresPortEnv, "5432")
@@ -163,7 +180,7 @@ func (client *clientObj) CreateTable(ctx context.Context, model interface{}, tab
 }
 
 func (client *clientObj) Insert(ctx context.Context, model interface{}, tableName string) error {
-	if _, err := client.bunClient.NewInsert().Model(model).Exec(ctx); err != nil {
+	if _, err := client.idb(ctx).NewInsert().Model(model).Exec(ctx); err != nil {
 		err := fmt.Errorf("unable to Perform %s Operation on Table: %s. %v", "Insert", tableName, err)
 		logrus.Errorf("InsertOne: %v\n", err)
 		return err
@@ -180,7 +197,7 @@ func (client *clientObj) Read(ctx context.Context, tableName string, pagination
 		readQuery     *bun.SelectQuery
 	)
 
-	readQuery = client.bunClient.NewSelect().Model(result)
+	readQuery = client.idb(ctx).NewSelect().Model(result)
 
 	if len(selectedColumns) != 0 {
 		colListStr := client.createColumnList(ctx, selectedColumns)
@@ -275,7 +292,7 @@ func (client *clientObj) Update(ctx context.Context, model interface{}, tableNam
 }
 
 func (client *clientObj) Delete(ctx context.Context, model interface{}, tableName string, whereClauseFilters []db.WhereClauseType) error {
-	deleteQuery := client.bunClient.NewDelete().
+	deleteQuery := client.idb(ctx).NewDelete().
 		Model(model)
 
 	// prepare whereClause.
@@ -295,6 +312,17 @@ func (client *clientObj) Delete(ctx context.Context, model interface{}, tableNam
 	return nil
 }
 
+// RunInTx runs fn in a transaction started with opts, committing it if fn
+// returns nil and rolling it back otherwise.
+func (client *clientObj) RunInTx(ctx context.Context, opts *sql.TxOptions, fn func(ctx context.Context, tx bun.Tx) error) error {
+	if err := client.bunClient.RunInTx(ctx, opts, fn); err != nil {
+		err := fmt.Errorf("unable to Perform %s Operation. %v", "RunInTx", err)
+		logrus.Errorf("RunInTx: %v\n", err)
+		return err
+	}
+	return nil
+}
+
 func (client *clientObj) Close(ctx context.Context) error {
 	//poolObj.close(ctx, client)
 	return client.bunClient.Close()
@@ -310,7 +338,7 @@ func (client *clientObj) prepareForeignKeyQuery(foreignKeyObj db.ForeignKey) str
 }
 
 func (client *clientObj) prepareUpdateQuery(ctx context.Context, data interface{}, igVersionCheck bool) *bun.UpdateQuery { // nolint
-	q := client.bunClient.NewUpdate().Model(data).WherePK()
+	q := client.idb(ctx).NewUpdate().Model(data).WherePK()
 	logrus.Debugf("PrepareUpdateQuery: updateQuery-start: %s", q.String())
 	v := reflect.ValueOf(data).Elem()
 	for i := 0; i < v.NumField(); i++ {
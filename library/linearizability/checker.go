@@ -0,0 +1,283 @@
+package linearizability
+
+import (
+	"math/bits"
+	"sort"
+	"sync/atomic"
+	"time"
+)
+
+// CheckResult is the outcome of a check.
+type CheckResult int
+
+const (
+	// Ok means the history is linearizable.
+	Ok CheckResult = iota
+
+	// Illegal means the history isn't linearizable.
+	Illegal
+
+	// Unknown means the check timed out.
+	Unknown
+)
+
+func (r CheckResult) String() string {
+	switch r {
+	case Ok:
+		return "Ok"
+	case Illegal:
+		return "Illegal"
+	default:
+		return "Unknown"
+	}
+}
+
+// CheckOperations reports whether history is linearizable with respect to
+// model.
+func CheckOperations(model Model, history []Operation) bool {
+	return CheckOperationsTimeout(model, history, 0) == Ok
+}
+
+// CheckOperationsTimeout checks whether history is linearizable with respect
+// to model, giving up after timeout. A timeout of 0 means no timeout. The
+// partitions of the history are checked concurrently; the first illegal one
+// stops the others.
+func CheckOperationsTimeout(model Model, history []Operation, timeout time.Duration) CheckResult {
+	partitions := model.partition(history)
+	var kill int32
+	results := make(chan bool, len(partitions))
+	for _, partition := range partitions {
+		go func(partition []Operation) {
+			results <- checkSingle(model, makeEntries(partition), &kill)
+		}(partition)
+	}
+
+	var deadline <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		deadline = timer.C
+	}
+	result := Ok
+	for range partitions {
+		select {
+		case ok := <-results:
+			if !ok {
+				atomic.StoreInt32(&kill, 1)
+				return Illegal
+			}
+		case <-deadline:
+			atomic.StoreInt32(&kill, 1)
+			result = Unknown
+			// Partitions that already finished may still have found an
+			// illegal history.
+			for range partitions {
+				select {
+				case ok := <-results:
+					if !ok {
+						return Illegal
+					}
+				default:
+				}
+			}
+			return result
+		}
+	}
+	return result
+}
+
+type entryKind bool
+
+const (
+	callEntry   entryKind = false
+	returnEntry entryKind = true
+)
+
+// entry is the invocation or the response of an operation.
+type entry struct {
+	kind  entryKind
+	value interface{}
+	id    int
+	time  int64
+}
+
+// makeEntries turns operations into their invocations and responses, in
+// time order. An invocation sorts before a response at the same time, so
+// the two operations are taken as concurrent.
+func makeEntries(history []Operation) []entry {
+	var entries []entry
+	for id, op := range history {
+		entries = append(entries,
+			entry{kind: callEntry, value: op.Input, id: id, time: op.Call},
+			entry{kind: returnEntry, value: op.Output, id: id, time: op.Return})
+	}
+	sort.SliceStable(entries, func(i, j int) bool {
+		if entries[i].time != entries[j].time {
+			return entries[i].time < entries[j].time
+		}
+		return entries[i].kind == callEntry && entries[j].kind == returnEntry
+	})
+	return entries
+}
+
+// node is an entry in the doubly linked list the search works on. The node
+// of an invocation points to the node of its response through match.
+type node struct {
+	value interface{}
+	match *node
+	id    int
+	next  *node
+	prev  *node
+}
+
+// makeLinkedEntries links entries into a list headed by a sentinel node.
+func makeLinkedEntries(entries []entry) *node {
+	head := &node{id: -1}
+	matches := make(map[int]*node)
+	tail := head
+	for _, e := range entries {
+		n := &node{value: e.value, id: e.id, prev: tail}
+		if e.kind == returnEntry {
+			matches[e.id] = n
+		}
+		tail.next = n
+		tail = n
+	}
+	for n := head.next; n != nil; n = n.next {
+		if match, found := matches[n.id]; found && match != n {
+			n.match = match
+		}
+	}
+	return head
+}
+
+// lift takes an invocation and its response out of the list.
+func lift(n *node) {
+	n.prev.next = n.next
+	n.next.prev = n.prev
+	match := n.match
+	match.prev.next = match.next
+	if match.next != nil {
+		match.next.prev = match.prev
+	}
+}
+
+// unlift puts back an invocation and its response taken out by lift.
+func unlift(n *node) {
+	match := n.match
+	match.prev.next = match
+	if match.next != nil {
+		match.next.prev = match
+	}
+	n.prev.next = n
+	n.next.prev = n
+}
+
+// bitset is the set of operations linearized so far.
+type bitset []uint64
+
+func newBitset(n int) bitset {
+	return make(bitset, (n+63)/64)
+}
+
+func (b bitset) clone() bitset {
+	return append(bitset(nil), b...)
+}
+
+func (b bitset) set(pos int) bitset {
+	b[pos/64] |= 1 << uint(pos%64)
+	return b
+}
+
+func (b bitset) clear(pos int) bitset {
+	b[pos/64] &^= 1 << uint(pos%64)
+	return b
+}
+
+func (b bitset) hash() uint64 {
+	hash := uint64(len(b))
+	for _, word := range b {
+		hash = bits.RotateLeft64(hash, 7) ^ word
+	}
+	return hash
+}
+
+func (b bitset) equals(other bitset) bool {
+	for i := range b {
+		if b[i] != other[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// cacheEntry is a set of linearized operations and the state they lead to.
+// The search never explores the same pair twice.
+type cacheEntry struct {
+	linearized bitset
+	state      interface{}
+}
+
+// callsEntry is an invocation linearized by the search, and the state before
+// it, to backtrack to.
+type callsEntry struct {
+	n     *node
+	state interface{}
+}
+
+// checkSingle searches for a linearization of entries. It backtracks
+// whenever the first pending response in the list can't be reached, i.e.
+// its operation hasn't been linearized yet. It gives up, returning true,
+// once kill is set.
+func checkSingle(model Model, entries []entry, kill *int32) bool {
+	head := makeLinkedEntries(entries)
+	linearized := newBitset(len(entries) / 2)
+	cache := make(map[uint64][]cacheEntry)
+	var calls []callsEntry
+
+	state := model.Init()
+	n := head.next
+	for head.next != nil {
+		if atomic.LoadInt32(kill) != 0 {
+			return true
+		}
+		if n.match != nil {
+			ok, newState := model.Step(state, n.value, n.match.value)
+			if ok {
+				newLinearized := linearized.clone().set(n.id)
+				if !cacheContains(model, cache, newLinearized, newState) {
+					hash := newLinearized.hash()
+					cache[hash] = append(cache[hash], cacheEntry{linearized: newLinearized, state: newState})
+					calls = append(calls, callsEntry{n: n, state: state})
+					state = newState
+					linearized.set(n.id)
+					lift(n)
+					n = head.next
+					continue
+				}
+			}
+			n = n.next
+			continue
+		}
+		// n is a response whose operation couldn't be linearized before it.
+		if len(calls) == 0 {
+			return false
+		}
+		top := calls[len(calls)-1]
+		calls = calls[:len(calls)-1]
+		n, state = top.n, top.state
+		linearized.clear(n.id)
+		unlift(n)
+		n = n.next
+	}
+	return true
+}
+
+func cacheContains(model Model, cache map[uint64][]cacheEntry, linearized bitset, state interface{}) bool {
+	for _, entry := range cache[linearized.hash()] {
+		if linearized.equals(entry.linearized) && model.equal(state, entry.state) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,172 @@
+package linearizability
+
+import (
+	"math"
+	"testing"
+)
+
+func create(clientID int, productID string, quantity int, found bool, call, ret int64) Operation {
+	return Operation{
+		ClientID: clientID,
+		Input:    ProductInput{Op: ProductCreate, ProductID: productID, Quantity: quantity},
+		Output:   ProductOutput{Found: found},
+		Call:     call,
+		Return:   ret,
+	}
+}
+
+func update(clientID int, productID string, quantity int, found bool, call, ret int64) Operation {
+	return Operation{
+		ClientID: clientID,
+		Input:    ProductInput{Op: ProductUpdate, ProductID: productID, Quantity: quantity},
+		Output:   ProductOutput{Found: found},
+		Call:     call,
+		Return:   ret,
+	}
+}
+
+func get(clientID int, productID string, quantity int, found bool, call, ret int64) Operation {
+	return Operation{
+		ClientID: clientID,
+		Input:    ProductInput{Op: ProductGet, ProductID: productID},
+		Output:   ProductOutput{Found: found, Quantity: quantity},
+		Call:     call,
+		Return:   ret,
+	}
+}
+
+func TestCheckOperations(t *testing.T) {
+	tests := []struct {
+		name    string
+		history []Operation
+		want    bool
+	}{
+		{
+			name: "sequential",
+			history: []Operation{
+				get(0, "p", 0, false, 0, 5),
+				create(0, "p", 1, false, 10, 20),
+				get(1, "p", 1, true, 30, 40),
+				update(1, "p", 2, true, 50, 60),
+				get(0, "p", 2, true, 70, 80),
+			},
+			want: true,
+		},
+		{
+			name: "read concurrent with a write sees the old value",
+			history: []Operation{
+				create(0, "p", 1, false, 0, 10),
+				update(0, "p", 2, true, 20, 50),
+				get(1, "p", 1, true, 30, 40),
+			},
+			want: true,
+		},
+		{
+			name: "read concurrent with a write sees the new value",
+			history: []Operation{
+				create(0, "p", 1, false, 0, 10),
+				update(0, "p", 2, true, 20, 50),
+				get(1, "p", 2, true, 30, 40),
+			},
+			want: true,
+		},
+		{
+			name: "stale read after a completed write",
+			history: []Operation{
+				create(0, "p", 1, false, 0, 10),
+				update(0, "p", 2, true, 20, 30),
+				get(1, "p", 1, true, 40, 50),
+			},
+			want: false,
+		},
+		{
+			name: "read of a product created afterwards",
+			history: []Operation{
+				get(1, "p", 1, true, 0, 10),
+				create(0, "p", 1, false, 20, 30),
+			},
+			want: false,
+		},
+		{
+			name: "concurrent overlapping writes in either order",
+			history: []Operation{
+				create(0, "p", 1, false, 0, 10),
+				update(0, "p", 2, true, 20, 100),
+				update(1, "p", 3, true, 30, 90),
+				get(2, "p", 2, true, 110, 120),
+				get(2, "p", 2, true, 130, 140),
+			},
+			want: true,
+		},
+		{
+			name: "concurrent overlapping writes seen in two orders",
+			history: []Operation{
+				create(0, "p", 1, false, 0, 10),
+				update(0, "p", 2, true, 20, 100),
+				update(1, "p", 3, true, 30, 90),
+				get(2, "p", 3, true, 110, 120),
+				get(2, "p", 2, true, 130, 140),
+			},
+			want: false,
+		},
+		{
+			name: "concurrent creates both succeed",
+			history: []Operation{
+				create(0, "p", 1, false, 0, 50),
+				create(1, "p", 2, false, 10, 40),
+			},
+			want: false,
+		},
+		{
+			name: "pending write may take effect late",
+			history: []Operation{
+				create(0, "p", 1, false, 0, 10),
+				{ClientID: 0, Input: ProductInput{Op: ProductUpdate, ProductID: "p", Quantity: 2}, Call: 20, Return: math.MaxInt64},
+				get(1, "p", 1, true, 30, 40),
+				get(1, "p", 2, true, 50, 60),
+			},
+			want: true,
+		},
+		{
+			name: "products are checked independently",
+			history: []Operation{
+				create(0, "p", 1, false, 0, 10),
+				create(1, "q", 5, false, 0, 10),
+				update(0, "p", 2, true, 20, 30),
+				get(1, "q", 5, true, 40, 50),
+				get(1, "p", 1, true, 40, 50),
+			},
+			want: false,
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			if got := CheckOperations(ProductModel, test.history); got != test.want {
+				t.Fatalf("CheckOperations: got %t; want %t", got, test.want)
+			}
+		})
+	}
+}
+
+func TestHistoryOperations(t *testing.T) {
+	history := NewHistory()
+	done := history.Invoke(0, ProductInput{Op: ProductCreate, ProductID: "p", Quantity: 1})
+	history.Return(done, ProductOutput{})
+	dropped := history.Invoke(1, ProductInput{Op: ProductGet, ProductID: "p"})
+	history.Drop(dropped)
+	history.Invoke(2, ProductInput{Op: ProductUpdate, ProductID: "p", Quantity: 2})
+
+	operations := history.Operations()
+	if len(operations) != 2 {
+		t.Fatalf("got %d operations; want 2", len(operations))
+	}
+	if operations[0].Output == nil || operations[0].Return < operations[0].Call {
+		t.Fatalf("returned operation: %+v", operations[0])
+	}
+	if operations[1].Output != nil || operations[1].Return != math.MaxInt64 {
+		t.Fatalf("pending operation: %+v", operations[1])
+	}
+	if !CheckOperations(ProductModel, operations) {
+		t.Fatalf("history should be linearizable: %+v", operations)
+	}
+}
@@ -0,0 +1,81 @@
+package linearizability
+
+import (
+	"math"
+	"sync"
+	"time"
+)
+
+// History records the operations of concurrent clients. It's safe for
+// concurrent use.
+type History struct {
+	mu         sync.Mutex
+	start      time.Time
+	operations []Operation
+	pending    map[int]bool
+	dropped    map[int]bool
+}
+
+// NewHistory returns an empty history.
+func NewHistory() *History {
+	return &History{
+		start:   time.Now(),
+		pending: make(map[int]bool),
+		dropped: make(map[int]bool),
+	}
+}
+
+// Invoke records that clientID invoked an operation with input, and returns
+// the ID to record its outcome with.
+func (h *History) Invoke(clientID int, input interface{}) int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	id := len(h.operations)
+	h.operations = append(h.operations, Operation{
+		ClientID: clientID,
+		Input:    input,
+		Call:     time.Since(h.start).Nanoseconds(),
+	})
+	h.pending[id] = true
+	return id
+}
+
+// Return records the output of the operation with the given id.
+func (h *History) Return(id int, output interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.operations[id].Output = output
+	h.operations[id].Return = time.Since(h.start).Nanoseconds()
+	delete(h.pending, id)
+}
+
+// Drop forgets the operation with the given id. It's meant for operations
+// that failed without any effect, like a read that timed out. Operations
+// whose effect is unknown, like a write that timed out, must be left
+// pending instead.
+func (h *History) Drop(id int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.dropped[id] = true
+	delete(h.pending, id)
+}
+
+// Operations returns the operations recorded so far. Pending operations have
+// a nil output and return at the end of time: they may take effect at any
+// point after they were invoked, or not at all.
+func (h *History) Operations() []Operation {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	var operations []Operation
+	for id, op := range h.operations {
+		if h.dropped[id] {
+			continue
+		}
+		if h.pending[id] {
+			op.Output = nil
+			op.Return = math.MaxInt64
+		}
+		operations = append(operations, op)
+	}
+	return operations
+}
@@ -0,0 +1,53 @@
+// Package linearizability checks that a history of operations on a replicated
+// object is linearizable: that every operation appears to take effect at a
+// single instant between its invocation and its response, in an order that
+// a sequential model of the object accepts.
+//
+// The checker follows Porcupine (https://github.com/anishathalye/porcupine),
+// which implements the algorithm of Wing & Gong with the memoization of Lowe,
+// and splits histories into independent parts, e.g. one per key, first.
+package linearizability
+
+// Operation is a single operation of a history: its input and output, and
+// the times it was invoked and returned at. Call and Return are
+// nanoseconds from an arbitrary origin shared by the whole history.
+type Operation struct {
+	ClientID int
+	Input    interface{}
+	Call     int64
+	Output   interface{}
+	Return   int64
+}
+
+// Model is a sequential specification of an object.
+type Model struct {
+	// Partition splits a history into histories that can be checked
+	// separately, e.g. one per key. It's optional; by default the whole
+	// history is checked at once.
+	Partition func(history []Operation) [][]Operation
+
+	// Init returns the initial state of the object.
+	Init func() interface{}
+
+	// Step reports whether output is a valid response to input in state,
+	// and returns the state after the operation.
+	Step func(state interface{}, input interface{}, output interface{}) (bool, interface{})
+
+	// Equal reports whether two states are the same. It's optional; by
+	// default states are compared with ==.
+	Equal func(state1, state2 interface{}) bool
+}
+
+func (model Model) partition(history []Operation) [][]Operation {
+	if model.Partition == nil {
+		return [][]Operation{history}
+	}
+	return model.Partition(history)
+}
+
+func (model Model) equal(state1, state2 interface{}) bool {
+	if model.Equal == nil {
+		return state1 == state2
+	}
+	return model.Equal(state1, state2)
+}
@@ -0,0 +1,96 @@
+package linearizability
+
+import "sort"
+
+// ProductOp is an operation of ProductModel.
+type ProductOp int
+
+const (
+	ProductCreate ProductOp = iota
+	ProductUpdate
+	ProductGet
+)
+
+// ProductInput is the input of an operation on a single product.
+type ProductInput struct {
+	Op        ProductOp
+	ProductID string
+
+	// Quantity is the quantity written by ProductCreate and ProductUpdate.
+	Quantity int
+}
+
+// ProductOutput is the output of an operation on a single product.
+type ProductOutput struct {
+	// Found is set if the product existed when the operation took effect:
+	// ProductCreate fails if it did, ProductUpdate if it didn't.
+	Found bool
+
+	// Quantity is the quantity read by ProductGet.
+	Quantity int
+}
+
+// productState is the state of a single product.
+type productState struct {
+	Found    bool
+	Quantity int
+}
+
+// ProductModel is the product catalog as a register per product: a product
+// is created once and then updated and read as a whole. Histories are
+// checked one product at a time.
+//
+// A nil output stands for an operation whose outcome is unknown; a write
+// may then have taken effect or not.
+var ProductModel = Model{
+	Partition: func(history []Operation) [][]Operation {
+		byProduct := make(map[string][]Operation)
+		for _, op := range history {
+			productID := op.Input.(ProductInput).ProductID
+			byProduct[productID] = append(byProduct[productID], op)
+		}
+		var productIDs []string
+		for productID := range byProduct {
+			productIDs = append(productIDs, productID)
+		}
+		sort.Strings(productIDs)
+		var partitions [][]Operation
+		for _, productID := range productIDs {
+			partitions = append(partitions, byProduct[productID])
+		}
+		return partitions
+	},
+	Init: func() interface{} {
+		return productState{}
+	},
+	Step: func(state interface{}, input interface{}, output interface{}) (bool, interface{}) {
+		current := state.(productState)
+		in := input.(ProductInput)
+		if output == nil {
+			return true, productStep(current, in)
+		}
+		out := output.(ProductOutput)
+		if out.Found != current.Found {
+			return false, current
+		}
+		if in.Op == ProductGet && out.Quantity != current.Quantity {
+			return false, current
+		}
+		return true, productStep(current, in)
+	},
+}
+
+// productStep returns the state of a product after a successful op.
+func productStep(state productState, input ProductInput) productState {
+	switch input.Op {
+	case ProductCreate:
+		if !state.Found {
+			return productState{Found: true, Quantity: input.Quantity}
+		}
+	case ProductUpdate:
+		if state.Found {
+			return productState{Found: true, Quantity: input.Quantity}
+		}
+	}
+	return state
+}
@@ -0,0 +1,337 @@
+// Package peerauth authenticates the traffic between the replicas of a
+// database service with an HMAC keyed by a secret shared by the cluster. The
+// sequencer signs every datagram it sends (see library/totem) and the Raft
+// servers sign every RPC they make to each other (see library/raft), as do
+// the nodes of the customer-db streaming a snapshot to each other; receivers
+// drop anything that isn't signed with one of their keys.
+//
+// An RPC is also signed with the time it's sent at and a random nonce. It's
+// refused once it's older than maxRPCAge, and when its nonce was seen
+// already, so a captured RPC can't be replayed. Datagrams carry no such
+// freshness: a captured one can be replayed unchanged, and the sequencer
+// handles it as it does the duplicates its own retransmits cause.
+package peerauth
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/adarshsrinivasan/DS_S24/library/common"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// minSecretSize is the shortest secret accepted, in bytes.
+	minSecretSize = 16
+
+	// maxRPCAge bounds how long after it was sent an RPC is accepted, and
+	// how far ahead of this node's clock its timestamp may be. Nonces are
+	// remembered for as long.
+	maxRPCAge = 30 * time.Second
+
+	// nonceSize is the size of the nonce of an RPC, in bytes.
+	nonceSize = 16
+
+	keyIDMetadataKey     = "x-peer-auth-key-id"
+	macMetadataKey       = "x-peer-auth-mac"
+	timestampMetadataKey = "x-peer-auth-timestamp"
+	nonceMetadataKey     = "x-peer-auth-nonce"
+)
+
+// ErrUnauthenticated is returned for a message that isn't signed with a key
+// of the keyring.
+var ErrUnauthenticated = errors.New("unauthenticated peer message")
+
+// Key is a secret shared by the replicas of a cluster, named by ID so that the
+// receiver of a message knows which key signed it.
+type Key struct {
+	ID     string
+	Secret []byte
+}
+
+// Keyring holds the keys of a cluster. Messages are signed with the first key
+// and accepted under any of them, so a key is rotated without downtime by:
+// adding the new key last on every node, moving it first on every node, and
+// then dropping the old key.
+//
+// A nil Keyring signs nothing and accepts everything.
+type Keyring struct {
+	keys   []Key
+	nonces *nonceCache
+}
+
+// nonceCache holds the nonces of the RPCs accepted within the last maxRPCAge,
+// with the time they were sent at.
+type nonceCache struct {
+	mu         sync.Mutex
+	seen       map[string]time.Time
+	lastPruned time.Time
+}
+
+// check refuses an RPC sent at sentAt that is stale, or whose nonce was seen
+// already, and records the nonce otherwise.
+func (c *nonceCache) check(nonce string, sentAt, now time.Time) error {
+	if sentAt.Before(now.Add(-maxRPCAge)) || sentAt.After(now.Add(maxRPCAge)) {
+		return fmt.Errorf("%w. request sent at %v, more than %v from now", ErrUnauthenticated, sentAt, maxRPCAge)
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	if now.Sub(c.lastPruned) > maxRPCAge {
+		// Nonces sent before the window are refused as stale anyway.
+		for seenNonce, seenAt := range c.seen {
+			if seenAt.Before(now.Add(-maxRPCAge)) {
+				delete(c.seen, seenNonce)
+			}
+		}
+		c.lastPruned = now
+	}
+	if _, ok := c.seen[nonce]; ok {
+		return fmt.Errorf("%w. replayed nonce %s", ErrUnauthenticated, nonce)
+	}
+	c.seen[nonce] = sentAt
+	return nil
+}
+
+// ParseKeyring parses a keyring given as comma separated "keyID:secret"
+// pairs, the secrets base64 encoded, the signing key first. It returns nil
+// for an empty spec.
+func ParseKeyring(spec string) (*Keyring, error) {
+	if strings.TrimSpace(spec) == "" {
+		return nil, nil
+	}
+	keyring := &Keyring{nonces: &nonceCache{seen: map[string]time.Time{}}}
+	seen := map[string]bool{}
+	for _, pair := range common.SplitCSV(spec) {
+		id, encoded, found := strings.Cut(strings.TrimSpace(pair), ":")
+		if !found || id == "" {
+			return nil, fmt.Errorf("invalid key %q. expected keyID:secret", pair)
+		}
+		if seen[id] {
+			return nil, fmt.Errorf("duplicate key %s", id)
+		}
+		secret, err := base64.StdEncoding.DecodeString(encoded)
+		if err != nil {
+			return nil, fmt.Errorf("exception while decoding secret of key %s. %v", id, err)
+		}
+		if len(secret) < minSecretSize {
+			return nil, fmt.Errorf("secret of key %s is %d bytes. expected at least %d", id, len(secret), minSecretSize)
+		}
+		seen[id] = true
+		keyring.keys = append(keyring.keys, Key{ID: id, Secret: secret})
+	}
+	return keyring, nil
+}
+
+// KeyringFromEnv returns the keyring given in PEER_AUTH_KEYS, or nil if it's
+// not set.
+func KeyringFromEnv() (*Keyring, error) {
+	keyring, err := ParseKeyring(common.GetEnv(common.PeerAuthKeysEnv, ""))
+	if err != nil {
+		return nil, fmt.Errorf("exception while parsing %s. %v", common.PeerAuthKeysEnv, err)
+	}
+	return keyring, nil
+}
+
+// KeyIDs returns the IDs of the keys, the signing key first.
+func (k *Keyring) KeyIDs() []string {
+	if k == nil {
+		return nil
+	}
+	var ids []string
+	for _, key := range k.keys {
+		ids = append(ids, key.ID)
+	}
+	return ids
+}
+
+// Sign returns the ID of the signing key and the MAC of parts under it.
+func (k *Keyring) Sign(parts ...[]byte) (string, []byte) {
+	if k == nil {
+		return "", nil
+	}
+	key := k.keys[0]
+	return key.ID, computeMAC(key.Secret, parts)
+}
+
+// Verify checks that mac is the MAC of parts under the key keyID.
+func (k *Keyring) Verify(keyID string, mac []byte, parts ...[]byte) error {
+	if k == nil {
+		return nil
+	}
+	for _, key := range k.keys {
+		if key.ID != keyID {
+			continue
+		}
+		if !hmac.Equal(mac, computeMAC(key.Secret, parts)) {
+			return fmt.Errorf("%w. MAC mismatch under key %q", ErrUnauthenticated, keyID)
+		}
+		return nil
+	}
+	return fmt.Errorf("%w. unknown key %q", ErrUnauthenticated, keyID)
+}
+
+// computeMAC returns the HMAC-SHA256 of parts, each prefixed with its length
+// so that different splits of the same bytes don't collide.
+func computeMAC(secret []byte, parts [][]byte) []byte {
+	mac := hmac.New(sha256.New, secret)
+	var length [8]byte
+	for _, part := range parts {
+		binary.BigEndian.PutUint64(length[:], uint64(len(part)))
+		mac.Write(length[:])
+		mac.Write(part)
+	}
+	return mac.Sum(nil)
+}
+
+// marshalRequest returns the bytes of a request the MAC of an RPC covers. The
+// request is marshalled deterministically, so that the server gets the same
+// bytes out of the request it decoded; this assumes every node runs the same
+// build.
+func marshalRequest(req interface{}) ([]byte, error) {
+	msg, ok := req.(proto.Message)
+	if !ok {
+		return nil, fmt.Errorf("request %T is not a proto message", req)
+	}
+	data, err := proto.MarshalOptions{Deterministic: true}.Marshal(msg)
+	if err != nil {
+		return nil, fmt.Errorf("exception while marshalling request. %v", err)
+	}
+	return data, nil
+}
+
+// rpcParts returns what the MAC of an RPC covers: its method, timestamp,
+// nonce and request. The request of a stream isn't known when it's opened,
+// so it's nil for a stream.
+func rpcParts(method, timestamp, nonce string, request []byte) [][]byte {
+	return [][]byte{[]byte(method), []byte(timestamp), []byte(nonce), request}
+}
+
+// signRPC returns the metadata authenticating an RPC sent at now, as key and
+// value pairs.
+func (k *Keyring) signRPC(method string, request []byte, now time.Time) ([]string, error) {
+	nonceBytes := make([]byte, nonceSize)
+	if _, err := rand.Read(nonceBytes); err != nil {
+		return nil, fmt.Errorf("exception while generating nonce. %v", err)
+	}
+	nonce := base64.StdEncoding.EncodeToString(nonceBytes)
+	timestamp := strconv.FormatInt(now.UnixNano(), 10)
+	keyID, mac := k.Sign(rpcParts(method, timestamp, nonce, request)...)
+	return []string{
+		keyIDMetadataKey, keyID,
+		macMetadataKey, base64.StdEncoding.EncodeToString(mac),
+		timestampMetadataKey, timestamp,
+		nonceMetadataKey, nonce,
+	}, nil
+}
+
+// verifyRPC checks the metadata of an RPC received at now against its method
+// and request, and that it's neither stale nor replayed.
+func (k *Keyring) verifyRPC(ctx context.Context, method string, request []byte, now time.Time) error {
+	md, _ := metadata.FromIncomingContext(ctx)
+	values := map[string]string{}
+	for _, key := range []string{keyIDMetadataKey, macMetadataKey, timestampMetadataKey, nonceMetadataKey} {
+		if found := md.Get(key); len(found) == 1 {
+			values[key] = found[0]
+		}
+	}
+	if len(values) != 4 {
+		return fmt.Errorf("%w. request isn't signed", ErrUnauthenticated)
+	}
+	mac, err := base64.StdEncoding.DecodeString(values[macMetadataKey])
+	if err != nil {
+		return fmt.Errorf("%w. exception while decoding MAC. %v", ErrUnauthenticated, err)
+	}
+	parts := rpcParts(method, values[timestampMetadataKey], values[nonceMetadataKey], request)
+	if err := k.Verify(values[keyIDMetadataKey], mac, parts...); err != nil {
+		return err
+	}
+	sentAt, err := strconv.ParseInt(values[timestampMetadataKey], 10, 64)
+	if err != nil {
+		return fmt.Errorf("%w. exception while parsing timestamp. %v", ErrUnauthenticated, err)
+	}
+	return k.nonces.check(values[nonceMetadataKey], time.Unix(0, sentAt), now)
+}
+
+// UnaryClientInterceptor signs the requests of a gRPC client. Responses come
+// back on the connection the client opened and aren't signed.
+func (k *Keyring) UnaryClientInterceptor() grpc.UnaryClientInterceptor {
+	return func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, invoker grpc.UnaryInvoker, opts ...grpc.CallOption) error {
+		if k == nil {
+			return invoker(ctx, method, req, reply, cc, opts...)
+		}
+		request, err := marshalRequest(req)
+		if err != nil {
+			return status.Errorf(codes.Internal, "exception while signing %s. %v", method, err)
+		}
+		pairs, err := k.signRPC(method, request, time.Now())
+		if err != nil {
+			return status.Errorf(codes.Internal, "exception while signing %s. %v", method, err)
+		}
+		return invoker(metadata.AppendToOutgoingContext(ctx, pairs...), method, req, reply, cc, opts...)
+	}
+}
+
+// UnaryServerInterceptor refuses the requests of a gRPC server that aren't
+// signed with a key of the keyring, or are stale or replayed.
+func (k *Keyring) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if k == nil {
+			return handler(ctx, req)
+		}
+		request, err := marshalRequest(req)
+		if err == nil {
+			err = k.verifyRPC(ctx, info.FullMethod, request, time.Now())
+		}
+		if err != nil {
+			log.Errorf("UnaryServerInterceptor: Refusing %s. %v\n", info.FullMethod, err)
+			return nil, status.Error(codes.Unauthenticated, err.Error())
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamClientInterceptor signs the streams a gRPC client opens. The MAC
+// covers the method, not the messages sent on the stream.
+func (k *Keyring) StreamClientInterceptor() grpc.StreamClientInterceptor {
+	return func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, streamer grpc.Streamer, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		if k == nil {
+			return streamer(ctx, desc, cc, method, opts...)
+		}
+		pairs, err := k.signRPC(method, nil, time.Now())
+		if err != nil {
+			return nil, status.Errorf(codes.Internal, "exception while signing %s. %v", method, err)
+		}
+		return streamer(metadata.AppendToOutgoingContext(ctx, pairs...), desc, cc, method, opts...)
+	}
+}
+
+// StreamServerInterceptor refuses the streams of a gRPC server that aren't
+// opened with the signature of a key of the keyring, or are stale or
+// replayed.
+func (k *Keyring) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, ss grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if k == nil {
+			return handler(srv, ss)
+		}
+		if err := k.verifyRPC(ss.Context(), info.FullMethod, nil, time.Now()); err != nil {
+			log.Errorf("StreamServerInterceptor: Refusing %s. %v\n", info.FullMethod, err)
+			return status.Error(codes.Unauthenticated, err.Error())
+		}
+		return handler(srv, ss)
+	}
+}
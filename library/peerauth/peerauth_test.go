@@ -0,0 +1,245 @@
+package peerauth
+
+import (
+	"context"
+	"encoding/base64"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/wrapperspb"
+)
+
+const testMethod = "/test.Service/Method"
+
+// secret returns a base64 encoded secret of size bytes.
+func secret(size int) string {
+	return secretOf("s", size)
+}
+
+// secretOf returns a base64 encoded secret of size bytes filled with fill.
+func secretOf(fill string, size int) string {
+	return base64.StdEncoding.EncodeToString([]byte(strings.Repeat(fill, size)))
+}
+
+func mustParseKeyring(t *testing.T, spec string) *Keyring {
+	t.Helper()
+	keyring, err := ParseKeyring(spec)
+	if err != nil {
+		t.Fatalf("ParseKeyring(%q): %v", spec, err)
+	}
+	return keyring
+}
+
+func TestParseKeyring(t *testing.T) {
+	tests := []struct {
+		name    string
+		spec    string
+		wantIDs []string
+		wantErr bool
+	}{
+		{name: "empty", spec: " "},
+		{name: "one key", spec: "k1:" + secret(minSecretSize), wantIDs: []string{"k1"}},
+		{name: "signing key first", spec: "k2:" + secret(32) + ",k1:" + secret(32), wantIDs: []string{"k2", "k1"}},
+		{name: "missing secret", spec: "k1", wantErr: true},
+		{name: "missing ID", spec: ":" + secret(32), wantErr: true},
+		{name: "duplicate ID", spec: "k1:" + secret(32) + ",k1:" + secret(32), wantErr: true},
+		{name: "short secret", spec: "k1:" + secret(minSecretSize-1), wantErr: true},
+		{name: "bad base64", spec: "k1:not base64!", wantErr: true},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			keyring, err := ParseKeyring(test.spec)
+			if test.wantErr {
+				if err == nil {
+					t.Fatalf("ParseKeyring(%q) = %v; want an error", test.spec, keyring.KeyIDs())
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("ParseKeyring(%q): %v", test.spec, err)
+			}
+			if got := strings.Join(keyring.KeyIDs(), ","); got != strings.Join(test.wantIDs, ",") {
+				t.Fatalf("ParseKeyring(%q) keys %s; want %s", test.spec, got, strings.Join(test.wantIDs, ","))
+			}
+		})
+	}
+}
+
+func TestSignVerifyAcrossRotation(t *testing.T) {
+	oldKey, newKey := "old:"+secretOf("o", 32), "new:"+secretOf("n", 32)
+	// The steps of a rotation, as described on Keyring.
+	steps := []*Keyring{
+		mustParseKeyring(t, oldKey),
+		mustParseKeyring(t, oldKey+","+newKey),
+		mustParseKeyring(t, newKey+","+oldKey),
+		mustParseKeyring(t, newKey),
+	}
+	payload := []byte("payload")
+	// Nodes are at most one step apart during a rotation, and each accepts
+	// what the other signs.
+	for i := 0; i+1 < len(steps); i++ {
+		for _, pair := range [][2]*Keyring{{steps[i], steps[i+1]}, {steps[i+1], steps[i]}} {
+			keyID, mac := pair[0].Sign(payload)
+			if err := pair[1].Verify(keyID, mac, payload); err != nil {
+				t.Fatalf("step %d: %v signed with %s rejected by %v: %v", i, pair[0].KeyIDs(), keyID, pair[1].KeyIDs(), err)
+			}
+		}
+	}
+	keyID, mac := steps[0].Sign(payload)
+	if err := steps[3].Verify(keyID, mac, payload); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("Verify under a dropped key: got %v; want %v", err, ErrUnauthenticated)
+	}
+}
+
+func TestVerifyRejectsTamperedPayload(t *testing.T) {
+	keyring := mustParseKeyring(t, "k1:"+secret(32))
+	keyID, mac := keyring.Sign([]byte("ab"), []byte("c"))
+	if err := keyring.Verify(keyID, mac, []byte("ab"), []byte("d")); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("Verify of a tampered part: got %v; want %v", err, ErrUnauthenticated)
+	}
+	if err := keyring.Verify(keyID, mac, []byte("a"), []byte("bc")); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("Verify of the same bytes split differently: got %v; want %v", err, ErrUnauthenticated)
+	}
+	mac[0] ^= 1
+	if err := keyring.Verify(keyID, mac, []byte("ab"), []byte("c")); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("Verify of a tampered MAC: got %v; want %v", err, ErrUnauthenticated)
+	}
+}
+
+func TestVerifyRejectsUnknownKey(t *testing.T) {
+	keyring := mustParseKeyring(t, "k1:"+secret(32))
+	other := mustParseKeyring(t, "k2:"+secret(32))
+	keyID, mac := other.Sign([]byte("payload"))
+	if err := keyring.Verify(keyID, mac, []byte("payload")); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("Verify under an unknown key: got %v; want %v", err, ErrUnauthenticated)
+	}
+}
+
+// callThrough signs req with client, if not nil, and passes it to the server
+// interceptor of server. It returns the error the server answers with.
+func callThrough(t *testing.T, client, server *Keyring, req *wrapperspb.StringValue) error {
+	t.Helper()
+	ctx := context.Background()
+	if client != nil {
+		invoker := func(ctx context.Context, method string, req, reply interface{}, cc *grpc.ClientConn, opts ...grpc.CallOption) error {
+			md, _ := metadata.FromOutgoingContext(ctx)
+			ctx = metadata.NewIncomingContext(context.Background(), md)
+			return callServer(ctx, server, req)
+		}
+		return client.UnaryClientInterceptor()(ctx, testMethod, req, nil, nil, invoker)
+	}
+	return callServer(ctx, server, req)
+}
+
+func callServer(ctx context.Context, server *Keyring, req interface{}) error {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return req, nil
+	}
+	_, err := server.UnaryServerInterceptor()(ctx, req, &grpc.UnaryServerInfo{FullMethod: testMethod}, handler)
+	return err
+}
+
+// signedContext returns the incoming context of an RPC to testMethod signed by
+// keyring at sentAt.
+func signedContext(t *testing.T, keyring *Keyring, request []byte, sentAt time.Time) context.Context {
+	t.Helper()
+	pairs, err := keyring.signRPC(testMethod, request, sentAt)
+	if err != nil {
+		t.Fatalf("signRPC: %v", err)
+	}
+	return metadata.NewIncomingContext(context.Background(), metadata.Pairs(pairs...))
+}
+
+func TestServerInterceptor(t *testing.T) {
+	keyring := mustParseKeyring(t, "k1:"+secret(32))
+	if err := callThrough(t, keyring, keyring, wrapperspb.String("request")); err != nil {
+		t.Fatalf("signed request: %v", err)
+	}
+	if err := callThrough(t, nil, keyring, wrapperspb.String("request")); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("unsigned request: got %v; want %v", err, codes.Unauthenticated)
+	}
+	other := mustParseKeyring(t, "k2:"+secret(32))
+	if err := callThrough(t, other, keyring, wrapperspb.String("request")); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("request signed with an unknown key: got %v; want %v", err, codes.Unauthenticated)
+	}
+
+	// A request altered after it was signed.
+	ctx := signedContext(t, keyring, []byte("request"), time.Now())
+	if err := callServer(ctx, keyring, wrapperspb.String("tampered")); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("tampered request: got %v; want %v", err, codes.Unauthenticated)
+	}
+
+	if err := callThrough(t, nil, nil, wrapperspb.String("request")); err != nil {
+		t.Fatalf("unsigned request without a keyring: %v", err)
+	}
+}
+
+func TestVerifyRPCRejectsStaleAndReplayedRequests(t *testing.T) {
+	keyring := mustParseKeyring(t, "k1:"+secret(32))
+	now := time.Now()
+	request := []byte("request")
+
+	ctx := signedContext(t, keyring, request, now)
+	if err := keyring.verifyRPC(ctx, testMethod, request, now); err != nil {
+		t.Fatalf("fresh request: %v", err)
+	}
+	if err := keyring.verifyRPC(ctx, testMethod, request, now.Add(time.Second)); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("replayed request: got %v; want %v", err, ErrUnauthenticated)
+	}
+
+	stale := signedContext(t, keyring, request, now.Add(-2*maxRPCAge))
+	if err := keyring.verifyRPC(stale, testMethod, request, now); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("stale request: got %v; want %v", err, ErrUnauthenticated)
+	}
+	future := signedContext(t, keyring, request, now.Add(2*maxRPCAge))
+	if err := keyring.verifyRPC(future, testMethod, request, now); !errors.Is(err, ErrUnauthenticated) {
+		t.Fatalf("request from the future: got %v; want %v", err, ErrUnauthenticated)
+	}
+
+	// Nonces are forgotten once the requests carrying them are stale.
+	later := now.Add(2 * maxRPCAge)
+	if err := keyring.verifyRPC(signedContext(t, keyring, request, later), testMethod, request, later); err != nil {
+		t.Fatalf("fresh request later: %v", err)
+	}
+	if len(keyring.nonces.seen) != 1 {
+		t.Fatalf("%d nonces remembered; want 1", len(keyring.nonces.seen))
+	}
+}
+
+// serverStream is a grpc.ServerStream carrying a context, for the stream
+// interceptor.
+type serverStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *serverStream) Context() context.Context {
+	return s.ctx
+}
+
+func TestStreamInterceptors(t *testing.T) {
+	keyring := mustParseKeyring(t, "k1:"+secret(32))
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		return nil
+	}
+	info := &grpc.StreamServerInfo{FullMethod: testMethod, IsServerStream: true}
+	streamer := func(ctx context.Context, desc *grpc.StreamDesc, cc *grpc.ClientConn, method string, opts ...grpc.CallOption) (grpc.ClientStream, error) {
+		md, _ := metadata.FromOutgoingContext(ctx)
+		stream := &serverStream{ctx: metadata.NewIncomingContext(context.Background(), md)}
+		return nil, keyring.StreamServerInterceptor()(nil, stream, info, handler)
+	}
+
+	if _, err := keyring.StreamClientInterceptor()(context.Background(), &grpc.StreamDesc{ServerStreams: true}, nil, testMethod, streamer); err != nil {
+		t.Fatalf("signed stream: %v", err)
+	}
+	unsigned := &serverStream{ctx: context.Background()}
+	if err := keyring.StreamServerInterceptor()(nil, unsigned, info, handler); status.Code(err) != codes.Unauthenticated {
+		t.Fatalf("unsigned stream: got %v; want %v", err, codes.Unauthenticated)
+	}
+}
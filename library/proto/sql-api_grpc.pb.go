@@ -60,6 +60,8 @@ type SQLServiceClient interface {
 	DeleteTransactionsByCartID(ctx context.Context, in *DeleteTransactionsByCartIDRequest, opts ...grpc.CallOption) (*DeleteTransactionsByCartIDResponse, error)
 	DeleteTransactionsByBuyerID(ctx context.Context, in *DeleteTransactionsByBuyerIDRequest, opts ...grpc.CallOption) (*DeleteTransactionsByBuyerIDResponse, error)
 	DeleteTransactionsBySellerID(ctx context.Context, in *DeleteTransactionsBySellerIDRequest, opts ...grpc.CallOption) (*DeleteTransactionsBySellerIDResponse, error)
+	// StateTransfer APIs
+	StateTransfer(ctx context.Context, in *StateTransferRequest, opts ...grpc.CallOption) (SQLService_StateTransferClient, error)
 }
 
 type sQLServiceClient struct {
@@ -367,6 +369,38 @@ func (c *sQLServiceClient) DeleteTransactionsBySellerID(ctx context.Context, in
 	return out, nil
 }
 
+func (c *sQLServiceClient) StateTransfer(ctx context.Context, in *StateTransferRequest, opts ...grpc.CallOption) (SQLService_StateTransferClient, error) {
+	stream, err := c.cc.NewStream(ctx, &SQLService_ServiceDesc.Streams[0], "/proto.SQLService/StateTransfer", opts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &sQLServiceStateTransferClient{stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+type SQLService_StateTransferClient interface {
+	Recv() (*StateTransferChunk, error)
+	grpc.ClientStream
+}
+
+type sQLServiceStateTransferClient struct {
+	grpc.ClientStream
+}
+
+func (x *sQLServiceStateTransferClient) Recv() (*StateTransferChunk, error) {
+	m := new(StateTransferChunk)
+	if err := x.ClientStream.RecvMsg(m); err != nil {
+		return nil, err
+	}
+	return m, nil
+}
+
 // SQLServiceServer is the server API for SQLService service.
 // All implementations must embed UnimplementedSQLServiceServer
 // for forward compatibility
@@ -409,6 +443,8 @@ type SQLServiceServer interface {
 	DeleteTransactionsByCartID(context.Context, *DeleteTransactionsByCartIDRequest) (*DeleteTransactionsByCartIDResponse, error)
 	DeleteTransactionsByBuyerID(context.Context, *DeleteTransactionsByBuyerIDRequest) (*DeleteTransactionsByBuyerIDResponse, error)
 	DeleteTransactionsBySellerID(context.Context, *DeleteTransactionsBySellerIDRequest) (*DeleteTransactionsBySellerIDResponse, error)
+	// StateTransfer APIs
+	StateTransfer(*StateTransferRequest, SQLService_StateTransferServer) error
 	mustEmbedUnimplementedSQLServiceServer()
 }
 
@@ -515,6 +551,9 @@ func (UnimplementedSQLServiceServer) DeleteTransactionsByBuyerID(context.Context
 func (UnimplementedSQLServiceServer) DeleteTransactionsBySellerID(context.Context, *DeleteTransactionsBySellerIDRequest) (*DeleteTransactionsBySellerIDResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteTransactionsBySellerID not implemented")
 }
+func (UnimplementedSQLServiceServer) StateTransfer(*StateTransferRequest, SQLService_StateTransferServer) error {
+	return status.Errorf(codes.Unimplemented, "method StateTransfer not implemented")
+}
 func (UnimplementedSQLServiceServer) mustEmbedUnimplementedSQLServiceServer() {}
 
 // UnsafeSQLServiceServer may be embedded to opt out of forward compatibility for this service.
@@ -1122,6 +1161,27 @@ func _SQLService_DeleteTransactionsBySellerID_Handler(srv interface{}, ctx conte
 	return interceptor(ctx, in, info, handler)
 }
 
+func _SQLService_StateTransfer_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(StateTransferRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(SQLServiceServer).StateTransfer(m, &sQLServiceStateTransferServer{stream})
+}
+
+type SQLService_StateTransferServer interface {
+	Send(*StateTransferChunk) error
+	grpc.ServerStream
+}
+
+type sQLServiceStateTransferServer struct {
+	grpc.ServerStream
+}
+
+func (x *sQLServiceStateTransferServer) Send(m *StateTransferChunk) error {
+	return x.ServerStream.SendMsg(m)
+}
+
 // SQLService_ServiceDesc is the grpc.ServiceDesc for SQLService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -1262,6 +1322,12 @@ var SQLService_ServiceDesc = grpc.ServiceDesc{
 			Handler:    _SQLService_DeleteTransactionsBySellerID_Handler,
 		},
 	},
-	Streams:  []grpc.StreamDesc{},
+	Streams: []grpc.StreamDesc{
+		{
+			StreamName:    "StateTransfer",
+			Handler:       _SQLService_StateTransfer_Handler,
+			ServerStreams: true,
+		},
+	},
 	Metadata: "sql-api.proto",
 }
@@ -0,0 +1,586 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.28.1
+// 	protoc        v3.21.12
+// source: totem.proto
+
+package proto
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	reflect "reflect"
+	sync "sync"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// TotemMessage is a message of the sequencer atomic broadcast.
+type TotemMessage struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	ID                 string   `protobuf:"bytes,1,opt,name=ID,proto3" json:"ID,omitempty"`
+	MsgType            int32    `protobuf:"varint,2,opt,name=msgType,proto3" json:"msgType,omitempty"`
+	OpsType            int32    `protobuf:"varint,3,opt,name=opsType,proto3" json:"opsType,omitempty"`
+	Payload            []byte   `protobuf:"bytes,4,opt,name=payload,proto3" json:"payload,omitempty"`
+	RequestNodeName    string   `protobuf:"bytes,5,opt,name=requestNodeName,proto3" json:"requestNodeName,omitempty"`
+	SequenceNodeName   string   `protobuf:"bytes,6,opt,name=sequenceNodeName,proto3" json:"sequenceNodeName,omitempty"`
+	RetransmitNodeName string   `protobuf:"bytes,7,opt,name=retransmitNodeName,proto3" json:"retransmitNodeName,omitempty"`
+	LocalSeqNum        int32    `protobuf:"varint,8,opt,name=localSeqNum,proto3" json:"localSeqNum,omitempty"`
+	GlobalSeqNum       int32    `protobuf:"varint,9,opt,name=globalSeqNum,proto3" json:"globalSeqNum,omitempty"`
+	AckType            int32    `protobuf:"varint,10,opt,name=ackType,proto3" json:"ackType,omitempty"`
+	ViewID             int32    `protobuf:"varint,11,opt,name=viewID,proto3" json:"viewID,omitempty"`
+	Members            []string `protobuf:"bytes,12,rep,name=members,proto3" json:"members,omitempty"`
+	SenderNodeName     string   `protobuf:"bytes,13,opt,name=senderNodeName,proto3" json:"senderNodeName,omitempty"`
+	Incarnation        int64    `protobuf:"varint,14,opt,name=incarnation,proto3" json:"incarnation,omitempty"`
+	LogBaseSeqNum      int32    `protobuf:"varint,15,opt,name=logBaseSeqNum,proto3" json:"logBaseSeqNum,omitempty"`
+}
+
+func (x *TotemMessage) Reset() {
+	*x = TotemMessage{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_totem_proto_msgTypes[0]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TotemMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TotemMessage) ProtoMessage() {}
+
+func (x *TotemMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_totem_proto_msgTypes[0]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TotemMessage.ProtoReflect.Descriptor instead.
+func (*TotemMessage) Descriptor() ([]byte, []int) {
+	return file_totem_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *TotemMessage) GetID() string {
+	if x != nil {
+		return x.ID
+	}
+	return ""
+}
+
+func (x *TotemMessage) GetMsgType() int32 {
+	if x != nil {
+		return x.MsgType
+	}
+	return 0
+}
+
+func (x *TotemMessage) GetOpsType() int32 {
+	if x != nil {
+		return x.OpsType
+	}
+	return 0
+}
+
+func (x *TotemMessage) GetPayload() []byte {
+	if x != nil {
+		return x.Payload
+	}
+	return nil
+}
+
+func (x *TotemMessage) GetRequestNodeName() string {
+	if x != nil {
+		return x.RequestNodeName
+	}
+	return ""
+}
+
+func (x *TotemMessage) GetSequenceNodeName() string {
+	if x != nil {
+		return x.SequenceNodeName
+	}
+	return ""
+}
+
+func (x *TotemMessage) GetRetransmitNodeName() string {
+	if x != nil {
+		return x.RetransmitNodeName
+	}
+	return ""
+}
+
+func (x *TotemMessage) GetLocalSeqNum() int32 {
+	if x != nil {
+		return x.LocalSeqNum
+	}
+	return 0
+}
+
+func (x *TotemMessage) GetGlobalSeqNum() int32 {
+	if x != nil {
+		return x.GlobalSeqNum
+	}
+	return 0
+}
+
+func (x *TotemMessage) GetAckType() int32 {
+	if x != nil {
+		return x.AckType
+	}
+	return 0
+}
+
+func (x *TotemMessage) GetViewID() int32 {
+	if x != nil {
+		return x.ViewID
+	}
+	return 0
+}
+
+func (x *TotemMessage) GetMembers() []string {
+	if x != nil {
+		return x.Members
+	}
+	return nil
+}
+
+func (x *TotemMessage) GetSenderNodeName() string {
+	if x != nil {
+		return x.SenderNodeName
+	}
+	return ""
+}
+
+func (x *TotemMessage) GetIncarnation() int64 {
+	if x != nil {
+		return x.Incarnation
+	}
+	return 0
+}
+
+func (x *TotemMessage) GetLogBaseSeqNum() int32 {
+	if x != nil {
+		return x.LogBaseSeqNum
+	}
+	return 0
+}
+
+// TotemEnvelope is a single datagram of the atomic broadcast. A marshalled
+// TotemMessage that doesn't fit in one datagram is split into fragmentCount
+// envelopes sharing messageID, which the receiver reassembles in
+// fragmentIndex order. Receivers drop envelopes of any other version.
+// When the cluster has a keyring, mac is the HMAC of the other fields under
+// the key keyID, and receivers drop envelopes it doesn't match.
+type TotemEnvelope struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	Version        uint32 `protobuf:"varint,1,opt,name=version,proto3" json:"version,omitempty"`
+	SenderNodeName string `protobuf:"bytes,2,opt,name=senderNodeName,proto3" json:"senderNodeName,omitempty"`
+	MessageID      uint64 `protobuf:"varint,3,opt,name=messageID,proto3" json:"messageID,omitempty"`
+	FragmentIndex  uint32 `protobuf:"varint,4,opt,name=fragmentIndex,proto3" json:"fragmentIndex,omitempty"`
+	FragmentCount  uint32 `protobuf:"varint,5,opt,name=fragmentCount,proto3" json:"fragmentCount,omitempty"`
+	Data           []byte `protobuf:"bytes,6,opt,name=data,proto3" json:"data,omitempty"`
+	KeyID          string `protobuf:"bytes,7,opt,name=keyID,proto3" json:"keyID,omitempty"`
+	Mac            []byte `protobuf:"bytes,8,opt,name=mac,proto3" json:"mac,omitempty"`
+}
+
+func (x *TotemEnvelope) Reset() {
+	*x = TotemEnvelope{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_totem_proto_msgTypes[1]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *TotemEnvelope) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TotemEnvelope) ProtoMessage() {}
+
+func (x *TotemEnvelope) ProtoReflect() protoreflect.Message {
+	mi := &file_totem_proto_msgTypes[1]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TotemEnvelope.ProtoReflect.Descriptor instead.
+func (*TotemEnvelope) Descriptor() ([]byte, []int) {
+	return file_totem_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *TotemEnvelope) GetVersion() uint32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *TotemEnvelope) GetSenderNodeName() string {
+	if x != nil {
+		return x.SenderNodeName
+	}
+	return ""
+}
+
+func (x *TotemEnvelope) GetMessageID() uint64 {
+	if x != nil {
+		return x.MessageID
+	}
+	return 0
+}
+
+func (x *TotemEnvelope) GetFragmentIndex() uint32 {
+	if x != nil {
+		return x.FragmentIndex
+	}
+	return 0
+}
+
+func (x *TotemEnvelope) GetFragmentCount() uint32 {
+	if x != nil {
+		return x.FragmentCount
+	}
+	return 0
+}
+
+func (x *TotemEnvelope) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *TotemEnvelope) GetKeyID() string {
+	if x != nil {
+		return x.KeyID
+	}
+	return ""
+}
+
+func (x *TotemEnvelope) GetMac() []byte {
+	if x != nil {
+		return x.Mac
+	}
+	return nil
+}
+
+// StateTransferRequest asks a replica for a snapshot of its database. The
+// replica refuses if it has delivered fewer than minGlobalSeqNum messages.
+type StateTransferRequest struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	RequestNodeName string `protobuf:"bytes,1,opt,name=requestNodeName,proto3" json:"requestNodeName,omitempty"`
+	MinGlobalSeqNum int32  `protobuf:"varint,2,opt,name=minGlobalSeqNum,proto3" json:"minGlobalSeqNum,omitempty"`
+}
+
+func (x *StateTransferRequest) Reset() {
+	*x = StateTransferRequest{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_totem_proto_msgTypes[2]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *StateTransferRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*StateTransferRequest) ProtoMessage() {}
+
+func (x *StateTransferRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_totem_proto_msgTypes[2]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use StateTransferRequest.ProtoReflect.Descriptor instead.
+func (*StateTransferRequest) Descriptor() ([]byte, []int) {
+	return file_totem_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *StateTransferRequest) GetRequestNodeName() string {
+	if x != nil {
+		return x.RequestNodeName
+	}
+	return ""
+}
+
+func (x *StateTransferRequest) GetMinGlobalSeqNum() int32 {
+	if x != nil {
+		return x.MinGlobalSeqNum
+	}
+	return 0
+}
+
+// RequestWatermark is the last request delivered from an incarnation of a
+// replica.
+type RequestWatermark struct {
+	state         protoimpl.MessageState
+	sizeCache     protoimpl.SizeCache
+	unknownFields protoimpl.UnknownFields
+
+	NodeName    string `protobuf:"bytes,1,opt,name=nodeName,proto3" json:"nodeName,omitempty"`
+	Incarnation int64  `protobuf:"varint,2,opt,name=incarnation,proto3" json:"incarnation,omitempty"`
+	LocalSeqNum int32  `protobuf:"varint,3,opt,name=localSeqNum,proto3" json:"localSeqNum,omitempty"`
+}
+
+func (x *RequestWatermark) Reset() {
+	*x = RequestWatermark{}
+	if protoimpl.UnsafeEnabled {
+		mi := &file_totem_proto_msgTypes[3]
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		ms.StoreMessageInfo(mi)
+	}
+}
+
+func (x *RequestWatermark) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RequestWatermark) ProtoMessage() {}
+
+func (x *RequestWatermark) ProtoReflect() protoreflect.Message {
+	mi := &file_totem_proto_msgTypes[3]
+	if protoimpl.UnsafeEnabled && x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RequestWatermark.ProtoReflect.Descriptor instead.
+func (*RequestWatermark) Descriptor() ([]byte, []int) {
+	return file_totem_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *RequestWatermark) GetNodeName() string {
+	if x != nil {
+		return x.NodeName
+	}
+	return ""
+}
+
+func (x *RequestWatermark) GetIncarnation() int64 {
+	if x != nil {
+		return x.Incarnation
+	}
+	return 0
+}
+
+func (x *RequestWatermark) GetLocalSeqNum() int32 {
+	if x != nil {
+		return x.LocalSeqNum
+	}
+	return 0
+}
+
+var File_totem_proto protoreflect.FileDescriptor
+
+var file_totem_proto_rawDesc = []byte{
+	0x0a, 0x0b, 0x74, 0x6f, 0x74, 0x65, 0x6d, 0x2e, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x12, 0x05, 0x70,
+	0x72, 0x6f, 0x74, 0x6f, 0x22, 0xf4, 0x03, 0x0a, 0x0c, 0x54, 0x6f, 0x74, 0x65, 0x6d, 0x4d, 0x65,
+	0x73, 0x73, 0x61, 0x67, 0x65, 0x12, 0x0e, 0x0a, 0x02, 0x49, 0x44, 0x18, 0x01, 0x20, 0x01, 0x28,
+	0x09, 0x52, 0x02, 0x49, 0x44, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x05, 0x52, 0x07, 0x6d, 0x73, 0x67, 0x54, 0x79, 0x70, 0x65, 0x12,
+	0x18, 0x0a, 0x07, 0x6f, 0x70, 0x73, 0x54, 0x79, 0x70, 0x65, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x07, 0x6f, 0x70, 0x73, 0x54, 0x79, 0x70, 0x65, 0x12, 0x18, 0x0a, 0x07, 0x70, 0x61, 0x79,
+	0x6c, 0x6f, 0x61, 0x64, 0x18, 0x04, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x07, 0x70, 0x61, 0x79, 0x6c,
+	0x6f, 0x61, 0x64, 0x12, 0x28, 0x0a, 0x0f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4e, 0x6f,
+	0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x05, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x72, 0x65,
+	0x71, 0x75, 0x65, 0x73, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2a, 0x0a,
+	0x10, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63, 0x65, 0x4e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d,
+	0x65, 0x18, 0x06, 0x20, 0x01, 0x28, 0x09, 0x52, 0x10, 0x73, 0x65, 0x71, 0x75, 0x65, 0x6e, 0x63,
+	0x65, 0x4e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x2e, 0x0a, 0x12, 0x72, 0x65, 0x74,
+	0x72, 0x61, 0x6e, 0x73, 0x6d, 0x69, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x18,
+	0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x12, 0x72, 0x65, 0x74, 0x72, 0x61, 0x6e, 0x73, 0x6d, 0x69,
+	0x74, 0x4e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x6c, 0x6f, 0x63,
+	0x61, 0x6c, 0x53, 0x65, 0x71, 0x4e, 0x75, 0x6d, 0x18, 0x08, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b,
+	0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x53, 0x65, 0x71, 0x4e, 0x75, 0x6d, 0x12, 0x22, 0x0a, 0x0c, 0x67,
+	0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x53, 0x65, 0x71, 0x4e, 0x75, 0x6d, 0x18, 0x09, 0x20, 0x01, 0x28,
+	0x05, 0x52, 0x0c, 0x67, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x53, 0x65, 0x71, 0x4e, 0x75, 0x6d, 0x12,
+	0x18, 0x0a, 0x07, 0x61, 0x63, 0x6b, 0x54, 0x79, 0x70, 0x65, 0x18, 0x0a, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x07, 0x61, 0x63, 0x6b, 0x54, 0x79, 0x70, 0x65, 0x12, 0x16, 0x0a, 0x06, 0x76, 0x69, 0x65,
+	0x77, 0x49, 0x44, 0x18, 0x0b, 0x20, 0x01, 0x28, 0x05, 0x52, 0x06, 0x76, 0x69, 0x65, 0x77, 0x49,
+	0x44, 0x12, 0x18, 0x0a, 0x07, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x18, 0x0c, 0x20, 0x03,
+	0x28, 0x09, 0x52, 0x07, 0x6d, 0x65, 0x6d, 0x62, 0x65, 0x72, 0x73, 0x12, 0x26, 0x0a, 0x0e, 0x73,
+	0x65, 0x6e, 0x64, 0x65, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x0d, 0x20,
+	0x01, 0x28, 0x09, 0x52, 0x0e, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x4e,
+	0x61, 0x6d, 0x65, 0x12, 0x20, 0x0a, 0x0b, 0x69, 0x6e, 0x63, 0x61, 0x72, 0x6e, 0x61, 0x74, 0x69,
+	0x6f, 0x6e, 0x18, 0x0e, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x69, 0x6e, 0x63, 0x61, 0x72, 0x6e,
+	0x61, 0x74, 0x69, 0x6f, 0x6e, 0x12, 0x24, 0x0a, 0x0d, 0x6c, 0x6f, 0x67, 0x42, 0x61, 0x73, 0x65,
+	0x53, 0x65, 0x71, 0x4e, 0x75, 0x6d, 0x18, 0x0f, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0d, 0x6c, 0x6f,
+	0x67, 0x42, 0x61, 0x73, 0x65, 0x53, 0x65, 0x71, 0x4e, 0x75, 0x6d, 0x22, 0xf7, 0x01, 0x0a, 0x0d,
+	0x54, 0x6f, 0x74, 0x65, 0x6d, 0x45, 0x6e, 0x76, 0x65, 0x6c, 0x6f, 0x70, 0x65, 0x12, 0x18, 0x0a,
+	0x07, 0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x18, 0x01, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x07,
+	0x76, 0x65, 0x72, 0x73, 0x69, 0x6f, 0x6e, 0x12, 0x26, 0x0a, 0x0e, 0x73, 0x65, 0x6e, 0x64, 0x65,
+	0x72, 0x4e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x18, 0x02, 0x20, 0x01, 0x28, 0x09, 0x52,
+	0x0e, 0x73, 0x65, 0x6e, 0x64, 0x65, 0x72, 0x4e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12,
+	0x1c, 0x0a, 0x09, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44, 0x18, 0x03, 0x20, 0x01,
+	0x28, 0x04, 0x52, 0x09, 0x6d, 0x65, 0x73, 0x73, 0x61, 0x67, 0x65, 0x49, 0x44, 0x12, 0x24, 0x0a,
+	0x0d, 0x66, 0x72, 0x61, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x6e, 0x64, 0x65, 0x78, 0x18, 0x04,
+	0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x66, 0x72, 0x61, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x49, 0x6e,
+	0x64, 0x65, 0x78, 0x12, 0x24, 0x0a, 0x0d, 0x66, 0x72, 0x61, 0x67, 0x6d, 0x65, 0x6e, 0x74, 0x43,
+	0x6f, 0x75, 0x6e, 0x74, 0x18, 0x05, 0x20, 0x01, 0x28, 0x0d, 0x52, 0x0d, 0x66, 0x72, 0x61, 0x67,
+	0x6d, 0x65, 0x6e, 0x74, 0x43, 0x6f, 0x75, 0x6e, 0x74, 0x12, 0x12, 0x0a, 0x04, 0x64, 0x61, 0x74,
+	0x61, 0x18, 0x06, 0x20, 0x01, 0x28, 0x0c, 0x52, 0x04, 0x64, 0x61, 0x74, 0x61, 0x12, 0x14, 0x0a,
+	0x05, 0x6b, 0x65, 0x79, 0x49, 0x44, 0x18, 0x07, 0x20, 0x01, 0x28, 0x09, 0x52, 0x05, 0x6b, 0x65,
+	0x79, 0x49, 0x44, 0x12, 0x10, 0x0a, 0x03, 0x6d, 0x61, 0x63, 0x18, 0x08, 0x20, 0x01, 0x28, 0x0c,
+	0x52, 0x03, 0x6d, 0x61, 0x63, 0x22, 0x6a, 0x0a, 0x14, 0x53, 0x74, 0x61, 0x74, 0x65, 0x54, 0x72,
+	0x61, 0x6e, 0x73, 0x66, 0x65, 0x72, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x12, 0x28, 0x0a,
+	0x0f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65,
+	0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x0f, 0x72, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x4e,
+	0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d, 0x65, 0x12, 0x28, 0x0a, 0x0f, 0x6d, 0x69, 0x6e, 0x47, 0x6c,
+	0x6f, 0x62, 0x61, 0x6c, 0x53, 0x65, 0x71, 0x4e, 0x75, 0x6d, 0x18, 0x02, 0x20, 0x01, 0x28, 0x05,
+	0x52, 0x0f, 0x6d, 0x69, 0x6e, 0x47, 0x6c, 0x6f, 0x62, 0x61, 0x6c, 0x53, 0x65, 0x71, 0x4e, 0x75,
+	0x6d, 0x22, 0x72, 0x0a, 0x10, 0x52, 0x65, 0x71, 0x75, 0x65, 0x73, 0x74, 0x57, 0x61, 0x74, 0x65,
+	0x72, 0x6d, 0x61, 0x72, 0x6b, 0x12, 0x1a, 0x0a, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d,
+	0x65, 0x18, 0x01, 0x20, 0x01, 0x28, 0x09, 0x52, 0x08, 0x6e, 0x6f, 0x64, 0x65, 0x4e, 0x61, 0x6d,
+	0x65, 0x12, 0x20, 0x0a, 0x0b, 0x69, 0x6e, 0x63, 0x61, 0x72, 0x6e, 0x61, 0x74, 0x69, 0x6f, 0x6e,
+	0x18, 0x02, 0x20, 0x01, 0x28, 0x03, 0x52, 0x0b, 0x69, 0x6e, 0x63, 0x61, 0x72, 0x6e, 0x61, 0x74,
+	0x69, 0x6f, 0x6e, 0x12, 0x20, 0x0a, 0x0b, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x53, 0x65, 0x71, 0x4e,
+	0x75, 0x6d, 0x18, 0x03, 0x20, 0x01, 0x28, 0x05, 0x52, 0x0b, 0x6c, 0x6f, 0x63, 0x61, 0x6c, 0x53,
+	0x65, 0x71, 0x4e, 0x75, 0x6d, 0x42, 0x32, 0x5a, 0x30, 0x67, 0x69, 0x74, 0x68, 0x75, 0x62, 0x2e,
+	0x63, 0x6f, 0x6d, 0x2f, 0x61, 0x64, 0x61, 0x72, 0x73, 0x68, 0x73, 0x72, 0x69, 0x6e, 0x69, 0x76,
+	0x61, 0x73, 0x61, 0x6e, 0x2f, 0x44, 0x53, 0x5f, 0x53, 0x32, 0x34, 0x2f, 0x6c, 0x69, 0x62, 0x72,
+	0x61, 0x72, 0x79, 0x2f, 0x70, 0x72, 0x6f, 0x74, 0x6f, 0x62, 0x06, 0x70, 0x72, 0x6f, 0x74, 0x6f,
+	0x33,
+}
+
+var (
+	file_totem_proto_rawDescOnce sync.Once
+	file_totem_proto_rawDescData = file_totem_proto_rawDesc
+)
+
+func file_totem_proto_rawDescGZIP() []byte {
+	file_totem_proto_rawDescOnce.Do(func() {
+		file_totem_proto_rawDescData = protoimpl.X.CompressGZIP(file_totem_proto_rawDescData)
+	})
+	return file_totem_proto_rawDescData
+}
+
+var file_totem_proto_msgTypes = make([]protoimpl.MessageInfo, 4)
+var file_totem_proto_goTypes = []interface{}{
+	(*TotemMessage)(nil),         // 0: proto.TotemMessage
+	(*TotemEnvelope)(nil),        // 1: proto.TotemEnvelope
+	(*StateTransferRequest)(nil), // 2: proto.StateTransferRequest
+	(*RequestWatermark)(nil),     // 3: proto.RequestWatermark
+}
+var file_totem_proto_depIdxs = []int32{
+	0, // [0:0] is the sub-list for method output_type
+	0, // [0:0] is the sub-list for method input_type
+	0, // [0:0] is the sub-list for extension type_name
+	0, // [0:0] is the sub-list for extension extendee
+	0, // [0:0] is the sub-list for field type_name
+}
+
+func init() { file_totem_proto_init() }
+func file_totem_proto_init() {
+	if File_totem_proto != nil {
+		return
+	}
+	if !protoimpl.UnsafeEnabled {
+		file_totem_proto_msgTypes[0].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TotemMessage); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_totem_proto_msgTypes[1].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*TotemEnvelope); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_totem_proto_msgTypes[2].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*StateTransferRequest); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+		file_totem_proto_msgTypes[3].Exporter = func(v interface{}, i int) interface{} {
+			switch v := v.(*RequestWatermark); i {
+			case 0:
+				return &v.state
+			case 1:
+				return &v.sizeCache
+			case 2:
+				return &v.unknownFields
+			default:
+				return nil
+			}
+		}
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: file_totem_proto_rawDesc,
+			NumEnums:      0,
+			NumMessages:   4,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_totem_proto_goTypes,
+		DependencyIndexes: file_totem_proto_depIdxs,
+		MessageInfos:      file_totem_proto_msgTypes,
+	}.Build()
+	File_totem_proto = out.File
+	file_totem_proto_rawDesc = nil
+	file_totem_proto_goTypes = nil
+	file_totem_proto_depIdxs = nil
+}
@@ -0,0 +1,293 @@
+// Cluster membership for the Raft Consensus Module.
+//
+// Membership changes are made one server at a time (section 4.1 of the Raft
+// dissertation): a configuration is replicated as a regular log entry and
+// takes effect on each server as soon as it's appended to its log, whether
+// or not it's committed. Adding or removing a single server keeps every
+// majority of the old configuration overlapping with every majority of the
+// new one, so no joint consensus phase is needed.
+//
+// A leader accepts a change only once it has committed an entry of its own
+// term (the no-op appended when it's elected), which closes the gap of
+// single-server changes described in the errata of the dissertation: a
+// change left uncommitted by a previous leader could otherwise form a
+// majority with the new one that elects a second leader in the same term.
+//
+// Members can also be learners (section 4.2.1 of the dissertation): they
+// receive the log like any follower, but don't vote and don't count towards
+// any quorum. A new server always joins as a learner, so that adding it
+// doesn't hold up commits, and is made a voter with PromoteMember once it has
+// caught up, or stays one to serve reads.
+package raft
+
+import (
+	"bytes"
+	"encoding/gob"
+	"errors"
+	"fmt"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// ErrNotLeader is returned for proposals made to a CM that isn't the leader.
+var ErrNotLeader = errors.New("not the leader")
+
+// ErrNotCaughtUp is returned by PromoteMember for a learner that is still too
+// far behind the leader's log.
+var ErrNotCaughtUp = errors.New("not caught up")
+
+// Member describes a single server in the cluster configuration.
+type Member struct {
+	// ID is the node name of the server.
+	ID string
+
+	// RaftAddr is the host:port peers use to reach the server's CM.
+	RaftAddr string
+
+	// ClientAddr is the host:port of the server's NOSQLService.
+	ClientAddr string
+
+	// Learner is set for members that don't vote.
+	Learner bool
+}
+
+// Configuration is the set of servers taking part in consensus.
+type Configuration struct {
+	Members []Member
+}
+
+// HasMember reports whether id is part of the configuration.
+func (c Configuration) HasMember(id string) bool {
+	_, found := c.Member(id)
+	return found
+}
+
+// IsVoter reports whether id is a voting member of the configuration.
+func (c Configuration) IsVoter(id string) bool {
+	member, found := c.Member(id)
+	return found && !member.Learner
+}
+
+// Member returns the member with the given id, if any.
+func (c Configuration) Member(id string) (Member, bool) {
+	for _, member := range c.Members {
+		if member.ID == id {
+			return member, true
+		}
+	}
+	return Member{}, false
+}
+
+// clone returns a copy of the configuration that doesn't share its members
+// slice with c.
+func (c Configuration) clone() Configuration {
+	return Configuration{Members: append([]Member(nil), c.Members...)}
+}
+
+func encodeConfiguration(config Configuration) ([]byte, error) {
+	var configData bytes.Buffer
+	if err := gob.NewEncoder(&configData).Encode(config); err != nil {
+		return nil, fmt.Errorf("exception while encoding configuration. %v", err)
+	}
+	return configData.Bytes(), nil
+}
+
+// decodeConfiguration decodes a configuration encoded by encodeConfiguration.
+// data may come from a peer, so a malformed one is an error.
+func decodeConfiguration(data []byte) (Configuration, error) {
+	var config Configuration
+	if err := gob.NewDecoder(bytes.NewBuffer(data)).Decode(&config); err != nil {
+		return Configuration{}, fmt.Errorf("exception while decoding configuration. %v", err)
+	}
+	return config, nil
+}
+
+// mustDecodeConfiguration decodes the configuration of an entry already in
+// the log. Entries are checked when they're received from the leader, so an
+// entry that doesn't decode means the storage is corrupt, which is fatal as in
+// restoreFromStorage.
+func mustDecodeConfiguration(data []byte) Configuration {
+	config, err := decodeConfiguration(data)
+	if err != nil {
+		log.Fatal(err)
+	}
+	return config
+}
+
+// Configuration returns the latest configuration known to this CM and the
+// ID of the current leader, if known.
+func (cm *ConsensusModule) Configuration() (Configuration, string) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	return cm.config.clone(), cm.leaderID
+}
+
+// AddMember proposes adding member to the cluster as a learner, whatever its
+// Learner field says; it's made a voter with PromoteMember. id identifies the
+// proposal on the commit channel, like the ID passed to Submit. Only the
+// leader accepts proposals, and only when no other change is in flight.
+func (cm *ConsensusModule) AddMember(id string, member Member) error {
+	if member.ID == "" || member.RaftAddr == "" {
+		return fmt.Errorf("member needs a node name and raft address")
+	}
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if cm.config.HasMember(member.ID) {
+		return fmt.Errorf("%s is already a member", member.ID)
+	}
+	member.Learner = true
+	config := cm.config.clone()
+	config.Members = append(config.Members, member)
+	return cm.proposeConfig(id, config)
+}
+
+// RemoveMember proposes removing the member with the given memberID from the
+// cluster. If the leader removes itself, it steps down once the change
+// commits.
+func (cm *ConsensusModule) RemoveMember(id string, memberID string) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if !cm.config.HasMember(memberID) {
+		return fmt.Errorf("%s is not a member", memberID)
+	}
+	var config Configuration
+	voters := 0
+	for _, member := range cm.config.Members {
+		if member.ID != memberID {
+			config.Members = append(config.Members, member)
+			if !member.Learner {
+				voters++
+			}
+		}
+	}
+	if voters == 0 {
+		return fmt.Errorf("cannot remove the last voter %s", memberID)
+	}
+	return cm.proposeConfig(id, config)
+}
+
+// maxPromotionLag is how many entries a learner may lag behind the leader's
+// log and still be promoted. A voter that is far behind would hold up
+// commits until it caught up.
+const maxPromotionLag = maxAppendEntriesBatch
+
+// PromoteMember proposes making the learner with the given memberID a voter.
+// It's refused until the learner has caught up with the leader's log.
+func (cm *ConsensusModule) PromoteMember(id string, memberID string) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	member, found := cm.config.Member(memberID)
+	if !found {
+		return fmt.Errorf("%s is not a member", memberID)
+	}
+	if !member.Learner {
+		return fmt.Errorf("%s is already a voter", memberID)
+	}
+	if cm.state == Leader && cm.lastIndex()-cm.matchIndex[memberID] > maxPromotionLag {
+		return fmt.Errorf("%s has %w yet. matchIndex: %d, lastIndex: %d", memberID, ErrNotCaughtUp, cm.matchIndex[memberID], cm.lastIndex())
+	}
+	config := cm.config.clone()
+	for i := range config.Members {
+		if config.Members[i].ID == memberID {
+			config.Members[i].Learner = false
+		}
+	}
+	return cm.proposeConfig(id, config)
+}
+
+// proposeConfig appends config to the leader's log and switches to it.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) proposeConfig(id string, config Configuration) error {
+	if cm.state != Leader {
+		return fmt.Errorf("%s is %w. leader: %q", cm.id, ErrNotLeader, cm.leaderID)
+	}
+	if cm.configIndex > cm.commitIndex {
+		return fmt.Errorf("configuration change at index %d is still in progress", cm.configIndex)
+	}
+	if cm.commitIndex < 0 || cm.termAt(cm.commitIndex) != cm.currentTerm {
+		return fmt.Errorf("leader has not committed an entry of term %d yet", cm.currentTerm)
+	}
+	if cm.transferTarget != "" {
+		return fmt.Errorf("leadership transfer to %s is in progress", cm.transferTarget)
+	}
+	payload, err := encodeConfiguration(config)
+	if err != nil {
+		return err
+	}
+	cm.log = append(cm.log, LogEntry{ID: id, Command: ConfigChange, Payload: payload, Term: cm.currentTerm})
+	cm.persistToStorage()
+	cm.setConfig(config, cm.lastIndex())
+	cm.dlog("proposed configuration %+v at index %d", config, cm.configIndex)
+	cm.triggerAE()
+	return nil
+}
+
+// reloadConfig makes the latest configuration in the log, or the snapshot's
+// if the log has none, the current configuration. It must be called whenever
+// the log is truncated or extended with entries from the leader.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) reloadConfig() {
+	config, index := cm.snapshotConfig, cm.snapshotIndex
+	for i := len(cm.log) - 1; i >= 0; i-- {
+		if cm.log[i].Command == ConfigChange {
+			config, index = mustDecodeConfiguration(cm.log[i].Payload), cm.snapshotIndex+1+i
+			break
+		}
+	}
+	cm.setConfig(config, index)
+}
+
+// configAt returns the configuration in effect at index, which must be
+// either snapshotIndex or an index still held in cm.log.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) configAt(index int) Configuration {
+	for i := cm.logPos(index); i >= 0; i-- {
+		if cm.log[i].Command == ConfigChange {
+			return mustDecodeConfiguration(cm.log[i].Payload)
+		}
+	}
+	return cm.snapshotConfig
+}
+
+// setConfig switches the CM to config, carried by the entry at index.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) setConfig(config Configuration, index int) {
+	cm.config = config
+	cm.configIndex = index
+	cm.peerIds = nil
+	for _, member := range config.Members {
+		if member.ID == cm.id {
+			continue
+		}
+		cm.peerIds = append(cm.peerIds, member.ID)
+		if _, found := cm.nextIndex[member.ID]; !found && cm.state == Leader {
+			cm.nextIndex[member.ID] = cm.lastIndex() + 1
+			cm.matchIndex[member.ID] = -1
+		}
+	}
+}
+
+// isQuorum reports whether the members for which has returns true form a
+// majority of the voters in the current configuration.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) isQuorum(has func(id string) bool) bool {
+	count, voters := 0, 0
+	for _, member := range cm.config.Members {
+		if member.Learner {
+			continue
+		}
+		voters++
+		if has(member.ID) {
+			count++
+		}
+	}
+	return count*2 > voters
+}
+
+// memberAddr returns the raft address of the member with the given id.
+func (cm *ConsensusModule) memberAddr(id string) (string, bool) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	member, found := cm.config.Member(id)
+	return member.RaftAddr, found
+}
@@ -0,0 +1,78 @@
+package raft
+
+import (
+	"errors"
+	"testing"
+)
+
+// newTestLeader returns a CM that is leader of term 2 with one voter, node0,
+// and the given log. Its election timer never starts.
+func newTestLeader(t *testing.T, entries []LogEntry, commitIndex int) *ConsensusModule {
+	t.Helper()
+	config := Configuration{Members: []Member{{ID: "node0", RaftAddr: "127.0.0.1:1"}}}
+	cm := NewConsensusModule("node0", config, nil, NewMapStorage(), make(chan interface{}), make(chan CommitEntry))
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.state = Leader
+	cm.currentTerm = 2
+	cm.log = entries
+	cm.commitIndex = commitIndex
+	return cm
+}
+
+func TestConfigChangeWaitsForCurrentTermCommit(t *testing.T) {
+	entries := []LogEntry{{ID: "a", Command: NoOp, Term: 1}, {ID: "b", Command: NoOp, Term: 2}}
+	cm := newTestLeader(t, entries, 0)
+	member := Member{ID: "node1", RaftAddr: "127.0.0.1:2"}
+
+	if err := cm.AddMember("add", member); err == nil {
+		t.Fatalf("AddMember succeeded before the leader committed an entry of its term")
+	}
+	if config, _ := cm.Configuration(); config.HasMember("node1") {
+		t.Fatalf("node1 is in the configuration after a refused change")
+	}
+
+	cm.mu.Lock()
+	cm.commitIndex = 1
+	cm.mu.Unlock()
+	if err := cm.AddMember("add", member); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+}
+
+func TestAddMemberJoinsAsLearner(t *testing.T) {
+	cm := newTestLeader(t, []LogEntry{{ID: "a", Command: NoOp, Term: 2}}, 0)
+	if err := cm.AddMember("add", Member{ID: "node1", RaftAddr: "127.0.0.1:2"}); err != nil {
+		t.Fatalf("AddMember: %v", err)
+	}
+	config, _ := cm.Configuration()
+	if !config.HasMember("node1") || config.IsVoter("node1") {
+		t.Fatalf("node1 should have joined as a learner; config: %+v", config)
+	}
+
+	// The addition is still in progress.
+	if err := cm.PromoteMember("promote", "node1"); err == nil {
+		t.Fatalf("PromoteMember succeeded while the addition was uncommitted")
+	}
+
+	// The addition commits, but node1 hasn't received anything yet.
+	cm.mu.Lock()
+	for i := 0; i <= maxPromotionLag; i++ {
+		cm.log = append(cm.log, LogEntry{ID: "x", Command: NoOp, Term: 2})
+	}
+	cm.commitIndex = cm.lastIndex()
+	cm.mu.Unlock()
+	if err := cm.PromoteMember("promote", "node1"); !errors.Is(err, ErrNotCaughtUp) {
+		t.Fatalf("PromoteMember: got %v; want %v", err, ErrNotCaughtUp)
+	}
+
+	cm.mu.Lock()
+	cm.matchIndex["node1"] = cm.lastIndex()
+	cm.mu.Unlock()
+	if err := cm.PromoteMember("promote", "node1"); err != nil {
+		t.Fatalf("PromoteMember: %v", err)
+	}
+	if config, _ := cm.Configuration(); !config.IsVoter("node1") {
+		t.Fatalf("node1 should be a voter; config: %+v", config)
+	}
+}
@@ -0,0 +1,431 @@
+// A replication.Engine on top of the Consensus Module.
+//
+// Writes are submitted to the CM as commands and applied by the commit loop
+// once they're committed, on the leader and on followers alike. A write
+// proposed on a follower is forwarded to the leader, which proposes it and
+// answers with the result of applying it there once the follower has applied
+// it too. The log is compacted into a snapshot of the service's database once
+// it grows past a threshold.
+package raft
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"time"
+
+	"github.com/adarshsrinivasan/DS_S24/library/common"
+	"github.com/adarshsrinivasan/DS_S24/library/peerauth"
+	libProto "github.com/adarshsrinivasan/DS_S24/library/proto"
+	"github.com/adarshsrinivasan/DS_S24/library/replication"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/anypb"
+)
+
+const (
+	// pendingRequestCheckInterval is how often a write waiting to be applied
+	// checks that this node is still the leader of the term it was proposed
+	// in.
+	pendingRequestCheckInterval = 100 * time.Millisecond
+
+	// barrierTimeout bounds how long Barrier waits to reach the read index.
+	barrierTimeout = 2 * time.Second
+)
+
+// EngineConfig is the configuration of the CM of a node and of the service
+// state it replicates.
+type EngineConfig struct {
+	// ID is the ID of this node and ListenAddr the address it serves its
+	// peers at.
+	ID         string
+	ListenAddr string
+
+	// Bootstrap is the configuration a new cluster starts with. It's empty
+	// for a node joining a running cluster, which learns the configuration
+	// from the leader once it's added.
+	Bootstrap Configuration
+
+	// Peers maps the IDs of the peers to connect to at start to their
+	// addresses.
+	Peers map[string]string
+
+	// StorageDir holds the state of the CM, under a directory per node. If
+	// it's empty, the state lives in memory and doesn't survive a restart.
+	StorageDir string
+
+	// SnapshotThreshold is the log size past which the log is compacted. It
+	// isn't compacted if it's 0.
+	SnapshotThreshold int
+
+	// Snapshot returns an image of the service's database, and Restore
+	// replaces the database with one. Reset empties the database, before
+	// the state restored from StorageDir is replayed onto it.
+	Snapshot func(ctx context.Context) ([]byte, error)
+	Restore  func(ctx context.Context, data []byte) error
+	Reset    func(ctx context.Context) error
+
+	// Keyring authenticates the RPCs between the servers; see
+	// Server.Authenticate.
+	Keyring *peerauth.Keyring
+}
+
+// Engine is a replication.Engine running the CM of this node.
+type Engine struct {
+	config  EngineConfig
+	server  *Server
+	applied *AppliedIndex
+	apply   replication.ApplyFunc
+
+	// responseTrackers holds the channels of the writes proposed on this
+	// node that wait for their result, keyed by request ID. It's shared by
+	// Propose and the commit loop, so it's guarded by responseTrackersMu.
+	responseTrackersMu sync.Mutex
+	responseTrackers   map[string]chan replication.Result
+}
+
+var _ replication.Engine = (*Engine)(nil)
+
+func NewEngine(config EngineConfig) *Engine {
+	return &Engine{
+		config:           config,
+		applied:          NewAppliedIndex(),
+		responseTrackers: make(map[string]chan replication.Result),
+	}
+}
+
+// Server returns the server of the CM. It's nil until Start is called.
+func (e *Engine) Server() *Server {
+	return e.server
+}
+
+// Start starts the CM and the commit loop, which runs until the commit
+// channel is closed.
+func (e *Engine) Start(ctx context.Context, apply replication.ApplyFunc) error {
+	storage, err := e.newStorage(ctx)
+	if err != nil {
+		return err
+	}
+	e.apply = apply
+	commitChan := make(chan CommitEntry)
+	ready := make(chan interface{})
+	e.server = NewServer(e.config.ID, e.config.ListenAddr, e.config.Bootstrap, storage, ready, commitChan)
+	e.server.HandlePropose(e.proposeLocal)
+	e.server.Authenticate(e.config.Keyring)
+	e.server.Serve()
+	// Connections are set up in the background and redialed whenever they
+	// break, so peers that aren't up yet don't hold up the start.
+	for peerID, addr := range e.config.Peers {
+		if peerID != e.config.ID {
+			if err := e.server.ConnectToPeer(peerID, addr); err != nil {
+				log.Errorf("Start(%s): exception while connecting to %s. %v", e.config.ID, addr, err)
+			}
+		}
+	}
+	close(ready)
+	go e.handleCommit(ctx, commitChan)
+	return nil
+}
+
+// newStorage returns the Storage backing the CM.
+func (e *Engine) newStorage(ctx context.Context) (Storage, error) {
+	id := e.config.ID
+	if e.config.StorageDir == "" {
+		log.Warnf("newStorage(%s): No storage dir set. Raft state will not survive a restart.", id)
+		return NewMapStorage(), nil
+	}
+	storage, err := NewFileStorage(filepath.Join(e.config.StorageDir, id))
+	if err != nil {
+		return nil, fmt.Errorf("exception while opening raft storage. %v", err)
+	}
+	if storage.HasData() && e.config.Reset != nil {
+		// The restored snapshot and log are replayed onto the database once
+		// the commit index is learnt, so start from a clean database
+		// instead of applying the same entries twice.
+		log.Infof("newStorage(%s): restored raft state from %s. Resetting database for replay.", id, e.config.StorageDir)
+		if err := e.config.Reset(ctx); err != nil {
+			return nil, fmt.Errorf("exception while resetting database. %v", err)
+		}
+	}
+	return storage, nil
+}
+
+// Service ops are stored in the log as commands. ConfigChange and NoOp were
+// taken by the CM before there was an Engine, so ops from ConfigChange up are
+// stored past NoOp.
+const opCommandShift = NoOp - ConfigChange + 1
+
+func commandOf(op replication.Op) OpsType {
+	if OpsType(op) < ConfigChange {
+		return OpsType(op)
+	}
+	return OpsType(op) + opCommandShift
+}
+
+func opOf(command OpsType) replication.Op {
+	if command < ConfigChange {
+		return replication.Op(command)
+	}
+	return replication.Op(command - opCommandShift)
+}
+
+// pendingRequest is a command submitted to the CM on this node that is
+// waiting for the commit loop to apply it.
+type pendingRequest struct {
+	ID     string
+	term   int
+	result chan replication.Result
+}
+
+// newPendingRequest registers a tracker for a command about to be submitted
+// in the current term.
+func (e *Engine) newPendingRequest() *pendingRequest {
+	_, term, _ := e.server.CM().Report()
+	requestID := common.GenerateUUID()
+	return &pendingRequest{
+		ID:     requestID,
+		term:   term,
+		result: e.addResponseTracker(requestID),
+	}
+}
+
+// wait blocks until the commit loop delivers the command's result. It gives
+// up if ctx is done or this node stops being the leader of the term the
+// command was submitted in; the command may still commit in that case.
+func (e *Engine) wait(ctx context.Context, pending *pendingRequest) replication.Result {
+	ticker := time.NewTicker(pendingRequestCheckInterval)
+	defer ticker.Stop()
+	for {
+		var err error
+		select {
+		case result := <-pending.result:
+			return result
+		case <-ctx.Done():
+			err = status.Errorf(codes.DeadlineExceeded, "gave up waiting for request %s. %v", pending.ID, ctx.Err())
+		case <-ticker.C:
+			if _, term, isLeader := e.server.CM().Report(); isLeader && term == pending.term {
+				continue
+			}
+			err = status.Errorf(codes.Unavailable, "leadership lost while waiting for request %s. it may or may not be applied", pending.ID)
+		}
+		e.takeResponseTracker(pending.ID)
+		select {
+		case result := <-pending.result:
+			// Delivered just before we gave up.
+			return result
+		default:
+		}
+		log.Errorf("wait: %v\n", err)
+		return replication.Result{Err: err}
+	}
+}
+
+// addResponseTracker registers a tracker for requestID. It's buffered so the
+// commit loop never blocks on a waiting write.
+func (e *Engine) addResponseTracker(requestID string) chan replication.Result {
+	e.responseTrackersMu.Lock()
+	defer e.responseTrackersMu.Unlock()
+	responseChan := make(chan replication.Result, 1)
+	e.responseTrackers[requestID] = responseChan
+	return responseChan
+}
+
+// takeResponseTracker removes and returns the tracker for requestID, if any.
+func (e *Engine) takeResponseTracker(requestID string) (chan replication.Result, bool) {
+	e.responseTrackersMu.Lock()
+	defer e.responseTrackersMu.Unlock()
+	responseChan, ok := e.responseTrackers[requestID]
+	delete(e.responseTrackers, requestID)
+	return responseChan, ok
+}
+
+// notLeaderError returns the typed NotLeader error carrying this node's best
+// guess of the current leader.
+func (e *Engine) notLeaderError() error {
+	config, leaderID := e.server.CM().Configuration()
+	leader, _ := config.Member(leaderID)
+	return common.NewNotLeaderError(e.config.ID, leaderID, leader.ClientAddr)
+}
+
+// Propose submits the write to the CM and waits until this node has applied
+// it. On a follower, the write is forwarded to the leader.
+func (e *Engine) Propose(ctx context.Context, op replication.Op, payload []byte) replication.Result {
+	if result, ok := e.proposeLocal(ctx, commandOf(op), payload); ok {
+		return result
+	}
+	return e.forward(ctx, commandOf(op), payload)
+}
+
+// proposeLocal submits the command to the CM and waits until the commit loop
+// has applied it. It returns false, without proposing anything, if this node
+// isn't the leader.
+func (e *Engine) proposeLocal(ctx context.Context, command OpsType, payload []byte) (replication.Result, bool) {
+	pending := e.newPendingRequest()
+	if !e.server.CM().Submit(pending.ID, command, payload) {
+		e.takeResponseTracker(pending.ID)
+		return replication.Result{}, false
+	}
+	return e.wait(ctx, pending), true
+}
+
+// forward proposes the command on the leader and waits until this node has
+// applied it too, so that a read served here afterwards sees it.
+func (e *Engine) forward(ctx context.Context, command OpsType, payload []byte) replication.Result {
+	_, leaderID := e.server.CM().Configuration()
+	if leaderID == "" || leaderID == e.config.ID {
+		err := e.notLeaderError()
+		log.Errorf("forward: %v\n", err)
+		return replication.Result{Err: err}
+	}
+	client, err := e.server.peerClient(leaderID)
+	if err != nil {
+		err = status.Errorf(codes.Unavailable, "exception while connecting to leader %s. %v", leaderID, err)
+		log.Errorf("forward: %v\n", err)
+		return replication.Result{Err: err}
+	}
+	log.Infof("forward(%s): forwarding command %d to leader %s", e.config.ID, command, leaderID)
+	response, err := client.Propose(ctx, &libProto.ProposeRequest{Command: int32(command), Payload: payload})
+	if err != nil {
+		err = status.Errorf(codes.Unavailable, "exception while forwarding to leader %s. it may or may not be applied. %v", leaderID, err)
+		log.Errorf("forward: %v\n", err)
+		return replication.Result{Err: err}
+	}
+	if response.GetNotLeader() {
+		err := e.notLeaderError()
+		log.Errorf("forward: %s is no longer the leader. %v\n", leaderID, err)
+		return replication.Result{Err: err}
+	}
+
+	var result replication.Result
+	if response.GetResponse() != nil {
+		if result.Response, err = response.GetResponse().UnmarshalNew(); err != nil {
+			result.Err = fmt.Errorf("exception while unmarshalling response of leader %s. %v", leaderID, err)
+			log.Errorf("forward: %v\n", result.Err)
+			return result
+		}
+	}
+	if codes.Code(response.GetErrCode()) != codes.OK {
+		result.Err = status.Error(codes.Code(response.GetErrCode()), response.GetErrMessage())
+	}
+	// The leader answers once it has applied the command; wait until this
+	// node has caught up with it.
+	if err := e.Barrier(ctx); err != nil {
+		log.Errorf("forward(%s): exception while catching up with leader %s. %v\n", e.config.ID, leaderID, err)
+	}
+	return result
+}
+
+// Barrier waits until the commit loop has applied the read index.
+func (e *Engine) Barrier(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, barrierTimeout)
+	defer cancel()
+	readIndex, err := e.server.CM().ReadIndex(ctx)
+	if err != nil {
+		err = status.Errorf(codes.Unavailable, "exception while getting read index. %v", err)
+		log.Errorf("Barrier: %v\n", err)
+		return err
+	}
+	if err := e.applied.Wait(ctx, readIndex); err != nil {
+		err = status.Errorf(codes.Unavailable, "exception while catching up to read index. %v", err)
+		log.Errorf("Barrier: %v\n", err)
+		return err
+	}
+	return nil
+}
+
+// ProposeConfigChange hands a membership change to the CM through propose and
+// waits for it to commit.
+func (e *Engine) ProposeConfigChange(ctx context.Context, propose func(requestID string) error) error {
+	pending := e.newPendingRequest()
+	if err := propose(pending.ID); err != nil {
+		e.takeResponseTracker(pending.ID)
+		err = fmt.Errorf("exception while proposing configuration change. %w", err)
+		log.Errorf("ProposeConfigChange: %v\n", err)
+		return err
+	}
+	log.Infof("ProposeConfigChange: Waiting. for requestID: %s to complete.\n", pending.ID)
+	if result := e.wait(ctx, pending); result.Err != nil {
+		return result.Err
+	}
+	log.Infof("ProposeConfigChange: requestID: %s completed!\n", pending.ID)
+	return nil
+}
+
+func (e *Engine) handleCommit(ctx context.Context, commitChan <-chan CommitEntry) {
+	for commitEntry := range commitChan {
+		if commitEntry.SnapshotValid {
+			log.Infof("handleCommit(%s) got snapshot at index %d (%d bytes)", e.config.ID, commitEntry.Index, len(commitEntry.Snapshot))
+			if err := e.config.Restore(ctx, commitEntry.Snapshot); err != nil {
+				log.Fatalf("handleCommit(%s): exception restoring snapshot at index %d. %v", e.config.ID, commitEntry.Index, err)
+			}
+			e.applied.Set(commitEntry.Index)
+			continue
+		}
+		log.Infof("handleCommit(%s) got %+v", e.config.ID, commitEntry)
+		e.applyCommitEntry(ctx, commitEntry)
+		e.applied.Set(commitEntry.Index)
+		e.maybeSnapshot(ctx, commitEntry.Index)
+	}
+}
+
+// applyCommitEntry applies a committed command. This is the only place
+// commands are executed, on the leader and on followers alike; if a write
+// proposed on this node is waiting for the command, the result is delivered
+// to it.
+func (e *Engine) applyCommitEntry(ctx context.Context, commitEntry CommitEntry) {
+	var result replication.Result
+	switch commitEntry.Command {
+	case ConfigChange:
+		log.Infof("handleCommit(%s): configuration committed at index %d", e.config.ID, commitEntry.Index)
+	case NoOp:
+	default:
+		result = e.apply(ctx, commitEntry.ID, opOf(commitEntry.Command), commitEntry.Payload)
+	}
+	if result.Err != nil {
+		log.Errorf("handleCommit(%s): exception committing %s request. %v", e.config.ID, commitEntry.ID, result.Err)
+	}
+	if responseTracker, ok := e.takeResponseTracker(commitEntry.ID); ok {
+		log.Infof("handleCommit(%s) found a tracker", e.config.ID)
+		responseTracker <- result
+	}
+}
+
+// maybeSnapshot compacts the log once it grows past SnapshotThreshold
+// entries. It runs on the commit loop, so the database reflects exactly the
+// entries up to index.
+func (e *Engine) maybeSnapshot(ctx context.Context, index int) {
+	if e.config.SnapshotThreshold <= 0 || e.server.CM().LogSize() < e.config.SnapshotThreshold {
+		return
+	}
+	data, err := e.config.Snapshot(ctx)
+	if err != nil {
+		log.Errorf("maybeSnapshot(%s): exception while taking snapshot at index %d. %v", e.config.ID, index, err)
+		return
+	}
+	log.Infof("maybeSnapshot(%s): compacting log up to index %d (%d bytes)", e.config.ID, index, len(data))
+	e.server.CM().Snapshot(index, data)
+}
+
+// ProposeHandler proposes a command forwarded by a follower. It returns false
+// if this node isn't the leader.
+type ProposeHandler func(ctx context.Context, command OpsType, payload []byte) (replication.Result, bool)
+
+// convertResultToProposeResponse converts the result of a forwarded command
+// for the follower that forwarded it.
+func convertResultToProposeResponse(result replication.Result) (*libProto.ProposeResponse, error) {
+	response := &libProto.ProposeResponse{}
+	if result.Response != nil {
+		var err error
+		if response.Response, err = anypb.New(result.Response); err != nil {
+			return nil, fmt.Errorf("exception while marshalling response. %v", err)
+		}
+	}
+	if result.Err != nil {
+		st := status.Convert(result.Err)
+		response.ErrCode = int32(st.Code())
+		response.ErrMessage = st.Message()
+	}
+	return response, nil
+}
@@ -0,0 +1,135 @@
+package harness
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"time"
+
+	"github.com/adarshsrinivasan/DS_S24/library/linearizability"
+	"github.com/adarshsrinivasan/DS_S24/library/raft"
+)
+
+const (
+	// clientRequestTimeout bounds a single operation of a Client.
+	clientRequestTimeout = 2 * time.Second
+
+	// clientPollInterval is how often a Client checks whether its write has
+	// been applied.
+	clientPollInterval = 5 * time.Millisecond
+)
+
+// Client issues product operations to the cluster of a harness, like the
+// product-db's frontend does, and records them in a history for the
+// linearizability checker. Clients are safe to use from a single goroutine
+// each.
+type Client struct {
+	h       *Harness
+	id      int
+	history *linearizability.History
+
+	// leader is the node the last write went to.
+	leader int
+}
+
+// NewClient returns a client that records its operations in history as
+// client id.
+func (h *Harness) NewClient(id int, history *linearizability.History) *Client {
+	return &Client{h: h, id: id, history: history}
+}
+
+// Create creates a product with the given quantity. found is set if the
+// product already existed.
+func (c *Client) Create(productID string, quantity int) (bool, error) {
+	return c.write(CreateProduct, linearizability.ProductInput{Op: linearizability.ProductCreate, ProductID: productID, Quantity: quantity})
+}
+
+// Update sets the quantity of a product. found is set if the product
+// existed; the update fails otherwise.
+func (c *Client) Update(productID string, quantity int) (bool, error) {
+	return c.write(UpdateProduct, linearizability.ProductInput{Op: linearizability.ProductUpdate, ProductID: productID, Quantity: quantity})
+}
+
+// write submits a write to the leader and waits for the leader to apply it.
+// A write that fails before it's submitted is dropped from the history; one
+// that fails afterwards may or may not have taken effect, so it's left
+// pending.
+func (c *Client) write(command raft.OpsType, input linearizability.ProductInput) (bool, error) {
+	op := c.history.Invoke(c.id, input)
+	ctx, cancel := context.WithTimeout(context.Background(), clientRequestTimeout)
+	defer cancel()
+
+	product := Product{ID: input.ProductID, Quantity: input.Quantity}
+	node, requestID, err := c.submit(ctx, command, product)
+	if err != nil {
+		c.history.Drop(op)
+		return false, err
+	}
+
+	ticker := time.NewTicker(clientPollInterval)
+	defer ticker.Stop()
+	for {
+		if applied, err := c.h.Store(node).Result(requestID); applied {
+			// Create fails if the product exists, update if it doesn't.
+			found := (err != nil) == (command == CreateProduct)
+			c.history.Return(op, linearizability.ProductOutput{Found: found})
+			return found, nil
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return false, fmt.Errorf("exception while waiting for %s to be applied. %v", requestID, ctx.Err())
+		}
+	}
+}
+
+// submit submits a command to the leader, trying every running node until
+// one accepts it, and returns the node and the request ID.
+func (c *Client) submit(ctx context.Context, command raft.OpsType, product Product) (int, string, error) {
+	ticker := time.NewTicker(clientPollInterval)
+	defer ticker.Stop()
+	for {
+		for k := 0; k < c.h.n; k++ {
+			node := (c.leader + k) % c.h.n
+			if !c.h.isAlive(node) {
+				continue
+			}
+			if requestID, ok := c.h.SubmitToServer(node, command, product); ok {
+				c.leader = node
+				return node, requestID, nil
+			}
+		}
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return -1, "", fmt.Errorf("exception while looking for the leader. %v", ctx.Err())
+		}
+	}
+}
+
+// Get reads a product from a random running node, once the node has applied
+// the read index given by the leader. A read that fails is dropped from the
+// history.
+func (c *Client) Get(productID string) (Product, bool, error) {
+	op := c.history.Invoke(c.id, linearizability.ProductInput{Op: linearizability.ProductGet, ProductID: productID})
+	ctx, cancel := context.WithTimeout(context.Background(), clientRequestTimeout)
+	defer cancel()
+
+	node := rand.Intn(c.h.n)
+	if !c.h.isAlive(node) {
+		c.history.Drop(op)
+		return Product{}, false, fmt.Errorf("node %d is down", node)
+	}
+	store := c.h.Store(node)
+	readIndex, err := c.h.CM(node).ReadIndex(ctx)
+	if err == nil {
+		err = store.Applied.Wait(ctx, readIndex)
+	}
+	if err != nil {
+		c.history.Drop(op)
+		return Product{}, false, fmt.Errorf("exception while reading from node %d. %v", node, err)
+	}
+	product, found := store.Get(productID)
+	c.history.Return(op, linearizability.ProductOutput{Found: found, Quantity: product.Quantity})
+	return product, found, nil
+}
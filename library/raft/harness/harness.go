@@ -0,0 +1,522 @@
+// Package harness runs a cluster of Raft Consensus Modules in a single
+// process, for tests of library/raft.
+//
+// Each node talks to its peers over gRPC on the loopback interface, keeps its
+// Raft state in a MapStorage and applies committed commands to a fake
+// product table. Tests can partition and heal the network, crash nodes and
+// restart them from their storage, and check the safety properties of Raft
+// along the way:
+//
+//   - Election safety: there is at most one leader per term.
+//   - Log matching: all nodes apply the same entry at a given index.
+//   - Durability: a committed entry is never lost or replaced, including on
+//     nodes that crashed and replayed their log.
+package harness
+
+import (
+	"fmt"
+	"net"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adarshsrinivasan/DS_S24/library/common"
+	"github.com/adarshsrinivasan/DS_S24/library/raft"
+)
+
+const (
+	// leaderCheckAttempts and leaderCheckInterval bound how long
+	// CheckSingleLeader waits for an election to settle.
+	leaderCheckAttempts = 10
+	leaderCheckInterval = 500 * time.Millisecond
+
+	// commitWaitTimeout bounds how long WaitCommitted waits for an entry to
+	// be applied.
+	commitWaitTimeout = 5 * time.Second
+
+	// monitorInterval is how often the leader of every node is sampled for
+	// the election safety check.
+	monitorInterval = 5 * time.Millisecond
+)
+
+// Harness is a cluster of n nodes. Nodes are identified by their position,
+// from 0 to n-1, in all its methods.
+type Harness struct {
+	mu sync.Mutex
+
+	t testing.TB
+	n int
+
+	ids    []string
+	addrs  []string
+	config raft.Configuration
+
+	cluster []*raft.Server
+	storage []*raft.MapStorage
+	stores  []*ProductStore
+
+	// alive is set for the nodes that are running; connected[i][j] is set
+	// while node i can reach node j.
+	alive     []bool
+	connected [][]bool
+
+	// incarnation counts the restarts of each node, so that entries a node
+	// delivers after it crashed are ignored.
+	incarnation []int
+
+	// applied holds the index at which each node applied each request ID,
+	// since the node last started.
+	applied []map[string]int
+
+	// committed holds every entry applied by any node, by index, and leaders
+	// the leader seen in each term. violations lists the safety violations
+	// found so far.
+	committed  map[int]raft.CommitEntry
+	leaders    map[int]string
+	violations []string
+
+	// snapshotThreshold makes nodes compact their log once it holds that
+	// many entries. 0 disables snapshots.
+	snapshotThreshold int
+
+	quit chan interface{}
+	wg   sync.WaitGroup
+}
+
+// NewHarness starts a cluster of n nodes, all connected to each other.
+func NewHarness(t testing.TB, n int) *Harness {
+	t.Helper()
+	h := &Harness{
+		t:           t,
+		n:           n,
+		ids:         make([]string, n),
+		addrs:       make([]string, n),
+		cluster:     make([]*raft.Server, n),
+		storage:     make([]*raft.MapStorage, n),
+		stores:      make([]*ProductStore, n),
+		alive:       make([]bool, n),
+		connected:   make([][]bool, n),
+		incarnation: make([]int, n),
+		applied:     make([]map[string]int, n),
+		committed:   make(map[int]raft.CommitEntry),
+		leaders:     make(map[int]string),
+		quit:        make(chan interface{}),
+	}
+	for i := 0; i < n; i++ {
+		addr, err := freeAddr()
+		if err != nil {
+			t.Fatalf("exception while allocating an address for node %d. %v", i, err)
+		}
+		h.ids[i] = fmt.Sprintf("node%d", i)
+		h.addrs[i] = addr
+		h.config.Members = append(h.config.Members, raft.Member{ID: h.ids[i], RaftAddr: addr})
+		h.storage[i] = raft.NewMapStorage()
+		h.connected[i] = make([]bool, n)
+		for j := 0; j < n; j++ {
+			h.connected[i][j] = true
+		}
+	}
+
+	ready := make(chan interface{})
+	for i := 0; i < n; i++ {
+		h.startNode(i, ready)
+	}
+	close(ready)
+
+	h.wg.Add(1)
+	go h.monitorLeaders()
+	return h
+}
+
+// freeAddr returns a loopback address with a port that's free right now.
+func freeAddr() (string, error) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	if err != nil {
+		return "", err
+	}
+	defer listener.Close()
+	return listener.Addr().String(), nil
+}
+
+// startNode starts node i from its storage, with an empty product table.
+// Links that are down stay down.
+func (h *Harness) startNode(i int, ready <-chan interface{}) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	commitChan := make(chan raft.CommitEntry)
+	h.stores[i] = newProductStore()
+	h.applied[i] = make(map[string]int)
+	h.cluster[i] = raft.NewServer(h.ids[i], h.addrs[i], h.config, h.storage[i], ready, commitChan)
+	h.cluster[i].Serve()
+	for j := 0; j < h.n; j++ {
+		if j != i && !h.connected[i][j] {
+			h.cluster[i].DisconnectPeer(h.ids[j])
+		}
+	}
+	h.alive[i] = true
+
+	h.wg.Add(1)
+	go h.collectCommits(i, h.incarnation[i], commitChan)
+}
+
+// Shutdown stops all the nodes and checks safety one last time.
+func (h *Harness) Shutdown() {
+	h.t.Helper()
+	for i := 0; i < h.n; i++ {
+		if h.isAlive(i) {
+			h.CrashPeer(i)
+		}
+	}
+	close(h.quit)
+	h.wg.Wait()
+	h.CheckSafety()
+}
+
+// ID returns the Raft ID of node i.
+func (h *Harness) ID(i int) string {
+	return h.ids[i]
+}
+
+// CM returns the Consensus Module of node i. It's replaced when the node is
+// restarted.
+func (h *Harness) CM(i int) *raft.ConsensusModule {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.cluster[i].CM()
+}
+
+// Store returns the product table of node i. It's replaced when the node is
+// restarted.
+func (h *Harness) Store(i int) *ProductStore {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.stores[i]
+}
+
+// isAlive reports whether node i is running.
+func (h *Harness) isAlive(i int) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.alive[i]
+}
+
+// SetSnapshotThreshold makes nodes compact their log once it holds threshold
+// entries; 0 disables snapshots.
+func (h *Harness) SetSnapshotThreshold(threshold int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.snapshotThreshold = threshold
+}
+
+// setLink brings the link from node i to node j up or down.
+// Expects h.mu to be locked.
+func (h *Harness) setLink(i, j int, up bool) {
+	if i == j || h.connected[i][j] == up {
+		return
+	}
+	h.connected[i][j] = up
+	if !h.alive[i] {
+		return
+	}
+	if up {
+		h.cluster[i].ConnectToPeer(h.ids[j], h.addrs[j])
+	} else {
+		h.cluster[i].DisconnectPeer(h.ids[j])
+	}
+}
+
+// DisconnectPeer isolates node i from all the other nodes.
+func (h *Harness) DisconnectPeer(i int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.t.Logf("Disconnect %d", i)
+	for j := 0; j < h.n; j++ {
+		h.setLink(i, j, false)
+		h.setLink(j, i, false)
+	}
+}
+
+// ReconnectPeer undoes DisconnectPeer for node i.
+func (h *Harness) ReconnectPeer(i int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.t.Logf("Reconnect %d", i)
+	for j := 0; j < h.n; j++ {
+		h.setLink(i, j, true)
+		h.setLink(j, i, true)
+	}
+}
+
+// Partition splits the cluster into the given groups of nodes: nodes can only
+// reach the nodes in their own group. Nodes left out of every group are
+// isolated.
+func (h *Harness) Partition(groups ...[]int) {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.t.Logf("Partition %v", groups)
+	group := make([]int, h.n)
+	for i := range group {
+		group[i] = -1 - i
+	}
+	for g, members := range groups {
+		for _, i := range members {
+			group[i] = g
+		}
+	}
+	for i := 0; i < h.n; i++ {
+		for j := 0; j < h.n; j++ {
+			h.setLink(i, j, group[i] == group[j])
+		}
+	}
+}
+
+// Heal reconnects all the nodes to each other.
+func (h *Harness) Heal() {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.t.Logf("Heal")
+	for i := 0; i < h.n; i++ {
+		for j := 0; j < h.n; j++ {
+			h.setLink(i, j, true)
+		}
+	}
+}
+
+// CrashPeer stops node i. Its storage survives, as it would on disk; its
+// product table doesn't.
+func (h *Harness) CrashPeer(i int) {
+	h.mu.Lock()
+	h.t.Logf("Crash %d", i)
+	server := h.cluster[i]
+	h.alive[i] = false
+	h.incarnation[i]++
+	h.mu.Unlock()
+
+	server.DisconnectAll()
+	server.Shutdown()
+}
+
+// RestartPeer starts node i again after CrashPeer. It rebuilds its product
+// table from the snapshot and log in its storage.
+func (h *Harness) RestartPeer(i int) {
+	if h.isAlive(i) {
+		h.t.Fatalf("node %d is already running", i)
+	}
+	h.t.Logf("Restart %d", i)
+	ready := make(chan interface{})
+	close(ready)
+	h.startNode(i, ready)
+}
+
+// collectCommits applies the entries node i delivers to its product table
+// until the harness is shut down, and checks them against the entries other
+// nodes applied at the same index. Entries delivered after the node crashed
+// are dropped.
+func (h *Harness) collectCommits(i int, incarnation int, commitChan <-chan raft.CommitEntry) {
+	defer h.wg.Done()
+	for {
+		var entry raft.CommitEntry
+		select {
+		case entry = <-commitChan:
+		case <-h.quit:
+			return
+		}
+
+		h.mu.Lock()
+		if h.incarnation[i] != incarnation {
+			h.mu.Unlock()
+			continue
+		}
+		store, cm, threshold := h.stores[i], h.cluster[i].CM(), h.snapshotThreshold
+		if entry.SnapshotValid {
+			if err := store.restore(entry.Index, entry.Snapshot); err != nil {
+				h.violate("node %d: exception while restoring snapshot at index %d. %v", i, entry.Index, err)
+			}
+			h.mu.Unlock()
+			continue
+		}
+		if other, found := h.committed[entry.Index]; found {
+			if other.ID != entry.ID || other.Command != entry.Command {
+				h.violate("node %d applied %s (command %d) at index %d, where %s (command %d) was applied before",
+					i, entry.ID, entry.Command, entry.Index, other.ID, other.Command)
+			}
+		} else {
+			h.committed[entry.Index] = entry
+		}
+		if index, found := h.applied[i][entry.ID]; found {
+			h.violate("node %d applied %s twice, at index %d and %d", i, entry.ID, index, entry.Index)
+		}
+		h.applied[i][entry.ID] = entry.Index
+		store.apply(entry)
+		h.mu.Unlock()
+
+		if threshold > 0 && cm.LogSize() >= threshold {
+			cm.Snapshot(entry.Index, store.snapshot())
+		}
+	}
+}
+
+// monitorLeaders samples the leader of every running node until the harness
+// is shut down, and records two leaders in the same term as a violation.
+func (h *Harness) monitorLeaders() {
+	defer h.wg.Done()
+	ticker := time.NewTicker(monitorInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case <-ticker.C:
+		case <-h.quit:
+			return
+		}
+		h.mu.Lock()
+		for i := 0; i < h.n; i++ {
+			if !h.alive[i] {
+				continue
+			}
+			if id, term, isLeader := h.cluster[i].CM().Report(); isLeader {
+				if leader, found := h.leaders[term]; found && leader != id {
+					h.violate("%s and %s are both leaders in term %d", leader, id, term)
+				}
+				h.leaders[term] = id
+			}
+		}
+		h.mu.Unlock()
+	}
+}
+
+// violate records a safety violation. It's reported by the next check, on
+// the test's goroutine.
+// Expects h.mu to be locked.
+func (h *Harness) violate(format string, args ...interface{}) {
+	h.violations = append(h.violations, fmt.Sprintf(format, args...))
+}
+
+// CheckSafety fails the test if any safety violation was seen so far.
+func (h *Harness) CheckSafety() {
+	h.t.Helper()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for _, violation := range h.violations {
+		h.t.Errorf("safety violation: %s", violation)
+	}
+	if len(h.violations) > 0 {
+		h.t.FailNow()
+	}
+}
+
+// CheckSingleLeader waits for the running nodes to settle on a single leader
+// and returns it along with its term. The test fails if there is none.
+func (h *Harness) CheckSingleLeader() (int, int) {
+	h.t.Helper()
+	for attempt := 0; attempt < leaderCheckAttempts; attempt++ {
+		leaderId, leaderTerm := -1, -1
+		h.mu.Lock()
+		for i := 0; i < h.n; i++ {
+			if !h.alive[i] {
+				continue
+			}
+			if _, term, isLeader := h.cluster[i].CM().Report(); isLeader && term >= leaderTerm {
+				if term == leaderTerm {
+					h.mu.Unlock()
+					h.t.Fatalf("both %d and %d think they're leaders in term %d", leaderId, i, term)
+				}
+				leaderId, leaderTerm = i, term
+			}
+		}
+		h.mu.Unlock()
+		if leaderId >= 0 && h.isMajorityLeader(leaderId) {
+			return leaderId, leaderTerm
+		}
+		time.Sleep(leaderCheckInterval)
+	}
+	h.t.Fatalf("leader not found")
+	return -1, -1
+}
+
+// isMajorityLeader reports whether node i, a leader, can reach a
+// majority of the cluster. A leader cut off from it may not have stepped down
+// yet.
+func (h *Harness) isMajorityLeader(i int) bool {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	reachable := 0
+	for j := 0; j < h.n; j++ {
+		if h.alive[j] && (i == j || h.connected[i][j] && h.connected[j][i]) {
+			reachable++
+		}
+	}
+	return reachable > h.n/2
+}
+
+// CheckNoLeader fails the test if any running node thinks it's the leader.
+func (h *Harness) CheckNoLeader() {
+	h.t.Helper()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	for i := 0; i < h.n; i++ {
+		if !h.alive[i] {
+			continue
+		}
+		if _, _, isLeader := h.cluster[i].CM().Report(); isLeader {
+			h.t.Fatalf("node %d is leader; want no leader", i)
+		}
+	}
+}
+
+// SubmitToServer submits a product command to node i and returns the request
+// ID it was submitted with. ok is false if the node isn't the leader.
+func (h *Harness) SubmitToServer(i int, command raft.OpsType, product Product) (string, bool) {
+	requestID := common.GenerateUUID()
+	return requestID, h.CM(i).Submit(requestID, command, EncodeProduct(product))
+}
+
+// CheckCommitted returns the number of running nodes that applied the entry
+// with requestID, and the index they applied it at. The test fails if they
+// applied it at different indexes.
+func (h *Harness) CheckCommitted(requestID string) (int, int) {
+	h.t.Helper()
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	count, commitIndex := 0, -1
+	for i := 0; i < h.n; i++ {
+		if !h.alive[i] {
+			continue
+		}
+		index, found := h.applied[i][requestID]
+		if !found {
+			continue
+		}
+		if commitIndex >= 0 && index != commitIndex {
+			h.t.Fatalf("%s applied at index %d and %d", requestID, commitIndex, index)
+		}
+		commitIndex = index
+		count++
+	}
+	return count, commitIndex
+}
+
+// WaitCommitted waits for at least n running nodes to apply the entry with
+// requestID and returns the index it was applied at.
+func (h *Harness) WaitCommitted(requestID string, n int) int {
+	h.t.Helper()
+	deadline := time.Now().Add(commitWaitTimeout)
+	for {
+		count, index := h.CheckCommitted(requestID)
+		if count >= n {
+			return index
+		}
+		if time.Now().After(deadline) {
+			h.t.Fatalf("%s applied by %d nodes; want %d", requestID, count, n)
+		}
+		time.Sleep(10 * time.Millisecond)
+	}
+}
+
+// CheckNotCommitted fails the test if any running node applied the entry
+// with requestID.
+func (h *Harness) CheckNotCommitted(requestID string) {
+	h.t.Helper()
+	if count, index := h.CheckCommitted(requestID); count > 0 {
+		h.t.Fatalf("%s applied at index %d by %d nodes; want none", requestID, index, count)
+	}
+}
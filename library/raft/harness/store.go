@@ -0,0 +1,181 @@
+package harness
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"sync"
+
+	"github.com/adarshsrinivasan/DS_S24/library/raft"
+)
+
+// Commands of the fake product state machine. Like the product-db's, they
+// are numbered from 0; raft.ConfigChange and raft.NoOp are taken by the CM.
+const (
+	CreateProduct raft.OpsType = iota
+	UpdateProduct
+	DeleteProduct
+)
+
+// Product is a row of the fake product table.
+type Product struct {
+	ID       string
+	Name     string
+	Quantity int
+}
+
+// EncodeProduct encodes product as the payload of a product command.
+func EncodeProduct(product Product) []byte {
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(product); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+func decodeProduct(payload []byte) (Product, error) {
+	var product Product
+	if err := gob.NewDecoder(bytes.NewBuffer(payload)).Decode(&product); err != nil {
+		return product, fmt.Errorf("exception while decoding product. %v", err)
+	}
+	return product, nil
+}
+
+// ProductStore is the fake product table each node of the harness applies
+// committed commands to.
+type ProductStore struct {
+	mu       sync.Mutex
+	products map[string]Product
+
+	// results holds the outcome of every command applied, by request ID.
+	results map[string]error
+
+	// Applied tracks the last index applied to the store, for reads that
+	// have to wait for a read index.
+	Applied *raft.AppliedIndex
+}
+
+func newProductStore() *ProductStore {
+	return &ProductStore{
+		products: make(map[string]Product),
+		results:  make(map[string]error),
+		Applied:  raft.NewAppliedIndex(),
+	}
+}
+
+// Get returns the product with the given id, if any.
+func (ps *ProductStore) Get(id string) (Product, bool) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	product, found := ps.products[id]
+	return product, found
+}
+
+// Len returns the number of products in the store.
+func (ps *ProductStore) Len() int {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	return len(ps.products)
+}
+
+// Result reports whether the command submitted with requestID has been
+// applied to the store, and its outcome.
+func (ps *ProductStore) Result(requestID string) (bool, error) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	err, found := ps.results[requestID]
+	return found, err
+}
+
+// apply executes a committed command. Commands that fail, like creating a
+// product that already exists, leave the store unchanged.
+func (ps *ProductStore) apply(entry raft.CommitEntry) {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.results[entry.ID] = ps.execute(entry)
+	ps.Applied.Set(entry.Index)
+}
+
+// execute applies a single command.
+// Expects ps.mu to be locked.
+func (ps *ProductStore) execute(entry raft.CommitEntry) error {
+	switch entry.Command {
+	case raft.ConfigChange, raft.NoOp:
+		return nil
+	case CreateProduct, UpdateProduct, DeleteProduct:
+	default:
+		return fmt.Errorf("unknown OPSType: %d", entry.Command)
+	}
+	product, err := decodeProduct(entry.Payload)
+	if err != nil {
+		return err
+	}
+	_, found := ps.products[product.ID]
+	switch entry.Command {
+	case CreateProduct:
+		if found {
+			return fmt.Errorf("product %s already exists", product.ID)
+		}
+		ps.products[product.ID] = product
+	case UpdateProduct:
+		if !found {
+			return fmt.Errorf("product %s not found", product.ID)
+		}
+		ps.products[product.ID] = product
+	case DeleteProduct:
+		if !found {
+			return fmt.Errorf("product %s not found", product.ID)
+		}
+		delete(ps.products, product.ID)
+	}
+	return nil
+}
+
+// storeSnapshot is the image of a ProductStore handed to the CM when the log
+// is compacted. Results are part of it so that clients waiting for a command
+// covered by a snapshot still learn its outcome.
+type storeSnapshot struct {
+	Products map[string]Product
+	Results  map[string]string
+}
+
+func (ps *ProductStore) snapshot() []byte {
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	snapshot := storeSnapshot{Products: ps.products, Results: make(map[string]string)}
+	for id, err := range ps.results {
+		snapshot.Results[id] = ""
+		if err != nil {
+			snapshot.Results[id] = err.Error()
+		}
+	}
+	var buf bytes.Buffer
+	if err := gob.NewEncoder(&buf).Encode(snapshot); err != nil {
+		panic(err)
+	}
+	return buf.Bytes()
+}
+
+// restore replaces the contents of the store with the snapshot taken at
+// index.
+func (ps *ProductStore) restore(index int, data []byte) error {
+	var snapshot storeSnapshot
+	if err := gob.NewDecoder(bytes.NewBuffer(data)).Decode(&snapshot); err != nil {
+		return fmt.Errorf("exception while decoding snapshot. %v", err)
+	}
+	ps.mu.Lock()
+	defer ps.mu.Unlock()
+	ps.products = make(map[string]Product)
+	for id, product := range snapshot.Products {
+		ps.products[id] = product
+	}
+	ps.results = make(map[string]error)
+	for id, msg := range snapshot.Results {
+		ps.results[id] = nil
+		if msg != "" {
+			ps.results[id] = fmt.Errorf("%s", msg)
+		}
+	}
+	ps.Applied.Set(index)
+	return nil
+}
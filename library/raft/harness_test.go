@@ -0,0 +1,242 @@
+package raft_test
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"github.com/adarshsrinivasan/DS_S24/library/raft"
+	"github.com/adarshsrinivasan/DS_S24/library/raft/harness"
+)
+
+// settleInterval gives a cluster time to exchange a few rounds of heartbeats,
+// so that partitioned leaders notice and healed nodes catch up.
+const settleInterval = 500 * time.Millisecond
+
+// submit submits a command to the current leader, retrying while leadership
+// changes, and returns its request ID.
+func submit(t *testing.T, h *harness.Harness, command raft.OpsType, product harness.Product) string {
+	t.Helper()
+	for attempt := 0; attempt < 10; attempt++ {
+		leader, _ := h.CheckSingleLeader()
+		if requestID, ok := h.SubmitToServer(leader, command, product); ok {
+			return requestID
+		}
+		time.Sleep(50 * time.Millisecond)
+	}
+	t.Fatalf("no leader accepted %+v", product)
+	return ""
+}
+
+// createProduct submits the creation of product i and returns its request ID.
+func createProduct(t *testing.T, h *harness.Harness, i int) string {
+	t.Helper()
+	return submit(t, h, harness.CreateProduct, harness.Product{ID: fmt.Sprintf("product%d", i), Quantity: i})
+}
+
+// checkStoresMatch fails the test unless the running nodes in nodes hold the
+// same products 0 to n-1.
+func checkStoresMatch(t *testing.T, h *harness.Harness, nodes []int, n int) {
+	t.Helper()
+	for _, node := range nodes {
+		store := h.Store(node)
+		if store.Len() != n {
+			t.Fatalf("node %d holds %d products; want %d", node, store.Len(), n)
+		}
+		for i := 0; i < n; i++ {
+			product, found := store.Get(fmt.Sprintf("product%d", i))
+			if !found || product.Quantity != i {
+				t.Fatalf("node %d: product%d = %+v, found: %t", node, i, product, found)
+			}
+		}
+	}
+}
+
+func TestElectionSafetyUnderPartitions(t *testing.T) {
+	h := harness.NewHarness(t, 5)
+	defer h.Shutdown()
+
+	for round := 0; round < 4; round++ {
+		leader, term := h.CheckSingleLeader()
+		minority := []int{leader, (leader + 1) % 5}
+		majority := []int{(leader + 2) % 5, (leader + 3) % 5, (leader + 4) % 5}
+		h.Partition(minority, majority)
+
+		newLeader, newTerm := h.CheckSingleLeader()
+		if newLeader == minority[0] || newLeader == minority[1] {
+			t.Fatalf("round %d: node %d leads from the minority", round, newLeader)
+		}
+		if newTerm <= term {
+			t.Fatalf("round %d: new leader %d in term %d; want a term after %d", round, newLeader, newTerm, term)
+		}
+		h.CheckSafety()
+
+		h.Heal()
+		time.Sleep(settleInterval)
+		h.CheckSafety()
+	}
+}
+
+func TestSplitIntoThreeHasNoLeader(t *testing.T) {
+	h := harness.NewHarness(t, 5)
+	defer h.Shutdown()
+
+	h.CheckSingleLeader()
+	h.Partition([]int{0, 1}, []int{2, 3}, []int{4})
+	// Leaders step down once they lose the quorum, and no group can elect a
+	// new one.
+	time.Sleep(3 * settleInterval)
+	h.CheckNoLeader()
+
+	h.Heal()
+	h.CheckSingleLeader()
+	h.CheckSafety()
+}
+
+func TestLogsMatchAfterHeal(t *testing.T) {
+	h := harness.NewHarness(t, 5)
+	defer h.Shutdown()
+
+	h.WaitCommitted(createProduct(t, h, 0), 5)
+
+	leader, _ := h.CheckSingleLeader()
+	minority := []int{leader, (leader + 1) % 5}
+	majority := []int{(leader + 2) % 5, (leader + 3) % 5, (leader + 4) % 5}
+	h.Partition(minority, majority)
+
+	// The old leader may still accept this, but can't commit it.
+	stale, staleAccepted := h.SubmitToServer(leader, harness.CreateProduct, harness.Product{ID: "stale"})
+
+	h.CheckSingleLeader()
+	var requestIDs []string
+	for i := 1; i < 4; i++ {
+		requestID := createProduct(t, h, i)
+		h.WaitCommitted(requestID, 3)
+		requestIDs = append(requestIDs, requestID)
+	}
+	if staleAccepted {
+		h.CheckNotCommitted(stale)
+	}
+
+	h.Heal()
+	h.WaitCommitted(createProduct(t, h, 4), 5)
+	for _, requestID := range requestIDs {
+		h.WaitCommitted(requestID, 5)
+	}
+	if staleAccepted {
+		h.CheckNotCommitted(stale)
+	}
+	checkStoresMatch(t, h, []int{0, 1, 2, 3, 4}, 5)
+	h.CheckSafety()
+}
+
+func TestCommittedEntriesSurviveCrashRestart(t *testing.T) {
+	h := harness.NewHarness(t, 3)
+	defer h.Shutdown()
+
+	var requestIDs []string
+	for i := 0; i < 5; i++ {
+		requestID := createProduct(t, h, i)
+		h.WaitCommitted(requestID, 3)
+		requestIDs = append(requestIDs, requestID)
+	}
+
+	leader, _ := h.CheckSingleLeader()
+	h.CrashPeer(leader)
+	requestID := createProduct(t, h, 5)
+	h.WaitCommitted(requestID, 2)
+	requestIDs = append(requestIDs, requestID)
+
+	// Crash the rest of the cluster too: every node has to rebuild its
+	// product table from its storage.
+	for i := 0; i < 3; i++ {
+		if i != leader {
+			h.CrashPeer(i)
+		}
+	}
+	for i := 0; i < 3; i++ {
+		h.RestartPeer(i)
+	}
+	h.CheckSingleLeader()
+	for _, requestID := range requestIDs {
+		h.WaitCommitted(requestID, 3)
+	}
+	checkStoresMatch(t, h, []int{0, 1, 2}, 6)
+	h.CheckSafety()
+}
+
+func TestCommittedEntriesSurviveRestartFromSnapshot(t *testing.T) {
+	h := harness.NewHarness(t, 3)
+	defer h.Shutdown()
+	h.SetSnapshotThreshold(4)
+
+	for i := 0; i < 10; i++ {
+		h.WaitCommitted(createProduct(t, h, i), 3)
+	}
+
+	// A node that misses entries compacted by the others catches up through
+	// InstallSnapshot.
+	h.CrashPeer(2)
+	for i := 10; i < 20; i++ {
+		h.WaitCommitted(createProduct(t, h, i), 2)
+	}
+	h.RestartPeer(2)
+	h.WaitCommitted(createProduct(t, h, 20), 3)
+	time.Sleep(settleInterval)
+	checkStoresMatch(t, h, []int{0, 1, 2}, 21)
+	h.CheckSafety()
+}
+
+func TestLeadershipTransfer(t *testing.T) {
+	h := harness.NewHarness(t, 3)
+	defer h.Shutdown()
+
+	h.WaitCommitted(createProduct(t, h, 0), 3)
+	leader, term := h.CheckSingleLeader()
+	target := (leader + 1) % 3
+
+	newLeaderID, err := h.CM(leader).TransferLeadership(context.Background(), h.ID(target))
+	if err != nil {
+		t.Fatalf("TransferLeadership: %v", err)
+	}
+	if newLeaderID != h.ID(target) {
+		t.Fatalf("leadership went to %s; want %s", newLeaderID, h.ID(target))
+	}
+	newLeader, newTerm := h.CheckSingleLeader()
+	if newLeader != target || newTerm <= term {
+		t.Fatalf("node %d leads in term %d; want node %d in a term after %d", newLeader, newTerm, target, term)
+	}
+
+	// The target had the whole log, so nothing committed is lost.
+	h.WaitCommitted(createProduct(t, h, 1), 3)
+	checkStoresMatch(t, h, []int{0, 1, 2}, 2)
+	h.CheckSafety()
+}
+
+func TestIsolatedFollowerDoesNotDisruptLeaderAfterHeal(t *testing.T) {
+	h := harness.NewHarness(t, 5)
+	defer h.Shutdown()
+
+	h.WaitCommitted(createProduct(t, h, 0), 5)
+	leader, term := h.CheckSingleLeader()
+	isolated := (leader + 1) % 5
+
+	// Pre-vote keeps the isolated follower from raising its term while it
+	// can't win, so its elections don't depose the leader once it's back.
+	h.DisconnectPeer(isolated)
+	time.Sleep(3 * settleInterval)
+	if _, isolatedTerm, _ := h.CM(isolated).Report(); isolatedTerm != term {
+		t.Fatalf("isolated node %d is in term %d; want %d", isolated, isolatedTerm, term)
+	}
+
+	h.ReconnectPeer(isolated)
+	time.Sleep(settleInterval)
+	newLeader, newTerm := h.CheckSingleLeader()
+	if newLeader != leader || newTerm != term {
+		t.Fatalf("node %d leads in term %d after heal; want node %d in term %d", newLeader, newTerm, leader, term)
+	}
+	h.WaitCommitted(createProduct(t, h, 1), 5)
+	checkStoresMatch(t, h, []int{0, 1, 2, 3, 4}, 2)
+	h.CheckSafety()
+}
@@ -0,0 +1,73 @@
+package raft_test
+
+import (
+	"fmt"
+	"math/rand"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/adarshsrinivasan/DS_S24/library/linearizability"
+	"github.com/adarshsrinivasan/DS_S24/library/raft/harness"
+)
+
+const (
+	// linearizabilityCheckTimeout bounds how long the checker may take.
+	linearizabilityCheckTimeout = 30 * time.Second
+
+	// clientCount clients each run clientOperations operations on
+	// productCount products, so that they often contend.
+	clientCount      = 4
+	clientOperations = 40
+	productCount     = 3
+)
+
+// TestClientHistoryIsLinearizable runs concurrent clients against a cluster
+// whose leader is cut off halfway through, and checks the history they
+// recorded.
+func TestClientHistoryIsLinearizable(t *testing.T) {
+	h := harness.NewHarness(t, 5)
+	defer h.Shutdown()
+	h.CheckSingleLeader()
+
+	history := linearizability.NewHistory()
+	var wg sync.WaitGroup
+	for id := 0; id < clientCount; id++ {
+		wg.Add(1)
+		go func(id int) {
+			defer wg.Done()
+			client := h.NewClient(id, history)
+			random := rand.New(rand.NewSource(int64(id)))
+			for i := 0; i < clientOperations; i++ {
+				productID := fmt.Sprintf("product%d", random.Intn(productCount))
+				// Failed operations are dropped from the history, or left
+				// pending if they may have taken effect.
+				switch random.Intn(3) {
+				case 0:
+					client.Create(productID, random.Intn(100))
+				case 1:
+					client.Update(productID, random.Intn(100))
+				default:
+					client.Get(productID)
+				}
+			}
+		}(id)
+	}
+
+	time.Sleep(200 * time.Millisecond)
+	leader, _ := h.CheckSingleLeader()
+	h.Partition([]int{leader}, []int{(leader + 1) % 5, (leader + 2) % 5, (leader + 3) % 5, (leader + 4) % 5})
+	time.Sleep(settleInterval)
+	h.Heal()
+	wg.Wait()
+
+	operations := history.Operations()
+	if len(operations) == 0 {
+		t.Fatalf("no operation completed")
+	}
+	result := linearizability.CheckOperationsTimeout(linearizability.ProductModel, operations, linearizabilityCheckTimeout)
+	if result != linearizability.Ok {
+		t.Fatalf("history of %d operations is %v", len(operations), result)
+	}
+	h.CheckSafety()
+}
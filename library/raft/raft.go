@@ -0,0 +1,1166 @@
+// Core Raft implementation - Consensus Module.
+//
+// Eli Bendersky [https://eli.thegreenplace.net]
+// This code is in the public domain.
+package raft
+
+import (
+	"bytes"
+	"encoding/gob"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/adarshsrinivasan/DS_S24/library/common"
+	log "github.com/sirupsen/logrus"
+)
+
+// OpsType identifies the command carried by a log entry. ConfigChange and NoOp
+// are used by the CM itself; clients define their own commands with the other
+// values.
+type OpsType int
+
+const (
+	// ConfigChange entries carry a cluster configuration.
+	ConfigChange OpsType = 3
+
+	// NoOp entries are appended by a new leader to commit an entry of its own
+	// term.
+	NoOp OpsType = 4
+)
+
+const DebugCM = 1
+
+// CommitEntry is the data reported by Raft to the commit channel. Each commit
+// entry notifies the client that consensus was reached on a command, and it can
+// be applied to the client's state machine.
+type CommitEntry struct {
+	// ID is used to uniquely identify a request
+	ID string
+
+	// Command is the client command being committed.
+	Command OpsType
+
+	// Payload for the command.
+	Payload []byte
+
+	// Index is the log index at which the client command is committed.
+	Index int
+
+	// Term is the Raft term at which the client command is committed.
+	Term int
+
+	// SnapshotValid is set when this entry carries a snapshot instead of a
+	// command. The client must replace its state machine with Snapshot, which
+	// covers every entry up to and including Index.
+	SnapshotValid bool
+
+	// Snapshot is the state machine snapshot when SnapshotValid is set.
+	Snapshot []byte
+}
+
+type CMState int
+
+const (
+	Follower CMState = iota
+	Candidate
+	Leader
+	Dead
+)
+
+func (s CMState) String() string {
+	switch s {
+	case Follower:
+		return "Follower"
+	case Candidate:
+		return "Candidate"
+	case Leader:
+		return "Leader"
+	case Dead:
+		return "Dead"
+	default:
+		panic("unreachable")
+	}
+}
+
+type LogEntry struct {
+	ID      string
+	Command OpsType
+	Payload []byte
+	Term    int
+}
+
+// ConsensusModule (CM) implements a single node of Raft consensus.
+type ConsensusModule struct {
+	// mu protects concurrent access to a CM.
+	mu sync.Mutex
+
+	// id is the server ID of this CM.
+	id string
+
+	// peerIds lists the IDs of our peers in the cluster. It's derived from
+	// config and excludes this CM.
+	peerIds []string
+
+	// config is the latest cluster configuration in the log; configIndex is
+	// the log index of the entry carrying it. When the log holds no
+	// configuration entry, config is snapshotConfig and configIndex is
+	// snapshotIndex.
+	config      Configuration
+	configIndex int
+
+	// server is the server containing this CM. It's used to issue RPC calls
+	// to peers.
+	server *Server
+
+	// storage is used to persist state.
+	storage Storage
+
+	// commitChan is the channel where this CM is going to report committed log
+	// entries. It's passed in by the client during construction.
+	commitChan chan<- CommitEntry
+
+	// newCommitReadyChan is an internal notification channel used by goroutines
+	// that commit new entries to the log to notify that these entries may be sent
+	// on commitChan.
+	newCommitReadyChan chan struct{}
+
+	// triggerAEChan is an internal notification channel used to trigger
+	// sending new AEs to followers when interesting changes occurred.
+	triggerAEChan chan struct{}
+
+	// persistPending is set when entries were appended to the log by Submit
+	// but not persisted yet; the next round of AEs persists them.
+	persistPending bool
+
+	// Persistent Raft state on all servers
+	currentTerm int
+	votedFor    string
+	log         []LogEntry
+
+	// snapshotIndex and snapshotTerm describe the last entry covered by the
+	// latest snapshot. Entries up to snapshotIndex have been discarded from
+	// log, so log[0] holds the entry at index snapshotIndex+1. Both are -1
+	// until the first snapshot is taken.
+	snapshotIndex int
+	snapshotTerm  int
+
+	// snapshotConfig is the configuration in effect at snapshotIndex, or the
+	// bootstrap configuration until a snapshot is taken.
+	snapshotConfig Configuration
+
+	// pendingSnapshot is set when a snapshot newer than lastApplied has to be
+	// delivered to the client before any further log entries.
+	pendingSnapshot bool
+
+	// Volatile Raft state on all servers
+	commitIndex        int
+	lastApplied        int
+	state              CMState
+	electionResetEvent time.Time
+
+	// Volatile Raft state on leaders
+	nextIndex  map[string]int
+	matchIndex map[string]int
+
+	// heartbeatRound numbers the rounds of AEs sent by leaderSendAEs, and
+	// ackedRound holds the latest round each peer has replied to in the
+	// current term. ReadIndex uses them to confirm leadership.
+	heartbeatRound int
+	ackedRound     map[string]int
+
+	// progress tracks pipelining and flow control per peer.
+	progress map[string]*followerProgress
+
+	// leaderSince is when this CM became leader, and lastAck holds when each
+	// peer last replied to it in the current term. checkQuorum uses them.
+	leaderSince time.Time
+	lastAck     map[string]time.Time
+
+	// leaderContact is when this CM last heard from the leader of its
+	// current term. Pre-votes aren't granted while it's recent.
+	leaderContact time.Time
+
+	// transferTarget is the peer leadership is being transferred to, if any.
+	// The leader accepts no new commands while it's set.
+	transferTarget string
+
+	// LeaderID of the leader for the current term
+	leaderID string
+}
+
+// NewConsensusModule creates a new CM with the given ID, bootstrap
+// configuration and server. The bootstrap configuration is only used until
+// the log or a snapshot provides a newer one; a server joining an existing
+// cluster passes an empty configuration and waits to be added. The ready
+// channel signals the CM that all peers are connected, and it's safe to
+// start its state machine. commitChan is going to be used by the CM to send
+// log entries that have been committed by the Raft cluster.
+func NewConsensusModule(id string, config Configuration, server *Server, storage Storage, ready <-chan interface{}, commitChan chan<- CommitEntry) *ConsensusModule {
+	cm := new(ConsensusModule)
+	cm.id = id
+	cm.server = server
+	cm.storage = storage
+	cm.commitChan = commitChan
+	cm.newCommitReadyChan = make(chan struct{}, 16)
+	cm.triggerAEChan = make(chan struct{}, 1)
+	cm.state = Follower
+	cm.votedFor = ""
+	cm.commitIndex = -1
+	cm.lastApplied = -1
+	cm.snapshotIndex = -1
+	cm.snapshotTerm = -1
+	cm.snapshotConfig = config
+	cm.nextIndex = make(map[string]int)
+	cm.matchIndex = make(map[string]int)
+	cm.ackedRound = make(map[string]int)
+	cm.lastAck = make(map[string]time.Time)
+	cm.progress = make(map[string]*followerProgress)
+
+	if cm.storage.HasData() {
+		cm.restoreFromStorage()
+	}
+	cm.reloadConfig()
+	if cm.snapshotIndex >= 0 {
+		// Everything in the snapshot is committed; hand it to the client first
+		// so its state machine starts from there.
+		cm.commitIndex = cm.snapshotIndex
+		cm.pendingSnapshot = true
+		cm.newCommitReadyChan <- struct{}{}
+	}
+
+	go func() {
+		// The CM is dormant until ready is signaled; then, it starts a countdown
+		// for leader election.
+		<-ready
+		cm.mu.Lock()
+		cm.electionResetEvent = time.Now()
+		cm.mu.Unlock()
+		cm.runElectionTimer()
+	}()
+
+	go cm.commitChanSender()
+	return cm
+}
+
+// Report reports the state of this CM.
+func (cm *ConsensusModule) Report() (id string, term int, isLeader bool) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	return cm.id, cm.currentTerm, cm.state == Leader
+}
+
+// LogSize returns the number of entries currently held in the log, i.e. not
+// yet compacted into a snapshot.
+func (cm *ConsensusModule) LogSize() int {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	return len(cm.log)
+}
+
+// Snapshot informs the CM that the client's state machine has captured every
+// entry up to and including index in snapshot. The CM persists the snapshot
+// and discards the log entries it covers. index must already have been
+// delivered on the commit channel.
+func (cm *ConsensusModule) Snapshot(index int, snapshot []byte) {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if index <= cm.snapshotIndex || index > cm.lastApplied {
+		cm.dlog("Snapshot at %d ignored [snapshotIndex=%d, lastApplied=%d]", index, cm.snapshotIndex, cm.lastApplied)
+		return
+	}
+	term := cm.termAt(index)
+	cm.compactLog(index, term)
+	cm.persistSnapshot(snapshot)
+	cm.persistToStorage()
+	cm.dlog("Snapshot taken at index=%d term=%d; %d entries left in log", index, term, len(cm.log))
+}
+
+// Submit submits a new command to the CM. This function doesn't block; clients
+// read the commit channel passed in the constructor to be notified of new
+// committed entries. It returns true iff this CM is the leader - in which case
+// the command is accepted. If false is returned, the client will have to find
+// a different CM to submit this command to. Commands are also refused while
+// leadership is being transferred.
+func (cm *ConsensusModule) Submit(id string, command OpsType, payload []byte) bool {
+	cm.mu.Lock()
+	cm.dlog("Submit received by %v: %v", cm.state, command)
+	if cm.state == Leader && cm.transferTarget == "" {
+		cm.log = append(cm.log, LogEntry{ID: id, Command: command, Payload: payload, Term: cm.currentTerm})
+		cm.persistPending = true
+		cm.dlog("... log=%v", cm.log)
+		cm.mu.Unlock()
+		// Commands submitted before the next round starts are sent, and
+		// persisted, together.
+		cm.triggerAE()
+		return true
+	}
+
+	cm.mu.Unlock()
+	return false
+}
+
+// Stop stops this CM, cleaning up its state. This method returns quickly, but
+// it may take a bit of time (up to ~election timeout) for all goroutines to
+// exit.
+func (cm *ConsensusModule) Stop() {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	cm.state = Dead
+	cm.dlog("becomes Dead")
+	close(cm.newCommitReadyChan)
+}
+
+// restoreFromStorage restores the persistent state of this CM from storage.
+// It should be called during constructor, before any concurrency concerns.
+func (cm *ConsensusModule) restoreFromStorage() {
+	if termData, found := cm.storage.Get("currentTerm"); found {
+		d := gob.NewDecoder(bytes.NewBuffer(termData))
+		if err := d.Decode(&cm.currentTerm); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		log.Fatal("currentTerm not found in storage")
+	}
+	if votedData, found := cm.storage.Get("votedFor"); found {
+		d := gob.NewDecoder(bytes.NewBuffer(votedData))
+		if err := d.Decode(&cm.votedFor); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		log.Fatal("votedFor not found in storage")
+	}
+	if logData, found := cm.storage.Get("log"); found {
+		d := gob.NewDecoder(bytes.NewBuffer(logData))
+		if err := d.Decode(&cm.log); err != nil {
+			log.Fatal(err)
+		}
+	} else {
+		log.Fatal("log not found in storage")
+	}
+	if indexData, found := cm.storage.Get("snapshotIndex"); found {
+		d := gob.NewDecoder(bytes.NewBuffer(indexData))
+		if err := d.Decode(&cm.snapshotIndex); err != nil {
+			log.Fatal(err)
+		}
+	}
+	if termData, found := cm.storage.Get("snapshotTerm"); found {
+		d := gob.NewDecoder(bytes.NewBuffer(termData))
+		if err := d.Decode(&cm.snapshotTerm); err != nil {
+			log.Fatal(err)
+		}
+	}
+	snapshot, found := cm.readSnapshot()
+	if !found {
+		return
+	}
+	if snapshot.Index > cm.snapshotIndex {
+		// We crashed after persisting a newer snapshot but before persisting the
+		// log that goes with it. The snapshot wins; trim the log to match.
+		if snapshot.Index <= cm.lastIndex() && cm.termAt(snapshot.Index) == snapshot.Term {
+			cm.compactLog(snapshot.Index, snapshot.Term)
+		} else {
+			cm.log = nil
+			cm.snapshotIndex = snapshot.Index
+			cm.snapshotTerm = snapshot.Term
+		}
+	}
+	cm.snapshotConfig = snapshot.Config
+}
+
+// persistToStorage saves all of CM's persistent state in cm.storage.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) persistToStorage() {
+	cm.persistPending = false
+
+	var termData bytes.Buffer
+	if err := gob.NewEncoder(&termData).Encode(cm.currentTerm); err != nil {
+		log.Fatal(err)
+	}
+	cm.storage.Set("currentTerm", termData.Bytes())
+
+	var votedData bytes.Buffer
+	if err := gob.NewEncoder(&votedData).Encode(cm.votedFor); err != nil {
+		log.Fatal(err)
+	}
+	cm.storage.Set("votedFor", votedData.Bytes())
+
+	var logData bytes.Buffer
+	if err := gob.NewEncoder(&logData).Encode(cm.log); err != nil {
+		log.Fatal(err)
+	}
+	cm.storage.Set("log", logData.Bytes())
+
+	var indexData bytes.Buffer
+	if err := gob.NewEncoder(&indexData).Encode(cm.snapshotIndex); err != nil {
+		log.Fatal(err)
+	}
+	cm.storage.Set("snapshotIndex", indexData.Bytes())
+
+	var snapshotTermData bytes.Buffer
+	if err := gob.NewEncoder(&snapshotTermData).Encode(cm.snapshotTerm); err != nil {
+		log.Fatal(err)
+	}
+	cm.storage.Set("snapshotTerm", snapshotTermData.Bytes())
+}
+
+// Snapshot is a state machine snapshot as kept in storage.
+type Snapshot struct {
+	Index  int
+	Term   int
+	Config Configuration
+	Data   []byte
+}
+
+// persistSnapshot saves snapshot as the latest snapshot, covering the log up
+// to cm.snapshotIndex. It must be called before persistToStorage so that a
+// crash in between never leaves a compacted log without its snapshot.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) persistSnapshot(data []byte) {
+	var snapshotData bytes.Buffer
+	if err := gob.NewEncoder(&snapshotData).Encode(Snapshot{Index: cm.snapshotIndex, Term: cm.snapshotTerm, Config: cm.snapshotConfig, Data: data}); err != nil {
+		log.Fatal(err)
+	}
+	cm.storage.Set("snapshot", snapshotData.Bytes())
+}
+
+// readSnapshot returns the latest persisted snapshot, if any.
+func (cm *ConsensusModule) readSnapshot() (Snapshot, bool) {
+	var snapshot Snapshot
+	snapshotData, found := cm.storage.Get("snapshot")
+	if !found {
+		return snapshot, false
+	}
+	if err := gob.NewDecoder(bytes.NewBuffer(snapshotData)).Decode(&snapshot); err != nil {
+		log.Fatal(err)
+	}
+	return snapshot, true
+}
+
+// compactLog discards all log entries up to and including index, which must
+// have term term, and records them as covered by the snapshot. The retained
+// suffix is copied so slices of the old log handed out earlier stay valid.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) compactLog(index, term int) {
+	cm.snapshotConfig = cm.configAt(index)
+	var retained []LogEntry
+	if index < cm.lastIndex() {
+		retained = append(retained, cm.log[cm.logPos(index+1):]...)
+	}
+	cm.log = retained
+	cm.snapshotIndex = index
+	cm.snapshotTerm = term
+}
+
+// dlog logs a debugging message is DebugCM > 0.
+func (cm *ConsensusModule) dlog(format string, args ...interface{}) {
+	if DebugCM > 0 {
+		format = fmt.Sprintf("[%s] ", cm.id) + format
+		log.Printf(format, args...)
+	}
+}
+
+// RequestVoteArgs See figure 2 in the paper.
+type RequestVoteArgs struct {
+	Term         int
+	CandidateId  string
+	LastLogIndex int
+	LastLogTerm  int
+
+	// PreVote asks whether the vote would be granted, without the receiver
+	// changing its term or recording a vote (section 9.6 of the Raft
+	// dissertation).
+	PreVote bool
+}
+
+type RequestVoteReply struct {
+	Term        int
+	VoteGranted bool
+}
+
+// RequestVote RPC.
+func (cm *ConsensusModule) RequestVote(args RequestVoteArgs, reply *RequestVoteReply) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if cm.state == Dead {
+		return nil
+	}
+	lastLogIndex, lastLogTerm := cm.lastLogIndexAndTerm()
+	cm.dlog("RequestVote: %+v [currentTerm=%d, votedFor=%s, log index/term=(%d, %d)]", args, cm.currentTerm, cm.votedFor, lastLogIndex, lastLogTerm)
+
+	if args.PreVote {
+		// Grant the pre-vote only if we'd vote for the candidate in the next
+		// term and haven't heard from a live leader ourselves; a server that
+		// can reach the leader has no reason to replace it.
+		heardFromLeader := cm.state == Leader ||
+			(cm.leaderID != "" && time.Since(cm.leaderContact) < electionTimeoutMin)
+		reply.VoteGranted = args.Term > cm.currentTerm && !heardFromLeader &&
+			(args.LastLogTerm > lastLogTerm ||
+				(args.LastLogTerm == lastLogTerm && args.LastLogIndex >= lastLogIndex))
+		reply.Term = cm.currentTerm
+		cm.dlog("... RequestVote pre-vote reply: %+v", reply)
+		return nil
+	}
+
+	if args.Term > cm.currentTerm {
+		cm.dlog("... term out of date in RequestVote")
+		cm.becomeFollower(args.Term)
+	}
+
+	if cm.currentTerm == args.Term &&
+		(cm.votedFor == "" || cm.votedFor == args.CandidateId) &&
+		(args.LastLogTerm > lastLogTerm ||
+			(args.LastLogTerm == lastLogTerm && args.LastLogIndex >= lastLogIndex)) {
+		reply.VoteGranted = true
+		cm.votedFor = args.CandidateId
+		cm.electionResetEvent = time.Now()
+	} else {
+		reply.VoteGranted = false
+	}
+	reply.Term = cm.currentTerm
+	cm.persistToStorage()
+	cm.dlog("... RequestVote reply: %+v", reply)
+	return nil
+}
+
+// AppendEntriesArgs See figure 2 in the paper.
+type AppendEntriesArgs struct {
+	Term     int
+	LeaderId string
+
+	PrevLogIndex int
+	PrevLogTerm  int
+	Entries      []LogEntry
+	LeaderCommit int
+}
+
+type AppendEntriesReply struct {
+	Term    int
+	Success bool
+
+	// Faster conflict resolution optimization (described near the end of section
+	// 5.3 in the paper.)
+	ConflictIndex int
+	ConflictTerm  int
+}
+
+func (cm *ConsensusModule) AppendEntries(args AppendEntriesArgs, reply *AppendEntriesReply) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if cm.state == Dead {
+		return nil
+	}
+	//cm.dlog("AppendEntries: %+v", args)
+	savedCurrentTerm := cm.currentTerm
+	logChanged := false
+
+	if args.Term > cm.currentTerm {
+		cm.dlog("... term out of date in AppendEntries")
+		cm.becomeFollower(args.Term)
+	}
+
+	reply.Success = false
+	if args.Term == cm.currentTerm {
+		cm.leaderID = args.LeaderId
+		if cm.state != Follower {
+			cm.becomeFollower(args.Term)
+		}
+		cm.electionResetEvent = time.Now()
+		cm.leaderContact = cm.electionResetEvent
+
+		// Entries up to snapshotIndex are committed and already covered by our
+		// snapshot, so they match the leader's by definition. Skip that overlap.
+		if args.PrevLogIndex < cm.snapshotIndex {
+			skip := min(cm.snapshotIndex-args.PrevLogIndex, len(args.Entries))
+			args.Entries = args.Entries[skip:]
+			args.PrevLogIndex = cm.snapshotIndex
+			args.PrevLogTerm = cm.snapshotTerm
+		}
+
+		// Does our log contain an entry at PrevLogIndex whose term matches
+		// PrevLogTerm? Note that in the extreme case of PrevLogIndex=-1 this is
+		// vacuously true.
+		if args.PrevLogIndex == -1 ||
+			(args.PrevLogIndex <= cm.lastIndex() && args.PrevLogTerm == cm.termAt(args.PrevLogIndex)) {
+			reply.Success = true
+
+			// Find an insertion point - where there's a term mismatch between
+			// the existing log starting at PrevLogIndex+1 and the new entries sent
+			// in the RPC.
+			logInsertIndex := args.PrevLogIndex + 1
+			newEntriesIndex := 0
+
+			for {
+				if logInsertIndex > cm.lastIndex() || newEntriesIndex >= len(args.Entries) {
+					break
+				}
+				if cm.termAt(logInsertIndex) != args.Entries[newEntriesIndex].Term {
+					break
+				}
+				logInsertIndex++
+				newEntriesIndex++
+			}
+			// At the end of this loop:
+			// - logInsertIndex points at the end of the log, or an index where the
+			//   term mismatches with an entry from the leader
+			// - newEntriesIndex points at the end of Entries, or an index where the
+			//   term mismatches with the corresponding log entry
+			if newEntriesIndex < len(args.Entries) {
+				cm.dlog("... inserting entries %v from index %d", args.Entries[newEntriesIndex:], logInsertIndex)
+				cm.log = append(cm.log[:cm.logPos(logInsertIndex)], args.Entries[newEntriesIndex:]...)
+				cm.reloadConfig()
+				logChanged = true
+				cm.dlog("... log is now: %v", cm.log)
+			}
+
+			// Set commit index. Only entries the leader has vouched for in this
+			// AE can be committed; anything in our log past them may still be
+			// replaced.
+			if newCommitIndex := min(args.LeaderCommit, args.PrevLogIndex+len(args.Entries)); newCommitIndex > cm.commitIndex {
+				cm.commitIndex = newCommitIndex
+				cm.dlog("... setting commitIndex=%d", cm.commitIndex)
+				cm.newCommitReadyChan <- struct{}{}
+			}
+		} else {
+			// No match for PrevLogIndex/PrevLogTerm. Populate
+			// ConflictIndex/ConflictTerm to help the leader bring us up to date
+			// quickly.
+			if args.PrevLogIndex > cm.lastIndex() {
+				reply.ConflictIndex = cm.lastIndex() + 1
+				reply.ConflictTerm = -1
+			} else {
+				// PrevLogIndex points within our log, but PrevLogTerm doesn't match
+				// cm.log[PrevLogIndex].
+				reply.ConflictTerm = cm.termAt(args.PrevLogIndex)
+
+				var i int
+				for i = args.PrevLogIndex - 1; i > cm.snapshotIndex; i-- {
+					if cm.termAt(i) != reply.ConflictTerm {
+						break
+					}
+				}
+				reply.ConflictIndex = i + 1
+			}
+		}
+	}
+
+	reply.Term = cm.currentTerm
+	if logChanged || cm.currentTerm != savedCurrentTerm {
+		cm.persistToStorage()
+	}
+	//cm.dlog("AppendEntries reply: %+v", *reply)
+	return nil
+}
+
+// InstallSnapshotArgs See figure 13 in the paper. The snapshot is always sent
+// in a single chunk.
+type InstallSnapshotArgs struct {
+	Term     int
+	LeaderId string
+
+	LastIncludedIndex int
+	LastIncludedTerm  int
+	Config            Configuration
+	Data              []byte
+}
+
+type InstallSnapshotReply struct {
+	Term int
+}
+
+// InstallSnapshot RPC. Used by the leader to bring a follower up to date when
+// the entries it needs have already been compacted.
+func (cm *ConsensusModule) InstallSnapshot(args InstallSnapshotArgs, reply *InstallSnapshotReply) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if cm.state == Dead {
+		return nil
+	}
+	cm.dlog("InstallSnapshot: [term=%d, leader=%s, lastIncluded index/term=(%d, %d), %d bytes]", args.Term, args.LeaderId, args.LastIncludedIndex, args.LastIncludedTerm, len(args.Data))
+
+	if args.Term > cm.currentTerm {
+		cm.dlog("... term out of date in InstallSnapshot")
+		cm.becomeFollower(args.Term)
+	}
+
+	reply.Term = cm.currentTerm
+	if args.Term < cm.currentTerm {
+		return nil
+	}
+	cm.leaderID = args.LeaderId
+	if cm.state != Follower {
+		cm.becomeFollower(args.Term)
+	}
+	cm.electionResetEvent = time.Now()
+	cm.leaderContact = cm.electionResetEvent
+
+	if args.LastIncludedIndex <= cm.snapshotIndex {
+		cm.dlog("... stale snapshot, already have snapshotIndex=%d", cm.snapshotIndex)
+		return nil
+	}
+
+	// Retain any log suffix that follows the snapshot; otherwise the snapshot
+	// replaces our whole log.
+	if args.LastIncludedIndex <= cm.lastIndex() && cm.termAt(args.LastIncludedIndex) == args.LastIncludedTerm {
+		cm.compactLog(args.LastIncludedIndex, args.LastIncludedTerm)
+	} else {
+		cm.log = nil
+		cm.snapshotIndex = args.LastIncludedIndex
+		cm.snapshotTerm = args.LastIncludedTerm
+		cm.snapshotConfig = args.Config
+	}
+	cm.reloadConfig()
+	cm.persistSnapshot(args.Data)
+	cm.persistToStorage()
+
+	if args.LastIncludedIndex > cm.commitIndex {
+		cm.commitIndex = args.LastIncludedIndex
+	}
+	if args.LastIncludedIndex > cm.lastApplied {
+		cm.pendingSnapshot = true
+		cm.newCommitReadyChan <- struct{}{}
+	}
+	cm.dlog("... installed snapshot; snapshotIndex=%d, commitIndex=%d, log=%v", cm.snapshotIndex, cm.commitIndex, cm.log)
+	return nil
+}
+
+const (
+	// electionTimeoutMin and electionTimeoutMax bound the randomized election
+	// timeout.
+	electionTimeoutMin = 150 * time.Millisecond
+	electionTimeoutMax = 300 * time.Millisecond
+
+	// checkQuorumTimeout is how long a leader keeps its leadership without
+	// hearing from a majority of the configuration.
+	checkQuorumTimeout = electionTimeoutMax
+)
+
+// electionTimeout generates a pseudo-random election timeout duration.
+func (cm *ConsensusModule) electionTimeout() time.Duration {
+	// If RAFT_FORCE_MORE_REELECTION is set, stress-test by deliberately
+	// generating a hard-coded number very often. This will create collisions
+	// between different servers and force more re-elections.
+	if len(os.Getenv("RAFT_FORCE_MORE_REELECTION")) > 0 && rand.Intn(3) == 0 {
+		return electionTimeoutMin
+	} else {
+		return electionTimeoutMin + time.Duration(rand.Int63n(int64(electionTimeoutMax-electionTimeoutMin)))
+	}
+}
+
+// runElectionTimer implements an election timer. It should be launched whenever
+// we want to start a timer towards becoming a candidate in a new election.
+//
+// This function is blocking and should be launched in a separate goroutine;
+// it's designed to work for a single (one-shot) election timer, as it exits
+// whenever the CM state changes from follower/candidate or the term changes.
+func (cm *ConsensusModule) runElectionTimer() {
+	timeoutDuration := cm.electionTimeout()
+	cm.mu.Lock()
+	termStarted := cm.currentTerm
+	cm.mu.Unlock()
+	cm.dlog("election timer started (%v), term=%d", timeoutDuration, termStarted)
+
+	// This loops until either:
+	// - we discover the election timer is no longer needed, or
+	// - the election timer expires and this CM becomes a candidate
+	// In a follower, this typically keeps running in the background for the
+	// duration of the CM's lifetime.
+	ticker := time.NewTicker(10 * time.Millisecond)
+	defer ticker.Stop()
+	for {
+		<-ticker.C
+
+		cm.mu.Lock()
+		if cm.state != Candidate && cm.state != Follower {
+			cm.dlog("in election timer state=%s, bailing out", cm.state)
+			cm.mu.Unlock()
+			return
+		}
+
+		if termStarted != cm.currentTerm {
+			cm.dlog("in election timer term changed from %d to %d, bailing out", termStarted, cm.currentTerm)
+			cm.mu.Unlock()
+			return
+		}
+
+		// A server that isn't a voter (yet, or anymore) never starts elections;
+		// it only follows the leader that replicates to it.
+		if !cm.config.IsVoter(cm.id) {
+			cm.electionResetEvent = time.Now()
+			cm.mu.Unlock()
+			continue
+		}
+
+		// Start an election if we haven't heard from a leader or haven't voted for
+		// someone for the duration of the timeout.
+		if elapsed := time.Since(cm.electionResetEvent); elapsed >= timeoutDuration {
+			cm.startPreVote()
+			cm.mu.Unlock()
+			return
+		}
+		cm.mu.Unlock()
+	}
+}
+
+// startPreVote runs the pre-vote phase (section 9.6 of the Raft dissertation)
+// before starting an election: it asks the peers whether they would vote for
+// this CM in the next term, without anyone's term changing. The election is
+// only started once a majority would, so a server that was partitioned away
+// can't make a healthy leader step down by bumping the term when it returns.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) startPreVote() {
+	savedCurrentTerm := cm.currentTerm
+	cm.electionResetEvent = time.Now()
+	started := cm.electionResetEvent
+	cm.dlog("starts pre-vote for term %d", savedCurrentTerm+1)
+
+	votes := map[string]bool{cm.id: true}
+	if cm.isQuorum(func(id string) bool { return votes[id] }) {
+		// We're the only member.
+		cm.startElection()
+		return
+	}
+
+	savedLastLogIndex, savedLastLogTerm := cm.lastLogIndexAndTerm()
+	args := RequestVoteArgs{
+		Term:         savedCurrentTerm + 1,
+		CandidateId:  cm.id,
+		LastLogIndex: savedLastLogIndex,
+		LastLogTerm:  savedLastLogTerm,
+		PreVote:      true,
+	}
+	won := false
+	for _, peerId := range cm.peerIds {
+		go func(peerId string) {
+			cm.dlog("sending pre-vote RequestVote to %s: %+v", peerId, args)
+			var reply RequestVoteReply
+			if err := cm.server.Call(peerId, "ConsensusModule.RequestVote", args, &reply); err != nil {
+				return
+			}
+			cm.mu.Lock()
+			defer cm.mu.Unlock()
+			cm.dlog("received pre-vote RequestVoteReply %+v", reply)
+
+			// Give up on this round if an election was started meanwhile, or
+			// a leader showed up.
+			if won || cm.currentTerm != savedCurrentTerm || (cm.state != Follower && cm.state != Candidate) || cm.leaderContact.After(started) {
+				return
+			}
+			if reply.Term > savedCurrentTerm {
+				cm.dlog("term out of date in pre-vote RequestVoteReply")
+				cm.becomeFollower(reply.Term)
+				return
+			}
+			if reply.VoteGranted {
+				votes[peerId] = true
+				if cm.isQuorum(func(id string) bool { return votes[id] }) {
+					won = true
+					cm.dlog("wins pre-vote with %d votes", len(votes))
+					cm.startElection()
+				}
+			}
+		}(peerId)
+	}
+
+	// Run another election timer, in case the pre-vote doesn't succeed.
+	go cm.runElectionTimer()
+}
+
+// startElection starts a new election with this CM as a candidate.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) startElection() {
+	cm.state = Candidate
+	cm.leaderID = ""
+	cm.currentTerm += 1
+	savedCurrentTerm := cm.currentTerm
+	cm.electionResetEvent = time.Now()
+	cm.votedFor = cm.id
+	cm.dlog("becomes Candidate (currentTerm=%d); log=%v", savedCurrentTerm, cm.log)
+
+	votes := map[string]bool{cm.id: true}
+	if cm.isQuorum(func(id string) bool { return votes[id] }) {
+		// We're the only member.
+		cm.leaderID = cm.id
+		cm.dlog("wins election as the only member")
+		cm.startLeader()
+		return
+	}
+
+	// Send RequestVote RPCs to all other servers concurrently.
+	for _, peerId := range cm.peerIds {
+		go func(peerId string) {
+			cm.mu.Lock()
+			savedLastLogIndex, savedLastLogTerm := cm.lastLogIndexAndTerm()
+			cm.mu.Unlock()
+
+			args := RequestVoteArgs{
+				Term:         savedCurrentTerm,
+				CandidateId:  cm.id,
+				LastLogIndex: savedLastLogIndex,
+				LastLogTerm:  savedLastLogTerm,
+			}
+
+			cm.dlog("sending RequestVote to %s: %+v", peerId, args)
+			var reply RequestVoteReply
+			if err := cm.server.Call(peerId, "ConsensusModule.RequestVote", args, &reply); err == nil {
+				cm.mu.Lock()
+				defer cm.mu.Unlock()
+				cm.dlog("received RequestVoteReply %+v", reply)
+
+				if cm.state != Candidate {
+					cm.dlog("while waiting for reply, state = %v", cm.state)
+					return
+				}
+
+				if reply.Term > savedCurrentTerm {
+					cm.dlog("term out of date in RequestVoteReply")
+					cm.becomeFollower(reply.Term)
+					return
+				} else if reply.Term == savedCurrentTerm {
+					if reply.VoteGranted {
+						votes[peerId] = true
+						if cm.isQuorum(func(id string) bool { return votes[id] }) {
+							// Won the election!
+							cm.leaderID = cm.id
+							cm.dlog("wins election with %d votes", len(votes))
+							cm.startLeader()
+							return
+						}
+
+					}
+				}
+			}
+		}(peerId)
+	}
+
+	// Run another election timer, in case this election is not successful.
+	go cm.runElectionTimer()
+}
+
+// stepDown makes a leader a follower in its current term. Unlike
+// becomeFollower it keeps votedFor, since we've already voted for ourselves
+// in this term.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) stepDown() {
+	cm.dlog("steps down in term=%d", cm.currentTerm)
+	cm.state = Follower
+	cm.leaderID = ""
+	cm.electionResetEvent = time.Now()
+
+	go cm.runElectionTimer()
+}
+
+// checkQuorum makes the leader step down if it hasn't heard from a majority
+// of the configuration for checkQuorumTimeout, e.g. because it's been
+// partitioned away. The majority elects a new leader in the meantime, so this
+// stops the old leader from accepting writes it can't commit. Returns true if
+// cm is still the leader.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) checkQuorum() bool {
+	if time.Since(cm.leaderSince) < checkQuorumTimeout {
+		return true
+	}
+	if cm.isQuorum(func(id string) bool { return id == cm.id || time.Since(cm.lastAck[id]) < checkQuorumTimeout }) {
+		return true
+	}
+	cm.dlog("no quorum heard from within %v; stepping down", checkQuorumTimeout)
+	cm.stepDown()
+	return false
+}
+
+// becomeFollower makes cm a follower and resets its state.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) becomeFollower(term int) {
+	cm.dlog("becomes Follower with term=%d; log=%v", term, cm.log)
+	cm.state = Follower
+	cm.currentTerm = term
+	cm.votedFor = ""
+	cm.electionResetEvent = time.Now()
+
+	go cm.runElectionTimer()
+}
+
+// startLeader switches cm into a leader state and begins process of heartbeats.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) startLeader() {
+	cm.state = Leader
+	cm.leaderSince = time.Now()
+	cm.transferTarget = ""
+
+	cm.ackedRound = make(map[string]int)
+	cm.lastAck = make(map[string]time.Time)
+	cm.progress = make(map[string]*followerProgress)
+	for _, peerId := range cm.peerIds {
+		cm.nextIndex[peerId] = cm.lastIndex() + 1
+		cm.matchIndex[peerId] = -1
+	}
+	// Commit a no-op entry right away: until an entry of its own term is
+	// committed, the leader can't tell which entries are committed, which
+	// ReadIndex depends on.
+	cm.log = append(cm.log, LogEntry{ID: common.GenerateUUID(), Command: NoOp, Term: cm.currentTerm})
+	cm.persistToStorage()
+	cm.dlog("becomes Leader; term=%d, nextIndex=%v, matchIndex=%v; log=%v", cm.currentTerm, cm.nextIndex, cm.matchIndex, cm.log)
+
+	// This goroutine runs in the background and sends AEs to peers:
+	// * Whenever something is sent on triggerAEChan
+	// * ... Or every 50 ms, if no events occur on triggerAEChan
+	go func(heartbeatTimeout time.Duration) {
+		// Immediately send AEs to peers.
+		cm.leaderSendAEs()
+
+		t := time.NewTimer(heartbeatTimeout)
+		defer t.Stop()
+		for {
+			doSend := false
+			select {
+			case <-t.C:
+				doSend = true
+
+				// Reset timer to fire again after heartbeatTimeout.
+				t.Stop()
+				t.Reset(heartbeatTimeout)
+			case _, ok := <-cm.triggerAEChan:
+				if ok {
+					doSend = true
+				} else {
+					return
+				}
+
+				// Reset timer for heartbeatTimeout.
+				if !t.Stop() {
+					<-t.C
+				}
+				t.Reset(heartbeatTimeout)
+			}
+
+			if doSend {
+				// If this isn't a leader anymore, stop the heartbeat loop.
+				cm.mu.Lock()
+				if cm.state != Leader {
+					cm.mu.Unlock()
+					return
+				}
+				cm.mu.Unlock()
+				cm.leaderSendAEs()
+			}
+		}
+	}(50 * time.Millisecond)
+}
+
+// leaderAdvanceCommitIndex moves commitIndex to the latest entry of the
+// current term replicated on a majority of the configuration, and notifies
+// the client if it changed. A leader that is no longer part of a committed
+// configuration steps down. Returns true if commitIndex changed.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) leaderAdvanceCommitIndex() bool {
+	savedCommitIndex := cm.commitIndex
+	for i := cm.commitIndex + 1; i <= cm.lastIndex(); i++ {
+		if cm.termAt(i) == cm.currentTerm {
+			if cm.isQuorum(func(id string) bool { return id == cm.id || cm.matchIndex[id] >= i }) {
+				cm.commitIndex = i
+			}
+		}
+	}
+	if cm.commitIndex == savedCommitIndex {
+		return false
+	}
+	cm.dlog("leader sets commitIndex := %d", cm.commitIndex)
+	// Commit index changed: the leader considers new entries to be committed.
+	// Send new entries on the commit channel to this leader's clients.
+	cm.newCommitReadyChan <- struct{}{}
+	if cm.configIndex <= cm.commitIndex && !cm.config.HasMember(cm.id) {
+		cm.dlog("removed from the configuration; stepping down")
+		cm.stepDown()
+	}
+	return true
+}
+
+// lastLogIndexAndTerm returns the last log index and the last log entry's term
+// (or -1 if there's no log) for this server.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) lastLogIndexAndTerm() (int, int) {
+	lastIndex := cm.lastIndex()
+	return lastIndex, cm.termAt(lastIndex)
+}
+
+// lastIndex returns the index of the last entry in the log, counting entries
+// already compacted into the snapshot, or -1 if there are none.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) lastIndex() int {
+	return cm.snapshotIndex + len(cm.log)
+}
+
+// logPos translates the log index into a position in cm.log.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) logPos(index int) int {
+	return index - cm.snapshotIndex - 1
+}
+
+// termAt returns the term of the entry at index, which must be either
+// snapshotIndex or an index still held in cm.log.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) termAt(index int) int {
+	if index == cm.snapshotIndex {
+		return cm.snapshotTerm
+	}
+	return cm.log[cm.logPos(index)].Term
+}
+
+// commitChanSender is responsible for sending committed entries on
+// cm.commitChan. It watches newCommitReadyChan for notifications and calculates
+// which new entries are ready to be sent. This method should run in a separate
+// background goroutine; cm.commitChan may be buffered and will limit how fast
+// the client consumes new committed entries. Returns when newCommitReadyChan is
+// closed.
+func (cm *ConsensusModule) commitChanSender() {
+	for range cm.newCommitReadyChan {
+		// Find which entries we have to apply.
+		cm.mu.Lock()
+		savedTerm := cm.currentTerm
+		var snapshot *Snapshot
+		if cm.pendingSnapshot {
+			cm.pendingSnapshot = false
+			if s, found := cm.readSnapshot(); found && s.Index > cm.lastApplied {
+				snapshot = &s
+				cm.lastApplied = s.Index
+			}
+		}
+		savedLastApplied := cm.lastApplied
+		var entries []LogEntry
+		if cm.commitIndex > cm.lastApplied {
+			entries = cm.log[cm.logPos(cm.lastApplied+1) : cm.logPos(cm.commitIndex)+1]
+			cm.lastApplied = cm.commitIndex
+		}
+		cm.mu.Unlock()
+		cm.dlog("commitChanSender entries=%v, savedLastApplied=%d", entries, savedLastApplied)
+
+		if snapshot != nil {
+			cm.commitChan <- CommitEntry{
+				Index:         snapshot.Index,
+				Term:          snapshot.Term,
+				SnapshotValid: true,
+				Snapshot:      snapshot.Data,
+			}
+		}
+
+		for i, entry := range entries {
+			cm.commitChan <- CommitEntry{
+				ID:      entry.ID,
+				Command: entry.Command,
+				Payload: entry.Payload,
+				Index:   savedLastApplied + i + 1,
+				Term:    savedTerm,
+			}
+		}
+	}
+	cm.dlog("commitChanSender done")
+}
+
+func min(a, b int) int {
+	if a < b {
+		return a
+	}
+	return b
+}
@@ -0,0 +1,189 @@
+// Linearizable reads for the Raft Consensus Module.
+//
+// Reads don't go through the log. Instead, the leader hands out a read index
+// (section 6.4 of the Raft dissertation): its commit index at the time of the
+// request, once it has confirmed with a quorum that it's still the leader.
+// Any server whose state machine has applied the read index can then serve
+// the read locally and observe every write committed before it started.
+package raft
+
+import (
+	"context"
+	"fmt"
+	"sync"
+	"time"
+)
+
+// readIndexPollInterval is how often a pending read index checks whether the
+// leader has heard back from a quorum.
+const readIndexPollInterval = 5 * time.Millisecond
+
+// ReadIndex returns the index the client's state machine has to reach before
+// serving a linearizable read. The leader computes it itself; followers ask
+// the leader for it.
+func (cm *ConsensusModule) ReadIndex(ctx context.Context) (int, error) {
+	cm.mu.Lock()
+	state, leaderID := cm.state, cm.leaderID
+	cm.mu.Unlock()
+
+	if state == Leader {
+		return cm.leaderReadIndex(ctx)
+	}
+	if leaderID == "" {
+		return -1, fmt.Errorf("%s is %w. leader unknown", cm.id, ErrNotLeader)
+	}
+
+	args := ReadIndexArgs{ReaderId: cm.id}
+	var reply ReadIndexReply
+	call := make(chan error, 1)
+	go func() {
+		call <- cm.server.Call(leaderID, "ConsensusModule.ReadIndex", args, &reply)
+	}()
+	select {
+	case err := <-call:
+		if err != nil {
+			return -1, fmt.Errorf("exception while requesting read index from %s. %v", leaderID, err)
+		}
+	case <-ctx.Done():
+		return -1, fmt.Errorf("exception while requesting read index from %s. %v", leaderID, ctx.Err())
+	}
+	if !reply.Success {
+		return -1, fmt.Errorf("%s is %w. leader: %q", leaderID, ErrNotLeader, reply.LeaderId)
+	}
+	cm.dlog("read index %d from %s", reply.Index, leaderID)
+	return reply.Index, nil
+}
+
+// leaderReadIndex implements ReadIndex on the leader: it records the commit
+// index, then waits for a heartbeat round sent after that to be acknowledged
+// by a quorum, so that no other leader can have committed entries the read
+// wouldn't see.
+func (cm *ConsensusModule) leaderReadIndex(ctx context.Context) (int, error) {
+	ticker := time.NewTicker(readIndexPollInterval)
+	defer ticker.Stop()
+
+	// A new leader doesn't know which entries are committed until it commits
+	// one of its own term; startLeader appends a no-op entry for this.
+	cm.mu.Lock()
+	for cm.state == Leader && cm.termAt(cm.commitIndex) != cm.currentTerm {
+		cm.mu.Unlock()
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return -1, fmt.Errorf("exception while waiting for the leader to commit in its term. %v", ctx.Err())
+		}
+		cm.mu.Lock()
+	}
+	if cm.state != Leader {
+		leaderID := cm.leaderID
+		cm.mu.Unlock()
+		return -1, fmt.Errorf("%s is %w. leader: %q", cm.id, ErrNotLeader, leaderID)
+	}
+	readIndex := cm.commitIndex
+	savedCurrentTerm := cm.currentTerm
+	round := cm.heartbeatRound + 1
+	cm.mu.Unlock()
+
+	// If a round is already pending, it starts after readIndex was recorded
+	// too, so its acknowledgements will do.
+	cm.triggerAE()
+
+	for {
+		cm.mu.Lock()
+		if cm.state != Leader || cm.currentTerm != savedCurrentTerm {
+			leaderID := cm.leaderID
+			cm.mu.Unlock()
+			return -1, fmt.Errorf("%s is %w. leader: %q", cm.id, ErrNotLeader, leaderID)
+		}
+		if cm.isQuorum(func(id string) bool { return id == cm.id || cm.ackedRound[id] >= round }) {
+			cm.mu.Unlock()
+			cm.dlog("read index %d confirmed at heartbeat round %d", readIndex, round)
+			return readIndex, nil
+		}
+		cm.mu.Unlock()
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return -1, fmt.Errorf("exception while confirming leadership for read index %d. %v", readIndex, ctx.Err())
+		}
+	}
+}
+
+type ReadIndexArgs struct {
+	ReaderId string
+}
+
+type ReadIndexReply struct {
+	Success  bool
+	Index    int
+	LeaderId string
+}
+
+// readIndexRPCTimeout bounds how long a follower's ReadIndex RPC may wait for
+// the leader to confirm its leadership.
+const readIndexRPCTimeout = time.Second
+
+// ReadIndexRPC serves ReadIndex requests from followers. Success is false if
+// this CM isn't the leader; LeaderId then carries its best guess.
+func (cm *ConsensusModule) ReadIndexRPC(args ReadIndexArgs, reply *ReadIndexReply) error {
+	ctx, cancel := context.WithTimeout(context.Background(), readIndexRPCTimeout)
+	defer cancel()
+	cm.mu.Lock()
+	if cm.state == Dead {
+		cm.mu.Unlock()
+		return nil
+	}
+	reply.LeaderId = cm.leaderID
+	cm.mu.Unlock()
+
+	index, err := cm.leaderReadIndex(ctx)
+	if err != nil {
+		cm.dlog("ReadIndex from %s failed: %v", args.ReaderId, err)
+		return nil
+	}
+	reply.Success = true
+	reply.Index = index
+	return nil
+}
+
+// AppliedIndex tracks the index of the last entry the client has applied to
+// its state machine, so that reads can wait for a read index to be reached.
+type AppliedIndex struct {
+	mu      sync.Mutex
+	index   int
+	changed chan struct{}
+}
+
+func NewAppliedIndex() *AppliedIndex {
+	return &AppliedIndex{index: -1, changed: make(chan struct{})}
+}
+
+// Set records that every entry up to and including index has been applied.
+func (a *AppliedIndex) Set(index int) {
+	a.mu.Lock()
+	defer a.mu.Unlock()
+	if index <= a.index {
+		return
+	}
+	a.index = index
+	close(a.changed)
+	a.changed = make(chan struct{})
+}
+
+// Wait blocks until index has been applied or ctx is done.
+func (a *AppliedIndex) Wait(ctx context.Context, index int) error {
+	for {
+		a.mu.Lock()
+		if a.index >= index {
+			a.mu.Unlock()
+			return nil
+		}
+		changed := a.changed
+		a.mu.Unlock()
+		select {
+		case <-changed:
+		case <-ctx.Done():
+			return fmt.Errorf("exception while waiting for index %d to be applied. %v", index, ctx.Err())
+		}
+	}
+}
@@ -0,0 +1,283 @@
+// Log replication from the leader of the Raft Consensus Module.
+//
+// Commands submitted between two rounds of AEs are sent together, in batches
+// of up to maxAppendEntriesBatch entries. Once the leader knows where a
+// follower's log matches its own, it pipelines AEs to it: nextIndex moves past
+// entries as soon as they're sent, and up to maxInflightAppendEntries AEs may
+// be awaiting a reply. When a follower rejects an AE, the leader goes back to
+// probing it with a single AE at a time, using the conflict term and index in
+// the reply to skip a whole term of mismatching entries per round trip.
+package raft
+
+import "time"
+
+const (
+	// maxAppendEntriesBatch bounds the number of entries carried by a single
+	// AE.
+	maxAppendEntriesBatch = 256
+
+	// maxInflightAppendEntries bounds the AEs carrying entries that a
+	// follower may be sent before it replies to the first of them.
+	maxInflightAppendEntries = 4
+)
+
+// followerProgress is the leader's view of replication to a single follower,
+// on top of its nextIndex and matchIndex.
+type followerProgress struct {
+	// probing is set while the leader doesn't know where the follower's log
+	// diverges from its own: after becoming leader, after a rejection and
+	// after a lost AE. Only one AE carrying entries is in flight then, and
+	// nextIndex only moves when it's answered.
+	probing bool
+
+	// inflight counts the AEs carrying entries sent in the current epoch that
+	// haven't been answered. epoch changes whenever nextIndex is reset, so
+	// replies to AEs sent before that are ignored.
+	inflight int
+	epoch    int
+
+	// sendingSnapshot is set while an InstallSnapshot is in flight.
+	sendingSnapshot bool
+}
+
+// progressOf returns the progress of peerId, starting to probe it if it's new.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) progressOf(peerId string) *followerProgress {
+	pr, found := cm.progress[peerId]
+	if !found {
+		pr = &followerProgress{probing: true}
+		cm.progress[peerId] = pr
+	}
+	return pr
+}
+
+// resetProgress makes the leader probe peerId from nextIndex, forgetting the
+// AEs in flight.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) resetProgress(peerId string, nextIndex int) {
+	if nextIndex <= cm.matchIndex[peerId] {
+		nextIndex = cm.matchIndex[peerId] + 1
+	}
+	cm.nextIndex[peerId] = nextIndex
+	pr := cm.progressOf(peerId)
+	pr.probing = true
+	pr.inflight = 0
+	pr.epoch++
+}
+
+// triggerAE asks the heartbeat loop for another round of AEs, unless one is
+// already pending.
+func (cm *ConsensusModule) triggerAE() {
+	select {
+	case cm.triggerAEChan <- struct{}{}:
+	default:
+	}
+}
+
+// leaderSendAEs sends a round of AEs to all peers; their replies are handled
+// as they arrive.
+func (cm *ConsensusModule) leaderSendAEs() {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if cm.state != Leader {
+		return
+	}
+	savedCurrentTerm := cm.currentTerm
+	// Entries submitted since the last round are persisted together, before
+	// they're sent to anyone.
+	if cm.persistPending {
+		cm.persistToStorage()
+	}
+	// Entries may already be committed without hearing from anyone, e.g. when
+	// we're the only member or a member was just removed.
+	cm.leaderAdvanceCommitIndex()
+	if cm.state != Leader || !cm.checkQuorum() {
+		return
+	}
+	cm.heartbeatRound++
+	for _, peerId := range cm.peerIds {
+		cm.leaderReplicateTo(peerId, savedCurrentTerm, cm.heartbeatRound)
+	}
+}
+
+// leaderReplicateTo sends peerId the entries it's missing, as far as flow
+// control allows, and a heartbeat if there's nothing to send. Every peer gets
+// at least one AE per round, which ReadIndex relies on.
+// Expects cm.mu to be locked.
+func (cm *ConsensusModule) leaderReplicateTo(peerId string, savedCurrentTerm int, round int) {
+	pr := cm.progressOf(peerId)
+	if cm.nextIndex[peerId] <= cm.snapshotIndex && !pr.sendingSnapshot {
+		// The entries this peer needs next were compacted away; it has to
+		// catch up from our snapshot instead.
+		pr.sendingSnapshot = true
+		go cm.leaderSendSnapshot(peerId, savedCurrentTerm)
+	}
+
+	sent := false
+	for cm.nextIndex[peerId] > cm.snapshotIndex && cm.nextIndex[peerId] <= cm.lastIndex() {
+		if pr.probing && pr.inflight > 0 || pr.inflight >= maxInflightAppendEntries {
+			break
+		}
+		ni := cm.nextIndex[peerId]
+		pos := cm.logPos(ni)
+		entries := append([]LogEntry(nil), cm.log[pos:min(pos+maxAppendEntriesBatch, len(cm.log))]...)
+		args := AppendEntriesArgs{
+			Term:         savedCurrentTerm,
+			LeaderId:     cm.id,
+			PrevLogIndex: ni - 1,
+			PrevLogTerm:  cm.termAt(ni - 1),
+			Entries:      entries,
+			LeaderCommit: cm.commitIndex,
+		}
+		pr.inflight++
+		if !pr.probing {
+			cm.nextIndex[peerId] = ni + len(entries)
+		}
+		go cm.sendAppendEntries(peerId, args, round, pr.epoch)
+		sent = true
+	}
+	if sent {
+		return
+	}
+
+	// A heartbeat only vouches for entries the peer is known to have, so
+	// that it never fails and carries the commit index up to matchIndex.
+	args := AppendEntriesArgs{
+		Term:         savedCurrentTerm,
+		LeaderId:     cm.id,
+		PrevLogIndex: -1,
+		PrevLogTerm:  -1,
+		LeaderCommit: cm.commitIndex,
+	}
+	if matchIndex := cm.matchIndex[peerId]; matchIndex >= cm.snapshotIndex {
+		args.PrevLogIndex = matchIndex
+		args.PrevLogTerm = cm.termAt(matchIndex)
+	}
+	go cm.sendAppendEntries(peerId, args, round, pr.epoch)
+}
+
+// leaderSendSnapshot sends our latest snapshot to peerId and, if it's
+// accepted, advances the peer's nextIndex/matchIndex past it.
+func (cm *ConsensusModule) leaderSendSnapshot(peerId string, savedCurrentTerm int) {
+	defer func() {
+		cm.mu.Lock()
+		cm.progressOf(peerId).sendingSnapshot = false
+		cm.mu.Unlock()
+	}()
+
+	cm.mu.Lock()
+	snapshot, found := cm.readSnapshot()
+	cm.mu.Unlock()
+	if !found {
+		return
+	}
+
+	args := InstallSnapshotArgs{
+		Term:              savedCurrentTerm,
+		LeaderId:          cm.id,
+		LastIncludedIndex: snapshot.Index,
+		LastIncludedTerm:  snapshot.Term,
+		Config:            snapshot.Config,
+		Data:              snapshot.Data,
+	}
+	cm.dlog("sending InstallSnapshot to %s: index=%d, term=%d", peerId, snapshot.Index, snapshot.Term)
+	var reply InstallSnapshotReply
+	if err := cm.server.Call(peerId, "ConsensusModule.InstallSnapshot", args, &reply); err == nil {
+		cm.mu.Lock()
+		defer cm.mu.Unlock()
+		if reply.Term > cm.currentTerm {
+			cm.dlog("term out of date in InstallSnapshot reply")
+			cm.becomeFollower(reply.Term)
+			return
+		}
+		if cm.state == Leader && savedCurrentTerm == reply.Term {
+			cm.lastAck[peerId] = time.Now()
+			if snapshot.Index > cm.matchIndex[peerId] {
+				cm.matchIndex[peerId] = snapshot.Index
+			}
+			if cm.nextIndex[peerId] <= snapshot.Index {
+				// Pipeline from right after the snapshot.
+				cm.resetProgress(peerId, snapshot.Index+1)
+				cm.progressOf(peerId).probing = false
+			}
+			cm.dlog("InstallSnapshot reply from %s: nextIndex := %d", peerId, cm.nextIndex[peerId])
+		}
+	}
+}
+
+// sendAppendEntries sends a single AE, sent in the given round and epoch of
+// peerId's progress, and handles the reply.
+func (cm *ConsensusModule) sendAppendEntries(peerId string, args AppendEntriesArgs, round int, epoch int) {
+	//cm.dlog("sending AppendEntries to %v: args=%+v", peerId, args)
+	var reply AppendEntriesReply
+	err := cm.server.Call(peerId, "ConsensusModule.AppendEntries", args, &reply)
+
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	pr := cm.progressOf(peerId)
+	current := pr.epoch == epoch
+	if current && len(args.Entries) > 0 && pr.inflight > 0 {
+		pr.inflight--
+	}
+	if cm.state != Leader || cm.currentTerm != args.Term {
+		return
+	}
+	if err != nil {
+		if current && len(args.Entries) > 0 {
+			// Whatever was pipelined after this AE can't be appended by the
+			// peer either; start over from what it's known to have.
+			cm.resetProgress(peerId, cm.matchIndex[peerId]+1)
+		}
+		return
+	}
+	if reply.Term > cm.currentTerm {
+		cm.dlog("term out of date in heartbeat reply")
+		cm.becomeFollower(reply.Term)
+		return
+	}
+	if reply.Term != args.Term {
+		return
+	}
+
+	// Whatever the outcome, the peer still accepts us as leader.
+	cm.lastAck[peerId] = time.Now()
+	if round > cm.ackedRound[peerId] {
+		cm.ackedRound[peerId] = round
+	}
+
+	if reply.Success {
+		if match := args.PrevLogIndex + len(args.Entries); match > cm.matchIndex[peerId] {
+			cm.matchIndex[peerId] = match
+		}
+		if cm.nextIndex[peerId] <= cm.matchIndex[peerId] {
+			cm.nextIndex[peerId] = cm.matchIndex[peerId] + 1
+		}
+		if current && len(args.Entries) > 0 && pr.probing {
+			pr.probing = false
+		}
+		//cm.dlog("AppendEntries reply from %s success: nextIndex := %v, matchIndex := %v; commitIndex := %d", peerId, cm.nextIndex, cm.matchIndex, cm.commitIndex)
+		// Notify followers of the new commit index, and keep the pipeline
+		// full, by sending more AEs.
+		if cm.leaderAdvanceCommitIndex() && cm.state == Leader || cm.nextIndex[peerId] <= cm.lastIndex() {
+			cm.triggerAE()
+		}
+		return
+	}
+
+	if !current {
+		// Rejections of AEs sent before nextIndex was last reset are stale.
+		return
+	}
+	nextIndex := reply.ConflictIndex
+	if reply.ConflictTerm >= 0 {
+		for i := cm.lastIndex(); i > cm.snapshotIndex; i-- {
+			if cm.termAt(i) == reply.ConflictTerm {
+				nextIndex = i + 1
+				break
+			}
+		}
+	}
+	cm.resetProgress(peerId, nextIndex)
+	cm.dlog("AppendEntries reply from %s !success: nextIndex := %d", peerId, cm.nextIndex[peerId])
+	cm.triggerAE()
+}
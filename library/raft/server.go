@@ -0,0 +1,597 @@
+// Server container for a Raft Consensus Module. Exposes Raft to the network
+// and enables RPCs between Raft peers.
+//
+// Eli Bendersky [https://eli.thegreenplace.net]
+// This code is in the public domain.
+package raft
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"math/rand"
+	"net"
+	"os"
+	"sync"
+	"time"
+
+	"github.com/adarshsrinivasan/DS_S24/library/peerauth"
+	libProto "github.com/adarshsrinivasan/DS_S24/library/proto"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/backoff"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/keepalive"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// raftRPCTimeout bounds RequestVote and AppendEntries calls. It's well
+	// below the election timeout so a dead peer can't hold up a leader's
+	// heartbeats for a whole election round.
+	raftRPCTimeout = 100 * time.Millisecond
+
+	// raftSnapshotRPCTimeout bounds InstallSnapshot calls, which carry the
+	// whole product table.
+	raftSnapshotRPCTimeout = 10 * time.Second
+
+	// raftMaxMessageSize is the largest message peers exchange, which is
+	// usually an InstallSnapshot request.
+	raftMaxMessageSize = 256 << 20
+)
+
+var (
+	// raftKeepaliveParams makes idle peer connections send pings, so a peer
+	// that went away silently is noticed and redialed before the next RPC.
+	raftKeepaliveParams = keepalive.ClientParameters{
+		Time:                5 * time.Second,
+		Timeout:             2 * time.Second,
+		PermitWithoutStream: true,
+	}
+
+	// raftKeepalivePolicy lets peers ping as often as raftKeepaliveParams
+	// asks them to.
+	raftKeepalivePolicy = keepalive.EnforcementPolicy{
+		MinTime:             time.Second,
+		PermitWithoutStream: true,
+	}
+
+	// raftConnectParams redials broken peer connections quickly, backing off
+	// to a few seconds while a peer stays down.
+	raftConnectParams = grpc.ConnectParams{
+		Backoff: backoff.Config{
+			BaseDelay:  100 * time.Millisecond,
+			Multiplier: 1.6,
+			Jitter:     0.2,
+			MaxDelay:   3 * time.Second,
+		},
+		MinConnectTimeout: time.Second,
+	}
+)
+
+// Server wraps a raft.ConsensusModule along with a grpc.Server that exposes
+// its methods as RaftService. It also manages the peers of the Raft server.
+// The main goal of this type is to simplify the code of raft.Server for
+// presentation purposes. raft.ConsensusModule has a *Server to do its peer
+// communication and doesn't have to worry about the specifics of running an
+// RPC server.
+type Server struct {
+	mu sync.Mutex
+
+	serverId   string
+	listenAddr string
+	config     Configuration
+
+	cm       *ConsensusModule
+	storage  Storage
+	rpcProxy *RPCProxy
+
+	grpcServer *grpc.Server
+	listener   net.Listener
+
+	commitChan chan<- CommitEntry
+	peerConns  map[string]*peerConn
+
+	// propose proposes the commands followers forward; see HandlePropose.
+	propose ProposeHandler
+
+	// keyring signs the RPCs to peers and checks those from them; see
+	// Authenticate.
+	keyring *peerauth.Keyring
+
+	// disconnected holds the peers DisconnectPeer was called for. Calls to
+	// them fail until ConnectToPeer is called for them again.
+	disconnected map[string]bool
+
+	ready <-chan interface{}
+	quit  chan interface{}
+	wg    sync.WaitGroup
+}
+
+// peerConn is the connection to a peer. gRPC redials it in the background
+// whenever it breaks, so it's only replaced when the peer's address changes.
+type peerConn struct {
+	addr   string
+	conn   *grpc.ClientConn
+	client libProto.RaftServiceClient
+}
+
+// NewServer creates a server for the CM with the given ID, which serves its
+// peers at listenAddr. See NewConsensusModule for the other arguments.
+func NewServer(serverId string, listenAddr string, config Configuration, storage Storage, ready <-chan interface{}, commitChan chan<- CommitEntry) *Server {
+	s := new(Server)
+	s.serverId = serverId
+	s.listenAddr = listenAddr
+	s.config = config
+	s.peerConns = make(map[string]*peerConn)
+	s.disconnected = make(map[string]bool)
+	s.storage = storage
+	s.ready = ready
+	s.commitChan = commitChan
+	s.quit = make(chan interface{})
+	return s
+}
+
+// HandlePropose sets the handler of the commands followers forward to this
+// server. It must be called before Serve; without a handler, forwarded
+// commands are refused.
+func (s *Server) HandlePropose(propose ProposeHandler) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.propose = propose
+}
+
+// Authenticate makes the server sign its RPCs to peers with keyring and
+// refuse those from peers that aren't signed with one of its keys. It must be
+// called before Serve and ConnectToPeer; without a keyring, anyone who can
+// reach the listen address can act as a peer.
+func (s *Server) Authenticate(keyring *peerauth.Keyring) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.keyring = keyring
+}
+
+func (s *Server) Serve() {
+	s.mu.Lock()
+	s.cm = NewConsensusModule(s.serverId, s.config, s, s.storage, s.ready, s.commitChan)
+
+	// Create a new gRPC server and register a RPCProxy that forwards all
+	// methods to n.cm
+	s.grpcServer = grpc.NewServer(
+		grpc.KeepaliveEnforcementPolicy(raftKeepalivePolicy),
+		grpc.MaxRecvMsgSize(raftMaxMessageSize),
+		grpc.UnaryInterceptor(s.keyring.UnaryServerInterceptor()),
+	)
+	s.rpcProxy = &RPCProxy{cm: s.cm, propose: s.propose}
+	libProto.RegisterRaftServiceServer(s.grpcServer, s.rpcProxy)
+
+	var err error
+	s.listener, err = net.Listen("tcp", s.listenAddr)
+	if err != nil {
+		log.Fatal(err)
+	}
+	log.Printf("[%v] listening at %s", s.serverId, s.listener.Addr())
+	s.mu.Unlock()
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := s.grpcServer.Serve(s.listener); err != nil {
+			select {
+			case <-s.quit:
+			default:
+				log.Fatal("serve error:", err)
+			}
+		}
+	}()
+}
+
+// DisconnectAll disconnects this server from all of its peers, as
+// DisconnectPeer does.
+func (s *Server) DisconnectAll() {
+	var config Configuration
+	if cm := s.CM(); cm != nil {
+		config, _ = cm.Configuration()
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for _, member := range config.Members {
+		if member.ID != s.serverId {
+			s.disconnected[member.ID] = true
+		}
+	}
+	for id, peer := range s.peerConns {
+		s.disconnected[id] = true
+		peer.conn.Close()
+		delete(s.peerConns, id)
+	}
+}
+
+// Shutdown closes the server and its connections to peers, and waits for it
+// to shut down properly.
+func (s *Server) Shutdown() {
+	s.cm.Stop()
+	close(s.quit)
+	s.grpcServer.Stop()
+	s.wg.Wait()
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	for id, peer := range s.peerConns {
+		peer.conn.Close()
+		delete(s.peerConns, id)
+	}
+}
+
+// CM returns the Consensus Module served by this server. It's nil until Serve
+// is called.
+func (s *Server) CM() *ConsensusModule {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.cm
+}
+
+func (s *Server) GetListenAddr() net.Addr {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.listener.Addr()
+}
+
+// ConnectToPeer sets up the connection to the peer at addr, undoing an
+// earlier DisconnectPeer. The connection is established in the background
+// and re-established whenever it breaks, so this only fails for an invalid
+// address.
+func (s *Server) ConnectToPeer(peerId string, addr string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.disconnected, peerId)
+	return s.connectToPeer(peerId, addr)
+}
+
+// connectToPeer sets up the connection to the peer at addr, unless it's
+// already there.
+// Expects s.mu to be locked.
+func (s *Server) connectToPeer(peerId string, addr string) error {
+	if peer, found := s.peerConns[peerId]; found {
+		if peer.addr == addr {
+			return nil
+		}
+		// The peer was re-added under a new address.
+		peer.conn.Close()
+		delete(s.peerConns, peerId)
+	}
+	conn, err := grpc.Dial(addr,
+		grpc.WithTransportCredentials(insecure.NewCredentials()),
+		grpc.WithKeepaliveParams(raftKeepaliveParams),
+		grpc.WithConnectParams(raftConnectParams),
+		grpc.WithDefaultCallOptions(grpc.MaxCallSendMsgSize(raftMaxMessageSize), grpc.MaxCallRecvMsgSize(raftMaxMessageSize)),
+		grpc.WithUnaryInterceptor(s.keyring.UnaryClientInterceptor()),
+	)
+	if err != nil {
+		return err
+	}
+	s.peerConns[peerId] = &peerConn{addr: addr, conn: conn, client: libProto.NewRaftServiceClient(conn)}
+	return nil
+}
+
+// DisconnectPeer disconnects this server from the peer identified by peerId:
+// calls to the peer fail until ConnectToPeer is called for it again. Calls
+// from the peer still get through, so cutting the link both ways takes a
+// DisconnectPeer on either side.
+func (s *Server) DisconnectPeer(peerId string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.disconnected[peerId] = true
+	if peer, found := s.peerConns[peerId]; found {
+		delete(s.peerConns, peerId)
+		return peer.conn.Close()
+	}
+	return nil
+}
+
+// peerClient returns the RaftService client for the peer identified by id,
+// connecting to it first if needed.
+func (s *Server) peerClient(id string) (libProto.RaftServiceClient, error) {
+	s.mu.Lock()
+	cm := s.cm
+	s.mu.Unlock()
+
+	// Peers are looked up in the configuration on every call, so members
+	// added after startup are connected to on first use.
+	addr, found := cm.memberAddr(id)
+	if !found {
+		return nil, fmt.Errorf("call client %s: not a member", id)
+	}
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.disconnected[id] {
+		return nil, status.Errorf(codes.Unavailable, "call client %s: disconnected", id)
+	}
+	if err := s.connectToPeer(id, addr); err != nil {
+		return nil, fmt.Errorf("call client %s: %v", id, err)
+	}
+	return s.peerConns[id].client, nil
+}
+
+// Call invokes serviceMethod on the peer identified by id, with the same
+// arguments and reply types as the ConsensusModule's RPC methods. Each call
+// is bounded by a deadline; a call that fails leaves the connection to be
+// redialed in the background.
+func (s *Server) Call(id string, serviceMethod string, args interface{}, reply interface{}) error {
+	client, err := s.peerClient(id)
+	if err != nil {
+		return err
+	}
+
+	switch serviceMethod {
+	case "ConsensusModule.RequestVote":
+		ctx, cancel := context.WithTimeout(context.Background(), raftRPCTimeout)
+		defer cancel()
+		args := args.(RequestVoteArgs)
+		response, err := client.RequestVote(ctx, &libProto.RequestVoteRequest{
+			Term:         int64(args.Term),
+			CandidateID:  args.CandidateId,
+			LastLogIndex: int64(args.LastLogIndex),
+			LastLogTerm:  int64(args.LastLogTerm),
+			PreVote:      args.PreVote,
+		})
+		if err != nil {
+			return err
+		}
+		*reply.(*RequestVoteReply) = RequestVoteReply{
+			Term:        int(response.GetTerm()),
+			VoteGranted: response.GetVoteGranted(),
+		}
+	case "ConsensusModule.AppendEntries":
+		ctx, cancel := context.WithTimeout(context.Background(), raftRPCTimeout)
+		defer cancel()
+		args := args.(AppendEntriesArgs)
+		response, err := client.AppendEntries(ctx, &libProto.AppendEntriesRequest{
+			Term:         int64(args.Term),
+			LeaderID:     args.LeaderId,
+			PrevLogIndex: int64(args.PrevLogIndex),
+			PrevLogTerm:  int64(args.PrevLogTerm),
+			Entries:      convertLogEntriesToProtoEntries(args.Entries),
+			LeaderCommit: int64(args.LeaderCommit),
+		})
+		if err != nil {
+			return err
+		}
+		*reply.(*AppendEntriesReply) = AppendEntriesReply{
+			Term:          int(response.GetTerm()),
+			Success:       response.GetSuccess(),
+			ConflictIndex: int(response.GetConflictIndex()),
+			ConflictTerm:  int(response.GetConflictTerm()),
+		}
+	case "ConsensusModule.InstallSnapshot":
+		ctx, cancel := context.WithTimeout(context.Background(), raftSnapshotRPCTimeout)
+		defer cancel()
+		args := args.(InstallSnapshotArgs)
+		config, err := encodeConfiguration(args.Config)
+		if err != nil {
+			return err
+		}
+		response, err := client.InstallSnapshot(ctx, &libProto.InstallSnapshotRequest{
+			Term:              int64(args.Term),
+			LeaderID:          args.LeaderId,
+			LastIncludedIndex: int64(args.LastIncludedIndex),
+			LastIncludedTerm:  int64(args.LastIncludedTerm),
+			Config:            config,
+			Data:              args.Data,
+		})
+		if err != nil {
+			return err
+		}
+		*reply.(*InstallSnapshotReply) = InstallSnapshotReply{Term: int(response.GetTerm())}
+	case "ConsensusModule.ReadIndex":
+		// The leader may take up to readIndexRPCTimeout to confirm its
+		// leadership before answering.
+		ctx, cancel := context.WithTimeout(context.Background(), readIndexRPCTimeout+raftRPCTimeout)
+		defer cancel()
+		args := args.(ReadIndexArgs)
+		response, err := client.ReadIndex(ctx, &libProto.ReadIndexRequest{ReaderID: args.ReaderId})
+		if err != nil {
+			return err
+		}
+		*reply.(*ReadIndexReply) = ReadIndexReply{
+			Success:  response.GetSuccess(),
+			Index:    int(response.GetIndex()),
+			LeaderId: response.GetLeaderID(),
+		}
+	case "ConsensusModule.TimeoutNow":
+		ctx, cancel := context.WithTimeout(context.Background(), raftRPCTimeout)
+		defer cancel()
+		args := args.(TimeoutNowArgs)
+		response, err := client.TimeoutNow(ctx, &libProto.TimeoutNowRequest{
+			Term:     int64(args.Term),
+			LeaderID: args.LeaderId,
+		})
+		if err != nil {
+			return err
+		}
+		*reply.(*TimeoutNowReply) = TimeoutNowReply{Term: int(response.GetTerm())}
+	default:
+		return fmt.Errorf("call client %s: unknown method %s", id, serviceMethod)
+	}
+	return nil
+}
+
+// RPCProxy serves RaftService by converting its messages to and from the
+// ConsensusModule's RPC types.
+// It's useful for simulating possible unreliable connections by delaying some
+// messages significantly and dropping others when RAFT_UNRELIABLE_RPC is set.
+type RPCProxy struct {
+	libProto.UnimplementedRaftServiceServer
+	cm      *ConsensusModule
+	propose ProposeHandler
+}
+
+// simulateUnreliableRPC drops or delays some RPCs when RAFT_UNRELIABLE_RPC is
+// set. It returns an error for RPCs that should be dropped.
+func (rpp *RPCProxy) simulateUnreliableRPC(method string) error {
+	if len(os.Getenv("RAFT_UNRELIABLE_RPC")) == 0 {
+		return nil
+	}
+	dice := rand.Intn(10)
+	if dice == 9 {
+		rpp.cm.dlog("drop %s", method)
+		return status.Errorf(codes.Unavailable, "RPC failed")
+	} else if dice == 8 {
+		rpp.cm.dlog("delay %s", method)
+		time.Sleep(75 * time.Millisecond)
+	}
+	return nil
+}
+
+func (rpp *RPCProxy) RequestVote(ctx context.Context, request *libProto.RequestVoteRequest) (*libProto.RequestVoteResponse, error) {
+	if err := rpp.simulateUnreliableRPC("RequestVote"); err != nil {
+		return nil, err
+	}
+	args := RequestVoteArgs{
+		Term:         int(request.GetTerm()),
+		CandidateId:  request.GetCandidateID(),
+		LastLogIndex: int(request.GetLastLogIndex()),
+		LastLogTerm:  int(request.GetLastLogTerm()),
+		PreVote:      request.GetPreVote(),
+	}
+	var reply RequestVoteReply
+	if err := rpp.cm.RequestVote(args, &reply); err != nil {
+		return nil, err
+	}
+	return &libProto.RequestVoteResponse{
+		Term:        int64(reply.Term),
+		VoteGranted: reply.VoteGranted,
+	}, nil
+}
+
+func (rpp *RPCProxy) AppendEntries(ctx context.Context, request *libProto.AppendEntriesRequest) (*libProto.AppendEntriesResponse, error) {
+	if err := rpp.simulateUnreliableRPC("AppendEntries"); err != nil {
+		return nil, err
+	}
+	entries, err := convertProtoEntriesToLogEntries(request.GetEntries())
+	if err != nil {
+		log.Printf("AppendEntries: refusing entries from %s. %v", request.GetLeaderID(), err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	args := AppendEntriesArgs{
+		Term:         int(request.GetTerm()),
+		LeaderId:     request.GetLeaderID(),
+		PrevLogIndex: int(request.GetPrevLogIndex()),
+		PrevLogTerm:  int(request.GetPrevLogTerm()),
+		Entries:      entries,
+		LeaderCommit: int(request.GetLeaderCommit()),
+	}
+	var reply AppendEntriesReply
+	if err := rpp.cm.AppendEntries(args, &reply); err != nil {
+		return nil, err
+	}
+	return &libProto.AppendEntriesResponse{
+		Term:          int64(reply.Term),
+		Success:       reply.Success,
+		ConflictIndex: int64(reply.ConflictIndex),
+		ConflictTerm:  int64(reply.ConflictTerm),
+	}, nil
+}
+
+func (rpp *RPCProxy) InstallSnapshot(ctx context.Context, request *libProto.InstallSnapshotRequest) (*libProto.InstallSnapshotResponse, error) {
+	if err := rpp.simulateUnreliableRPC("InstallSnapshot"); err != nil {
+		return nil, err
+	}
+	config, err := decodeConfiguration(request.GetConfig())
+	if err != nil {
+		log.Printf("InstallSnapshot: refusing snapshot from %s. %v", request.GetLeaderID(), err)
+		return nil, status.Error(codes.InvalidArgument, err.Error())
+	}
+	args := InstallSnapshotArgs{
+		Term:              int(request.GetTerm()),
+		LeaderId:          request.GetLeaderID(),
+		LastIncludedIndex: int(request.GetLastIncludedIndex()),
+		LastIncludedTerm:  int(request.GetLastIncludedTerm()),
+		Config:            config,
+		Data:              request.GetData(),
+	}
+	var reply InstallSnapshotReply
+	if err := rpp.cm.InstallSnapshot(args, &reply); err != nil {
+		return nil, err
+	}
+	return &libProto.InstallSnapshotResponse{Term: int64(reply.Term)}, nil
+}
+
+func (rpp *RPCProxy) ReadIndex(ctx context.Context, request *libProto.ReadIndexRequest) (*libProto.ReadIndexResponse, error) {
+	if err := rpp.simulateUnreliableRPC("ReadIndex"); err != nil {
+		return nil, err
+	}
+	args := ReadIndexArgs{ReaderId: request.GetReaderID()}
+	var reply ReadIndexReply
+	if err := rpp.cm.ReadIndexRPC(args, &reply); err != nil {
+		return nil, err
+	}
+	return &libProto.ReadIndexResponse{
+		Success:  reply.Success,
+		Index:    int64(reply.Index),
+		LeaderID: reply.LeaderId,
+	}, nil
+}
+
+func (rpp *RPCProxy) TimeoutNow(ctx context.Context, request *libProto.TimeoutNowRequest) (*libProto.TimeoutNowResponse, error) {
+	if err := rpp.simulateUnreliableRPC("TimeoutNow"); err != nil {
+		return nil, err
+	}
+	args := TimeoutNowArgs{
+		Term:     int(request.GetTerm()),
+		LeaderId: request.GetLeaderID(),
+	}
+	var reply TimeoutNowReply
+	if err := rpp.cm.TimeoutNow(args, &reply); err != nil {
+		return nil, err
+	}
+	return &libProto.TimeoutNowResponse{Term: int64(reply.Term)}, nil
+}
+
+// Propose proposes a command forwarded by a follower and answers once it's
+// applied. It doesn't go through simulateUnreliableRPC: a dropped answer
+// would leave the follower unsure whether the command was applied.
+func (rpp *RPCProxy) Propose(ctx context.Context, request *libProto.ProposeRequest) (*libProto.ProposeResponse, error) {
+	if rpp.propose == nil {
+		return nil, status.Errorf(codes.Unimplemented, "%s doesn't take forwarded commands", rpp.cm.id)
+	}
+	result, ok := rpp.propose(ctx, OpsType(request.GetCommand()), request.GetPayload())
+	if !ok {
+		return &libProto.ProposeResponse{NotLeader: true}, nil
+	}
+	return convertResultToProposeResponse(result)
+}
+
+func convertLogEntriesToProtoEntries(entries []LogEntry) []*libProto.RaftLogEntry {
+	protoEntries := make([]*libProto.RaftLogEntry, 0, len(entries))
+	for _, entry := range entries {
+		protoEntries = append(protoEntries, &libProto.RaftLogEntry{
+			ID:      entry.ID,
+			Command: int32(entry.Command),
+			Payload: entry.Payload,
+			Term:    int64(entry.Term),
+		})
+	}
+	return protoEntries
+}
+
+// convertProtoEntriesToLogEntries converts the entries of an AppendEntries
+// request. It fails if a configuration entry doesn't decode, so that one
+// never makes it into the log.
+func convertProtoEntriesToLogEntries(protoEntries []*libProto.RaftLogEntry) ([]LogEntry, error) {
+	entries := make([]LogEntry, 0, len(protoEntries))
+	for _, entry := range protoEntries {
+		if OpsType(entry.GetCommand()) == ConfigChange {
+			if _, err := decodeConfiguration(entry.GetPayload()); err != nil {
+				return nil, fmt.Errorf("entry %s: %v", entry.GetID(), err)
+			}
+		}
+		entries = append(entries, LogEntry{
+			ID:      entry.GetID(),
+			Command: OpsType(entry.GetCommand()),
+			Payload: entry.GetPayload(),
+			Term:    int(entry.GetTerm()),
+		})
+	}
+	return entries, nil
+}
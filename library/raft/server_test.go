@@ -0,0 +1,44 @@
+package raft
+
+import (
+	"context"
+	"testing"
+
+	libProto "github.com/adarshsrinivasan/DS_S24/library/proto"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestMalformedConfigurationIsInvalidArgument(t *testing.T) {
+	rpp := &RPCProxy{}
+	garbage := []byte("not a configuration")
+
+	_, err := rpp.InstallSnapshot(context.Background(), &libProto.InstallSnapshotRequest{Term: 1, LeaderID: "node1", Config: garbage})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("InstallSnapshot: got %v; want %v", err, codes.InvalidArgument)
+	}
+
+	_, err = rpp.AppendEntries(context.Background(), &libProto.AppendEntriesRequest{
+		Term:     1,
+		LeaderID: "node1",
+		Entries:  []*libProto.RaftLogEntry{{ID: "config", Command: int32(ConfigChange), Payload: garbage, Term: 1}},
+	})
+	if status.Code(err) != codes.InvalidArgument {
+		t.Fatalf("AppendEntries: got %v; want %v", err, codes.InvalidArgument)
+	}
+}
+
+func TestConfigurationRoundTrip(t *testing.T) {
+	config := Configuration{Members: []Member{{ID: "node0", RaftAddr: "127.0.0.1:1"}, {ID: "node1", RaftAddr: "127.0.0.1:2", Learner: true}}}
+	data, err := encodeConfiguration(config)
+	if err != nil {
+		t.Fatalf("encodeConfiguration: %v", err)
+	}
+	decoded, err := decodeConfiguration(data)
+	if err != nil {
+		t.Fatalf("decodeConfiguration: %v", err)
+	}
+	if !decoded.IsVoter("node0") || decoded.IsVoter("node1") || !decoded.HasMember("node1") {
+		t.Fatalf("got %+v; want %+v", decoded, config)
+	}
+}
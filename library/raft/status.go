@@ -0,0 +1,73 @@
+// Introspection of the Raft Consensus Module, for operators and tests.
+package raft
+
+import "time"
+
+// Status is a point-in-time view of a CM's state.
+type Status struct {
+	ID            string           `json:"id"`
+	State         string           `json:"state"`
+	Term          int              `json:"term"`
+	LeaderID      string           `json:"leaderId"`
+	CommitIndex   int              `json:"commitIndex"`
+	LastApplied   int              `json:"lastApplied"`
+	SnapshotIndex int              `json:"snapshotIndex"`
+	LastLogIndex  int              `json:"lastLogIndex"`
+	LogSize       int              `json:"logSize"`
+	Config        Configuration    `json:"config"`
+	Followers     []FollowerStatus `json:"followers,omitempty"`
+}
+
+// FollowerStatus is the leader's view of replication to a single peer.
+type FollowerStatus struct {
+	ID         string `json:"id"`
+	NextIndex  int    `json:"nextIndex"`
+	MatchIndex int    `json:"matchIndex"`
+	Probing    bool   `json:"probing"`
+	Inflight   int    `json:"inflight"`
+
+	// SinceLastAck is how long ago the peer last replied in the current
+	// term, or -1 if it hasn't.
+	SinceLastAck time.Duration `json:"sinceLastAckNs"`
+}
+
+// Status reports the state of this CM. Followers are only reported by the
+// leader.
+func (cm *ConsensusModule) Status() Status {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	status := Status{
+		ID:            cm.id,
+		State:         cm.state.String(),
+		Term:          cm.currentTerm,
+		LeaderID:      cm.leaderID,
+		CommitIndex:   cm.commitIndex,
+		LastApplied:   cm.lastApplied,
+		SnapshotIndex: cm.snapshotIndex,
+		LastLogIndex:  cm.lastIndex(),
+		LogSize:       len(cm.log),
+		Config:        cm.config.clone(),
+	}
+	if cm.state != Leader {
+		return status
+	}
+	for _, peerId := range cm.peerIds {
+		follower := FollowerStatus{
+			ID:           peerId,
+			NextIndex:    cm.nextIndex[peerId],
+			MatchIndex:   cm.matchIndex[peerId],
+			SinceLastAck: -1,
+		}
+		// Don't go through progressOf: a status read mustn't change how the
+		// leader replicates to the peer.
+		if pr, found := cm.progress[peerId]; found {
+			follower.Probing = pr.probing
+			follower.Inflight = pr.inflight
+		}
+		if lastAck, found := cm.lastAck[peerId]; found {
+			follower.SinceLastAck = time.Since(lastAck)
+		}
+		status.Followers = append(status.Followers, follower)
+	}
+	return status
+}
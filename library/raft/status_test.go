@@ -0,0 +1,24 @@
+package raft
+
+import "testing"
+
+func TestStatusLeavesFollowerProgressAlone(t *testing.T) {
+	cm := newTestLeader(t, []LogEntry{{ID: "a", Command: NoOp, Term: 2}}, 0)
+	cm.mu.Lock()
+	cm.peerIds = []string{"node1"}
+	cm.mu.Unlock()
+
+	status := cm.Status()
+	if len(status.Followers) != 1 {
+		t.Fatalf("got followers %+v; want node1", status.Followers)
+	}
+	follower := status.Followers[0]
+	if follower.ID != "node1" || follower.Probing || follower.Inflight != 0 || follower.SinceLastAck != -1 {
+		t.Fatalf("got %+v; want the zero status of node1", follower)
+	}
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if _, found := cm.progress["node1"]; found {
+		t.Fatalf("Status created the progress of node1")
+	}
+}
@@ -0,0 +1,195 @@
+// Eli Bendersky [https://eli.thegreenplace.net]
+// This code is in the public domain.
+package raft
+
+import (
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Storage is an interface implemented by stable storage providers.
+type Storage interface {
+	Set(key string, value []byte)
+
+	Get(key string) ([]byte, bool)
+
+	// HasData returns true iff any Sets were made on this Storage.
+	HasData() bool
+}
+
+// MapStorage is a simple in-memory implementation of Storage for testing.
+type MapStorage struct {
+	mu sync.Mutex
+	m  map[string][]byte
+}
+
+func NewMapStorage() *MapStorage {
+	m := make(map[string][]byte)
+	return &MapStorage{
+		m: m,
+	}
+}
+
+func (ms *MapStorage) Get(key string) ([]byte, bool) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	v, found := ms.m[key]
+	return v, found
+}
+
+func (ms *MapStorage) Set(key string, value []byte) {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	ms.m[key] = value
+}
+
+func (ms *MapStorage) HasData() bool {
+	ms.mu.Lock()
+	defer ms.mu.Unlock()
+	return len(ms.m) > 0
+}
+
+const (
+	fileStorageTempSuffix = ".tmp"
+	fileStorageMagic      = "RAFTSTv1"
+)
+
+// FileStorage is a file-backed implementation of Storage. Each key lives in
+// its own file, so large values that rarely change (the snapshot) aren't
+// rewritten whenever the term or log is. A Set writes the value to a
+// temporary file, fsyncs it and atomically renames it over the previous
+// file, so a crash leaves either the old or the new value on disk, never a
+// torn mix of both.
+//
+// On-disk layout of a key file: magic (8 bytes) | crc32 of value (4 bytes) | value.
+type FileStorage struct {
+	mu  sync.Mutex
+	dir string
+	m   map[string][]byte
+}
+
+// NewFileStorage opens (or creates) a FileStorage rooted at dir and loads any
+// state left behind by a previous run, so HasData reports true after a
+// restart and the CM restores itself from it.
+func NewFileStorage(dir string) (*FileStorage, error) {
+	if err := os.MkdirAll(dir, 0o755); err != nil {
+		return nil, fmt.Errorf("exception while creating raft storage dir %s. %v", dir, err)
+	}
+	fs := &FileStorage{
+		dir: dir,
+		m:   make(map[string][]byte),
+	}
+	if err := fs.load(); err != nil {
+		return nil, err
+	}
+	return fs, nil
+}
+
+func (fs *FileStorage) Get(key string) ([]byte, bool) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	v, found := fs.m[key]
+	return v, found
+}
+
+// Set stores value under key and durably writes it before returning. Raft
+// must not answer an RPC with state it may forget, so a failed write is
+// fatal.
+func (fs *FileStorage) Set(key string, value []byte) {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	if err := fs.writeKey(key, value); err != nil {
+		log.Fatalf("FileStorage: exception while persisting key %s to %s. %v", key, fs.dir, err)
+	}
+	fs.m[key] = value
+}
+
+func (fs *FileStorage) HasData() bool {
+	fs.mu.Lock()
+	defer fs.mu.Unlock()
+	return len(fs.m) > 0
+}
+
+// load reads every key file in fs.dir into fs.m.
+func (fs *FileStorage) load() error {
+	dirEntries, err := os.ReadDir(fs.dir)
+	if err != nil {
+		return fmt.Errorf("exception while listing raft storage dir %s. %v", fs.dir, err)
+	}
+	for _, dirEntry := range dirEntries {
+		name := dirEntry.Name()
+		if dirEntry.IsDir() {
+			continue
+		}
+		path := filepath.Join(fs.dir, name)
+		if strings.HasSuffix(name, fileStorageTempSuffix) {
+			// We crashed before the rename; the key file (if any) still holds
+			// the last complete write.
+			if err := os.Remove(path); err != nil {
+				return fmt.Errorf("exception while removing stale raft temp file %s. %v", path, err)
+			}
+			continue
+		}
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return fmt.Errorf("exception while reading raft storage file %s. %v", path, err)
+		}
+		headerLen := len(fileStorageMagic) + 4
+		if len(data) < headerLen || string(data[:len(fileStorageMagic)]) != fileStorageMagic {
+			return fmt.Errorf("raft storage file %s has an invalid header", path)
+		}
+		value := data[headerLen:]
+		if crc32.ChecksumIEEE(value) != binary.BigEndian.Uint32(data[len(fileStorageMagic):headerLen]) {
+			return fmt.Errorf("raft storage file %s failed checksum verification", path)
+		}
+		fs.m[name] = value
+	}
+	return nil
+}
+
+// writeKey writes value to a temp file, fsyncs it, renames it over the key's
+// file and fsyncs the directory so the rename itself survives a crash.
+// Expects fs.mu to be locked.
+func (fs *FileStorage) writeKey(key string, value []byte) error {
+	header := make([]byte, len(fileStorageMagic)+4)
+	copy(header, fileStorageMagic)
+	binary.BigEndian.PutUint32(header[len(fileStorageMagic):], crc32.ChecksumIEEE(value))
+
+	path := filepath.Join(fs.dir, key)
+	tmpPath := path + fileStorageTempSuffix
+	f, err := os.OpenFile(tmpPath, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0o644)
+	if err != nil {
+		return err
+	}
+	if _, err := f.Write(header); err != nil {
+		f.Close()
+		return err
+	}
+	if _, err := f.Write(value); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Sync(); err != nil {
+		f.Close()
+		return err
+	}
+	if err := f.Close(); err != nil {
+		return err
+	}
+	if err := os.Rename(tmpPath, path); err != nil {
+		return err
+	}
+	d, err := os.Open(fs.dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
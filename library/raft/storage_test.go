@@ -0,0 +1,128 @@
+package raft
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+func newTestFileStorage(t *testing.T, dir string) *FileStorage {
+	t.Helper()
+	fs, err := NewFileStorage(dir)
+	if err != nil {
+		t.Fatalf("NewFileStorage: %v", err)
+	}
+	return fs
+}
+
+func TestFileStorageSurvivesReopen(t *testing.T) {
+	dir := t.TempDir()
+	fs := newTestFileStorage(t, dir)
+	if fs.HasData() {
+		t.Fatalf("HasData is true for an empty dir")
+	}
+	fs.Set("currentTerm", []byte("1"))
+	fs.Set("log", []byte("entries"))
+
+	reopened := newTestFileStorage(t, dir)
+	if !reopened.HasData() {
+		t.Fatalf("HasData is false after reopening")
+	}
+	for key, want := range map[string]string{"currentTerm": "1", "log": "entries"} {
+		if got, found := reopened.Get(key); !found || string(got) != want {
+			t.Fatalf("Get(%s) = %q, %v; want %q", key, got, found, want)
+		}
+	}
+}
+
+func TestFileStorageSetReplacesAtomically(t *testing.T) {
+	dir := t.TempDir()
+	fs := newTestFileStorage(t, dir)
+	fs.Set("currentTerm", []byte("1"))
+	fs.Set("currentTerm", []byte("22"))
+
+	dirEntries, err := os.ReadDir(dir)
+	if err != nil {
+		t.Fatalf("ReadDir: %v", err)
+	}
+	if len(dirEntries) != 1 || dirEntries[0].Name() != "currentTerm" {
+		t.Fatalf("got dir entries %v; want only currentTerm", dirEntries)
+	}
+	data, err := os.ReadFile(filepath.Join(dir, "currentTerm"))
+	if err != nil {
+		t.Fatalf("ReadFile: %v", err)
+	}
+	if !bytes.HasPrefix(data, []byte(fileStorageMagic)) || !bytes.HasSuffix(data, []byte("22")) {
+		t.Fatalf("key file holds %q; want the magic and the new value", data)
+	}
+	if got, _ := newTestFileStorage(t, dir).Get("currentTerm"); string(got) != "22" {
+		t.Fatalf("Get(currentTerm) = %q after reopening; want %q", got, "22")
+	}
+}
+
+func TestFileStorageRemovesStaleTempFiles(t *testing.T) {
+	dir := t.TempDir()
+	newTestFileStorage(t, dir).Set("currentTerm", []byte("1"))
+
+	// A crash between writing the temp file and renaming it.
+	tmpPath := filepath.Join(dir, "currentTerm"+fileStorageTempSuffix)
+	if err := os.WriteFile(tmpPath, []byte("torn"), 0o644); err != nil {
+		t.Fatalf("WriteFile: %v", err)
+	}
+
+	fs := newTestFileStorage(t, dir)
+	if got, _ := fs.Get("currentTerm"); string(got) != "1" {
+		t.Fatalf("Get(currentTerm) = %q; want the last complete write %q", got, "1")
+	}
+	if _, found := fs.Get("currentTerm" + fileStorageTempSuffix); found {
+		t.Fatalf("the temp file was loaded as a key")
+	}
+	if _, err := os.Stat(tmpPath); !os.IsNotExist(err) {
+		t.Fatalf("the stale temp file is still on disk: %v", err)
+	}
+}
+
+func TestFileStorageRejectsCorruptFiles(t *testing.T) {
+	tests := []struct {
+		name    string
+		corrupt func(data []byte) []byte
+		wantErr string
+	}{
+		{
+			name:    "bad magic",
+			corrupt: func(data []byte) []byte { data[0] ^= 0xff; return data },
+			wantErr: "invalid header",
+		},
+		{
+			name:    "truncated header",
+			corrupt: func(data []byte) []byte { return data[:len(fileStorageMagic)+2] },
+			wantErr: "invalid header",
+		},
+		{
+			name:    "crc mismatch",
+			corrupt: func(data []byte) []byte { data[len(data)-1] ^= 0xff; return data },
+			wantErr: "checksum",
+		},
+	}
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			dir := t.TempDir()
+			newTestFileStorage(t, dir).Set("log", []byte("entries"))
+			path := filepath.Join(dir, "log")
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatalf("ReadFile: %v", err)
+			}
+			if err := os.WriteFile(path, test.corrupt(data), 0o644); err != nil {
+				t.Fatalf("WriteFile: %v", err)
+			}
+
+			_, err = NewFileStorage(dir)
+			if err == nil || !strings.Contains(err.Error(), test.wantErr) {
+				t.Fatalf("NewFileStorage: got %v; want an error containing %q", err, test.wantErr)
+			}
+		})
+	}
+}
@@ -0,0 +1,145 @@
+// Leadership transfer for the Raft Consensus Module.
+//
+// The leader stops accepting new commands, brings the target fully up to
+// date and then sends it TimeoutNow, which makes the target start an election
+// at once instead of waiting for its election timer (section 3.10 of the Raft
+// dissertation). The target's log is at least as up-to-date as anyone's, so
+// it wins unless it fails in the meantime.
+package raft
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"time"
+)
+
+// leadershipTransferTimeout bounds a whole transfer: catching up the target
+// and its election. The leader accepts commands again once it expires.
+const leadershipTransferTimeout = 2 * time.Second
+
+// ErrInvalidTransferTarget is returned when a transfer is asked for a node
+// that can't take over leadership.
+var ErrInvalidTransferTarget = errors.New("invalid transfer target")
+
+// TransferLeadership hands leadership over to the member with the given
+// targetID, or to the most up-to-date peer if targetID is empty, and returns
+// the ID of the new leader.
+func (cm *ConsensusModule) TransferLeadership(ctx context.Context, targetID string) (string, error) {
+	ctx, cancel := context.WithTimeout(ctx, leadershipTransferTimeout)
+	defer cancel()
+
+	cm.mu.Lock()
+	if cm.state != Leader {
+		leaderID := cm.leaderID
+		cm.mu.Unlock()
+		return "", fmt.Errorf("%s is %w. leader: %q", cm.id, ErrNotLeader, leaderID)
+	}
+	if cm.transferTarget != "" {
+		cm.mu.Unlock()
+		return "", fmt.Errorf("transfer to %s is already in progress", cm.transferTarget)
+	}
+	if targetID == "" {
+		for _, peerId := range cm.peerIds {
+			if !cm.config.IsVoter(peerId) {
+				continue
+			}
+			if targetID == "" || cm.matchIndex[peerId] > cm.matchIndex[targetID] {
+				targetID = peerId
+			}
+		}
+	}
+	if targetID == cm.id {
+		cm.mu.Unlock()
+		return cm.id, nil
+	}
+	if targetID == "" || !cm.config.IsVoter(targetID) {
+		cm.mu.Unlock()
+		return "", fmt.Errorf("%w: %q is not a voting member", ErrInvalidTransferTarget, targetID)
+	}
+	savedCurrentTerm := cm.currentTerm
+	cm.transferTarget = targetID
+	cm.mu.Unlock()
+	cm.dlog("transferring leadership to %s", targetID)
+
+	defer func() {
+		cm.mu.Lock()
+		cm.transferTarget = ""
+		cm.mu.Unlock()
+	}()
+
+	ticker := time.NewTicker(readIndexPollInterval)
+	defer ticker.Stop()
+
+	// Wait for the target to replicate our whole log. No new commands are
+	// accepted meanwhile, so the log stops growing.
+	for {
+		cm.mu.Lock()
+		if cm.state != Leader || cm.currentTerm != savedCurrentTerm {
+			cm.mu.Unlock()
+			break
+		}
+		caughtUp := cm.matchIndex[targetID] == cm.lastIndex()
+		cm.mu.Unlock()
+		if caughtUp {
+			cm.dlog("transfer target %s caught up; sending TimeoutNow", targetID)
+			args := TimeoutNowArgs{Term: savedCurrentTerm, LeaderId: cm.id}
+			var reply TimeoutNowReply
+			if err := cm.server.Call(targetID, "ConsensusModule.TimeoutNow", args, &reply); err != nil {
+				return "", fmt.Errorf("exception while sending TimeoutNow to %s. %v", targetID, err)
+			}
+			break
+		}
+		cm.triggerAE()
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return "", fmt.Errorf("exception while catching up %s. %v", targetID, ctx.Err())
+		}
+	}
+
+	// Wait for the election the target has started to replace us.
+	for {
+		cm.mu.Lock()
+		if cm.state != Leader && cm.leaderID != "" && cm.leaderID != cm.id {
+			leaderID := cm.leaderID
+			cm.mu.Unlock()
+			cm.dlog("leadership transferred to %s", leaderID)
+			return leaderID, nil
+		}
+		cm.mu.Unlock()
+		select {
+		case <-ticker.C:
+		case <-ctx.Done():
+			return "", fmt.Errorf("exception while waiting for %s to take over. %v", targetID, ctx.Err())
+		}
+	}
+}
+
+type TimeoutNowArgs struct {
+	Term     int
+	LeaderId string
+}
+
+type TimeoutNowReply struct {
+	Term int
+}
+
+// TimeoutNow RPC. Sent by the leader to the target of a leadership transfer;
+// the target starts an election right away, skipping pre-vote since the
+// leader has asked for it.
+func (cm *ConsensusModule) TimeoutNow(args TimeoutNowArgs, reply *TimeoutNowReply) error {
+	cm.mu.Lock()
+	defer cm.mu.Unlock()
+	if cm.state == Dead {
+		return nil
+	}
+	cm.dlog("TimeoutNow: %+v [currentTerm=%d]", args, cm.currentTerm)
+	reply.Term = cm.currentTerm
+	if args.Term != cm.currentTerm || cm.state != Follower || !cm.config.IsVoter(cm.id) {
+		cm.dlog("... ignoring TimeoutNow")
+		return nil
+	}
+	cm.startElection()
+	return nil
+}
@@ -0,0 +1,166 @@
+package replication
+
+import (
+	"context"
+	"fmt"
+	"strings"
+
+	"github.com/adarshsrinivasan/DS_S24/library/common"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// Registry maps the write requests of a service to the Op they're replicated
+// as and the function applying them. A service registers each of its writes
+// once; Apply is then its ApplyFunc, and UnaryServerInterceptor replicates the
+// writes its gRPC server receives.
+type Registry struct {
+	statusCode func(err error) int
+	commands   map[Op]*command
+	ops        map[protoreflect.FullName]Op
+}
+
+type command struct {
+	name         string
+	requestType  protoreflect.MessageType
+	responseType protoreflect.MessageType
+	apply        func(ctx context.Context, request proto.Message) (proto.Message, error)
+}
+
+// NewRegistry returns an empty Registry. statusCode maps the error a write
+// failed with to the status code of its response.
+func NewRegistry(statusCode func(err error) int) *Registry {
+	return &Registry{
+		statusCode: statusCode,
+		commands:   make(map[Op]*command),
+		ops:        make(map[protoreflect.FullName]Op),
+	}
+}
+
+// Register maps the write request Req to op and to apply, which executes it
+// on the local database once the engine has ordered it. It panics if op or
+// Req is registered already.
+func Register[Req, Resp proto.Message](r *Registry, op Op, apply func(ctx context.Context, request Req) (Resp, error)) {
+	var request Req
+	var response Resp
+	requestType := request.ProtoReflect().Type()
+	responseType := response.ProtoReflect().Type()
+	fullName := requestType.Descriptor().FullName()
+	name := strings.TrimSuffix(string(requestType.Descriptor().Name()), "Request")
+	if existing, found := r.commands[op]; found {
+		panic(fmt.Sprintf("Register: op %d of %s is registered already for %s", op, name, existing.name))
+	}
+	if existing, found := r.ops[fullName]; found {
+		panic(fmt.Sprintf("Register: %s is registered already as op %d", name, existing))
+	}
+	r.ops[fullName] = op
+	r.commands[op] = &command{
+		name:         name,
+		requestType:  requestType,
+		responseType: responseType,
+		apply: func(ctx context.Context, request proto.Message) (proto.Message, error) {
+			response, err := apply(ctx, request.(Req))
+			if !response.ProtoReflect().IsValid() {
+				return nil, err
+			}
+			return response, err
+		},
+	}
+}
+
+// Registered reports whether request is a registered write.
+func (r *Registry) Registered(request proto.Message) bool {
+	_, found := r.ops[request.ProtoReflect().Descriptor().FullName()]
+	return found
+}
+
+// Name returns the name of op, for logging.
+func (r *Registry) Name(op Op) string {
+	if command, found := r.commands[op]; found {
+		return command.name
+	}
+	return fmt.Sprintf("unknown op %d", op)
+}
+
+// NewResponse returns an empty response of the registered write request.
+func (r *Registry) NewResponse(request proto.Message) proto.Message {
+	command := r.commands[r.ops[request.ProtoReflect().Descriptor().FullName()]]
+	return command.responseType.New().Interface()
+}
+
+// ErrorResponse returns the response of the registered write request failing
+// with err: its statusCode and err fields set, if it has them.
+func (r *Registry) ErrorResponse(request proto.Message, statusCode int, err error) proto.Message {
+	response := r.NewResponse(request).ProtoReflect()
+	fields := response.Descriptor().Fields()
+	if field := fields.ByName("statusCode"); field != nil && field.Kind() == protoreflect.Int32Kind {
+		response.Set(field, protoreflect.ValueOfInt32(int32(statusCode)))
+	}
+	if field := fields.ByName("err"); field != nil && err != nil {
+		response.Set(field, protoreflect.ValueOfMessage(common.ConvertErrorToProtoError(err).ProtoReflect()))
+	}
+	return response.Interface()
+}
+
+// Apply decodes and executes a write the engine has ordered. It's the
+// ApplyFunc of the service. An op that isn't registered fails: the write was
+// proposed by a build with other commands.
+func (r *Registry) Apply(ctx context.Context, requestID string, op Op, payload []byte) Result {
+	command, found := r.commands[op]
+	if !found {
+		err := status.Errorf(codes.Internal, "unknown op %d in request %s", op, requestID)
+		log.Errorf("Apply: %v\n", err)
+		return Result{Err: err}
+	}
+	request := command.requestType.New().Interface()
+	if err := proto.Unmarshal(payload, request); err != nil {
+		err = fmt.Errorf("exception while Unmarshalling %s Msg: %v", command.name, err)
+		log.Errorf("Apply: %v\n", err)
+		return Result{Err: err}
+	}
+	response, err := command.apply(ctx, request)
+	return Result{Response: response, Err: err}
+}
+
+// Propose replicates the registered write request through engine and returns
+// the result of applying it on this replica.
+func (r *Registry) Propose(ctx context.Context, engine Engine, request proto.Message) Result {
+	op, found := r.ops[request.ProtoReflect().Descriptor().FullName()]
+	if !found {
+		err := status.Errorf(codes.Unimplemented, "%T is not a registered write", request)
+		log.Errorf("Propose: %v\n", err)
+		return Result{Err: err}
+	}
+	name := r.commands[op].name
+	payload, err := proto.Marshal(request)
+	if err != nil {
+		err = fmt.Errorf("exception while Marshalling %s Msg: %v", name, err)
+		log.Errorf("Propose: %v\n", err)
+		return Result{Err: err}
+	}
+	log.Infof("%s: Waiting for the write to be replicated.\n", name)
+	result := engine.Propose(ctx, op, payload)
+	log.Infof("%s: write replicated!\n", name)
+	return result
+}
+
+// UnaryServerInterceptor serves every registered write a gRPC server receives
+// by replicating it through engine, in place of its handler. Other requests
+// go to their handler.
+func (r *Registry) UnaryServerInterceptor(engine Engine) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		request, ok := req.(proto.Message)
+		if !ok || !r.Registered(request) {
+			return handler(ctx, req)
+		}
+		result := r.Propose(ctx, engine, request)
+		if result.Response == nil {
+			return r.ErrorResponse(request, r.statusCode(result.Err), result.Err), result.Err
+		}
+		return result.Response, result.Err
+	}
+}
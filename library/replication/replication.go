@@ -0,0 +1,67 @@
+// Package replication is the interface between the database services and the
+// protocols that replicate their writes. A service hands each write to an
+// Engine as an operation code and a payload; the engine orders the writes of
+// every replica and calls the service's ApplyFunc with each of them, in the
+// same order on every replica. Reads are served from the local database. A
+// Registry maps the write requests of a service to their operation codes.
+//
+// Two engines implement it: the Raft Consensus Module in library/raft and the
+// rotating sequencer in library/totem. Which one a service runs on is chosen
+// with REPLICATION_ENGINE.
+package replication
+
+import (
+	"context"
+	"fmt"
+
+	"google.golang.org/protobuf/proto"
+)
+
+// Names of the engines, as given in REPLICATION_ENGINE.
+const (
+	EngineRaft      = "raft"
+	EngineSequencer = "sequencer"
+)
+
+// Op identifies the kind of a replicated write. Its values are stored in the
+// logs of the engines, so a service must not change them.
+type Op int32
+
+// Result is the outcome of applying a write.
+type Result struct {
+	Response proto.Message
+	Err      error
+}
+
+// ApplyFunc applies a write the engine has ordered to the local database. It
+// runs on every replica, one write at a time, in the order the engine agreed
+// on, so it has to be deterministic. requestID identifies the write across
+// replicas.
+type ApplyFunc func(ctx context.Context, requestID string, op Op, payload []byte) Result
+
+// Engine replicates the writes of a service.
+type Engine interface {
+	// Start joins the replica group. apply is called with every write
+	// ordered from then on, this replica's own included.
+	Start(ctx context.Context, apply ApplyFunc) error
+
+	// Propose replicates a write and waits until this replica has applied
+	// it, returning the result of applying it here. A write whose result
+	// isn't known, because ctx is done or the engine gave up on it, may
+	// still be applied later.
+	Propose(ctx context.Context, op Op, payload []byte) Result
+
+	// Barrier waits until this replica has applied every write that was
+	// applied anywhere before it was called, so that a read served locally
+	// afterwards is linearizable.
+	Barrier(ctx context.Context) error
+}
+
+// ValidateEngineName returns an error for an unknown REPLICATION_ENGINE.
+func ValidateEngineName(name string) error {
+	switch name {
+	case EngineRaft, EngineSequencer:
+		return nil
+	}
+	return fmt.Errorf("unknown replication engine %q. expected %q or %q", name, EngineRaft, EngineSequencer)
+}
@@ -0,0 +1,254 @@
+package totem
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"hash/crc32"
+	"io/fs"
+	"os"
+	"path/filepath"
+
+	log "github.com/sirupsen/logrus"
+)
+
+const (
+	deliveryLogFileName = "delivery.log"
+
+	// deliveryLogMagic starts every delivery log file.
+	deliveryLogMagic = "TOTEMLG2"
+
+	// deliveryLogHeaderSize is the size of the magic, the base and the
+	// length of the watermarks.
+	deliveryLogHeaderSize = 16
+
+	// deliveryLogCompactThreshold is how many stable messages the delivery
+	// log holds before it's compacted.
+	deliveryLogCompactThreshold = 1024
+
+	// deliveryLogRecordHeaderSize is the size of the length and the CRC32
+	// that precede each record.
+	deliveryLogRecordHeaderSize = 8
+)
+
+// deliveryLog is the durable record of the sequence messages this node has
+// delivered, in delivery order. A message is appended, and synced, right
+// before it's applied, so after a crash the log holds every write in the
+// database, and possibly the one being applied when the node went down. The
+// service records the last message it applied along with its writes, so that
+// one can tell whether to apply it again.
+//
+// The log only keeps the messages after its base, a point of the total order
+// it was reset to when the node installed a snapshot, or compacted to once
+// the messages before it were stable. The file starts with the magic, then
+// the base: the number of messages before the first one logged, and the
+// length of the last request of each node delivered up to there, both as
+// big-endian uint32s, then those requests as JSON. One record per message
+// follows: its length and CRC32 as big-endian uint32s, then the marshalled
+// TotemMessage.
+type deliveryLog struct {
+	dir  string
+	path string
+	file *os.File
+
+	// base is the sequence number the file starts after.
+	base int32
+}
+
+// openDeliveryLog opens the delivery log in dir, creating it if needed, and
+// returns its base and the messages it holds. A torn record at the end of the
+// file, left by a crash in the middle of an append, is cut off.
+func openDeliveryLog(ctx context.Context, dir string) (*deliveryLog, SnapshotPoint, []message, error) {
+	if err := os.MkdirAll(dir, 0755); err != nil {
+		return nil, SnapshotPoint{}, nil, fmt.Errorf("exception while creating dir %s. %v", dir, err)
+	}
+	path := filepath.Join(dir, deliveryLogFileName)
+	data, err := os.ReadFile(path)
+	if err != nil && !errors.Is(err, fs.ErrNotExist) {
+		return nil, SnapshotPoint{}, nil, fmt.Errorf("exception while reading %s. %v", path, err)
+	}
+	l := &deliveryLog{dir: dir, path: path}
+	if len(data) == 0 {
+		if err := l.reset(ctx, SnapshotPoint{}); err != nil {
+			return nil, SnapshotPoint{}, nil, err
+		}
+		return l, SnapshotPoint{}, nil, nil
+	}
+
+	base, headerSize, err := parseDeliveryLogHeader(data)
+	if err != nil {
+		return nil, SnapshotPoint{}, nil, fmt.Errorf("exception while parsing %s. %v", path, err)
+	}
+	msgs, validLen, err := parseDeliveryLog(ctx, data, headerSize, base.GlobalSeqNum)
+	if err != nil {
+		return nil, SnapshotPoint{}, nil, fmt.Errorf("exception while parsing %s. %v", path, err)
+	}
+	if validLen < len(data) {
+		log.Warnf("openDeliveryLog: Truncating torn record at offset %d of %s\n", validLen, path)
+		if err := os.Truncate(path, int64(validLen)); err != nil {
+			return nil, SnapshotPoint{}, nil, fmt.Errorf("exception while truncating %s. %v", path, err)
+		}
+	}
+	if l.file, err = os.OpenFile(path, os.O_WRONLY|os.O_APPEND, 0644); err != nil {
+		return nil, SnapshotPoint{}, nil, fmt.Errorf("exception while opening %s. %v", path, err)
+	}
+	l.base = base.GlobalSeqNum
+	return l, base, msgs, nil
+}
+
+// parseDeliveryLogHeader returns the base of a delivery log and the size of
+// its header. The header is written at once, when the file is replaced, so
+// it's never torn.
+func parseDeliveryLogHeader(data []byte) (SnapshotPoint, int, error) {
+	if len(data) < deliveryLogHeaderSize || string(data[:len(deliveryLogMagic)]) != deliveryLogMagic {
+		return SnapshotPoint{}, 0, fmt.Errorf("not a delivery log")
+	}
+	base := SnapshotPoint{GlobalSeqNum: int32(binary.BigEndian.Uint32(data[len(deliveryLogMagic):]))}
+	watermarksSize := int(binary.BigEndian.Uint32(data[len(deliveryLogMagic)+4:]))
+	headerSize := deliveryLogHeaderSize + watermarksSize
+	if len(data) < headerSize {
+		return SnapshotPoint{}, 0, fmt.Errorf("header of %d bytes cut short", headerSize)
+	}
+	if err := json.Unmarshal(data[deliveryLogHeaderSize:headerSize], &base.DeliveredRequests); err != nil {
+		return SnapshotPoint{}, 0, fmt.Errorf("exception while decoding request watermarks. %v", err)
+	}
+	return base, headerSize, nil
+}
+
+// parseDeliveryLog returns the messages of a delivery log and the length of
+// the intact part of it. Parsing stops at the first record that is cut short
+// or fails its checksum.
+func parseDeliveryLog(ctx context.Context, data []byte, headerSize int, base int32) ([]message, int, error) {
+	var msgs []message
+	offset := headerSize
+	for offset+deliveryLogRecordHeaderSize <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[offset:]))
+		checksum := binary.BigEndian.Uint32(data[offset+4:])
+		end := offset + deliveryLogRecordHeaderSize + length
+		if end > len(data) {
+			break
+		}
+		record := data[offset+deliveryLogRecordHeaderSize : end]
+		if crc32.ChecksumIEEE(record) != checksum {
+			break
+		}
+		msg, err := unmarshallMsg(ctx, record)
+		if err != nil {
+			break
+		}
+		if msg.GlobalSeqNum != base+int32(len(msgs))+1 {
+			return nil, 0, fmt.Errorf("msg %d at offset %d follows msg %d", msg.GlobalSeqNum, offset, base+int32(len(msgs)))
+		}
+		msgs = append(msgs, *msg)
+		offset = end
+	}
+	return msgs, offset, nil
+}
+
+// append adds a delivered message to the log and syncs it to disk.
+func (l *deliveryLog) append(ctx context.Context, msg *message) error {
+	record, err := encodeDeliveryLogRecord(ctx, msg)
+	if err != nil {
+		return err
+	}
+	if _, err := l.file.Write(record); err != nil {
+		return fmt.Errorf("exception while writing record. %v", err)
+	}
+	if err := l.file.Sync(); err != nil {
+		return fmt.Errorf("exception while syncing record. %v", err)
+	}
+	return nil
+}
+
+// encodeDeliveryLogRecord returns the record of msg, with its length and
+// CRC32.
+func encodeDeliveryLogRecord(ctx context.Context, msg *message) ([]byte, error) {
+	data, err := marshallMsg(ctx, msg)
+	if err != nil {
+		return nil, fmt.Errorf("exception while marshalling msg. %v", err)
+	}
+	record := make([]byte, deliveryLogRecordHeaderSize, deliveryLogRecordHeaderSize+len(data))
+	binary.BigEndian.PutUint32(record, uint32(len(data)))
+	binary.BigEndian.PutUint32(record[4:], crc32.ChecksumIEEE(data))
+	return append(record, data...), nil
+}
+
+// reset replaces the log with an empty one whose base is base.
+func (l *deliveryLog) reset(ctx context.Context, base SnapshotPoint) error {
+	return l.compact(ctx, base, nil)
+}
+
+// compact replaces the log with one whose base is base, holding the messages
+// of tail, which follow it. The new log is written next to the old one and
+// renamed over it, so a crash leaves one or the other.
+func (l *deliveryLog) compact(ctx context.Context, base SnapshotPoint, tail []message) error {
+	watermarks, err := json.Marshal(base.DeliveredRequests)
+	if err != nil {
+		return fmt.Errorf("exception while encoding request watermarks. %v", err)
+	}
+	data := make([]byte, deliveryLogHeaderSize, deliveryLogHeaderSize+len(watermarks))
+	copy(data, deliveryLogMagic)
+	binary.BigEndian.PutUint32(data[len(deliveryLogMagic):], uint32(base.GlobalSeqNum))
+	binary.BigEndian.PutUint32(data[len(deliveryLogMagic)+4:], uint32(len(watermarks)))
+	data = append(data, watermarks...)
+	for i := range tail {
+		record, err := encodeDeliveryLogRecord(ctx, &tail[i])
+		if err != nil {
+			return err
+		}
+		data = append(data, record...)
+	}
+
+	tmpPath := l.path + ".tmp"
+	if err := writeFileSync(tmpPath, data); err != nil {
+		return fmt.Errorf("exception while writing %s. %v", tmpPath, err)
+	}
+	if err := os.Rename(tmpPath, l.path); err != nil {
+		return fmt.Errorf("exception while renaming %s. %v", tmpPath, err)
+	}
+	if err := syncDir(l.dir); err != nil {
+		return fmt.Errorf("exception while syncing dir %s. %v", l.dir, err)
+	}
+
+	file, err := os.OpenFile(l.path, os.O_WRONLY|os.O_APPEND, 0644)
+	if err != nil {
+		return fmt.Errorf("exception while opening %s. %v", l.path, err)
+	}
+	if l.file != nil {
+		l.file.Close()
+	}
+	l.file = file
+	l.base = base.GlobalSeqNum
+	return nil
+}
+
+func writeFileSync(path string, data []byte) error {
+	file, err := os.OpenFile(path, os.O_WRONLY|os.O_CREATE|os.O_TRUNC, 0644)
+	if err != nil {
+		return err
+	}
+	if _, err := file.Write(data); err != nil {
+		file.Close()
+		return err
+	}
+	if err := file.Sync(); err != nil {
+		file.Close()
+		return err
+	}
+	return file.Close()
+}
+
+func syncDir(dir string) error {
+	d, err := os.Open(dir)
+	if err != nil {
+		return err
+	}
+	defer d.Close()
+	return d.Sync()
+}
+
+func (l *deliveryLog) close() error {
+	return l.file.Close()
+}
@@ -0,0 +1,223 @@
+package totem
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"github.com/adarshsrinivasan/DS_S24/library/peerauth"
+	"github.com/adarshsrinivasan/DS_S24/library/replication"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// requestTimeout bounds how long Propose waits for a write to be
+	// delivered.
+	requestTimeout = 10 * time.Second
+
+	// barrierOp marks the requests Barrier sends through the total order.
+	// They aren't handed to the service.
+	barrierOp replication.Op = -1
+)
+
+// Config is the configuration of the sequencer of a node.
+type Config struct {
+	// NodeName is the name of this node, and PeerNodeNames and PeerNodePorts
+	// the names and sync ports of every node of the group, this one
+	// included.
+	NodeName      string
+	PeerNodeNames []string
+	PeerNodePorts []string
+
+	// ListenAddr is the UDP address this node receives messages at.
+	ListenAddr string
+
+	// StorageDir holds the delivery log. If it's empty, delivered messages
+	// are only kept in memory and a restarted node can't tell which writes
+	// it already applied.
+	StorageDir string
+
+	// FetchSnapshot installs a snapshot from a peer, for a node that fell
+	// too far behind to catch up through retransmits. If it's nil, such a
+	// node stops delivering.
+	FetchSnapshot SnapshotFetcher
+
+	// InstalledSnapshot returns where the last snapshot FetchSnapshot
+	// installed was taken, as recorded along with it, or false if none was.
+	// It's only used with StorageDir, to tell a delivery log left behind by
+	// a crash during a state transfer.
+	InstalledSnapshot func(ctx context.Context) (SnapshotPoint, bool, error)
+
+	// AppliedSeqNum returns the sequence number of the last message whose
+	// write is in the database, or false if none was recorded. The service
+	// records it in the same transaction as the write, reading it from the
+	// context apply gets with GlobalSeqNumFromContext. The messages logged
+	// after it are applied again on restart. It's required with StorageDir.
+	// A database that predates it holds no record; every message logged is
+	// then taken as applied.
+	AppliedSeqNum func(ctx context.Context) (int32, bool, error)
+
+	// Keyring signs the datagrams this node sends and checks those it
+	// receives. If it's nil, anyone who can reach ListenAddr can inject
+	// messages.
+	Keyring *peerauth.Keyring
+}
+
+// globalSeqNumContextKey is the context key under which the sequence number of
+// the message being applied is stored.
+type globalSeqNumContextKey struct{}
+
+// GlobalSeqNumFromContext returns the sequence number of the message whose
+// write is being applied with ctx, or false if ctx doesn't come from the
+// sequencer.
+func GlobalSeqNumFromContext(ctx context.Context) (int32, bool) {
+	globalSeqNum, ok := ctx.Value(globalSeqNumContextKey{}).(int32)
+	return globalSeqNum, ok
+}
+
+// Engine is a replication.Engine running the sequencer of this node.
+type Engine struct {
+	config    Config
+	sequencer *sequencer
+}
+
+var _ replication.Engine = (*Engine)(nil)
+
+func NewEngine(config Config) *Engine {
+	return &Engine{config: config}
+}
+
+// Start restores the messages delivered by an earlier run of this node from
+// the delivery log, applying the ones the database is missing, starts the
+// sequencer and listens for messages from the peers until ctx is done.
+func (e *Engine) Start(ctx context.Context, apply replication.ApplyFunc) error {
+	nodeName := e.config.NodeName
+	var durableLog *deliveryLog
+	var snapshot SnapshotPoint
+	var delivered []message
+	var applied int32
+	if e.config.StorageDir != "" {
+		if e.config.AppliedSeqNum == nil {
+			return fmt.Errorf("AppliedSeqNum is required with StorageDir")
+		}
+		var base SnapshotPoint
+		var err error
+		if durableLog, base, delivered, err = openDeliveryLog(ctx, e.config.StorageDir); err != nil {
+			return fmt.Errorf("exception while opening delivery log. %v", err)
+		}
+		if snapshot, delivered, err = e.checkInstalledSnapshot(ctx, durableLog, base, delivered); err != nil {
+			return err
+		}
+		if applied, err = e.appliedSeqNum(ctx, snapshot, delivered); err != nil {
+			return err
+		}
+		log.Infof("Start(%s): Restored %d delivered msgs after %d from %s, applied up to %d\n", nodeName, len(delivered), snapshot.GlobalSeqNum, e.config.StorageDir, applied)
+	} else {
+		log.Warnf("Start(%s): No storage dir set. Delivered msgs will not survive a restart.", nodeName)
+	}
+
+	conn, err := listenFromPeers(ctx, e.config.ListenAddr)
+	if err != nil {
+		return err
+	}
+	transport := newUDPTransport(nodeName, e.config.PeerNodeNames, e.config.PeerNodePorts, e.config.Keyring)
+	e.sequencer = newSequencer(nodeName, e.config.PeerNodeNames, transport, durableLog, e.config.FetchSnapshot, func(ctx context.Context, msg *message) replication.Result {
+		if msg.OpsType == barrierOp {
+			return replication.Result{}
+		}
+		ctx = context.WithValue(ctx, globalSeqNumContextKey{}, msg.GlobalSeqNum)
+		return apply(ctx, msg.ID, msg.OpsType, msg.Payload)
+	})
+	e.sequencer.restore(ctx, snapshot, delivered, applied)
+	go e.sequencer.run(ctx)
+	go receiveFromPeers(ctx, e.sequencer, conn, e.config.Keyring)
+	go func() {
+		<-e.sequencer.stopped
+		conn.Close()
+	}()
+	return nil
+}
+
+// checkInstalledSnapshot reconciles the delivery log with the last snapshot
+// installed. A crash after a snapshot was installed but before the log was
+// reset leaves a log that ends before the snapshot; it's reset to the
+// snapshot. It returns the base and messages to restore.
+func (e *Engine) checkInstalledSnapshot(ctx context.Context, durableLog *deliveryLog, base SnapshotPoint, delivered []message) (SnapshotPoint, []message, error) {
+	if e.config.InstalledSnapshot == nil {
+		return base, delivered, nil
+	}
+	snapshot, ok, err := e.config.InstalledSnapshot(ctx)
+	if err != nil {
+		return SnapshotPoint{}, nil, fmt.Errorf("exception while reading installed snapshot. %v", err)
+	}
+	if !ok || snapshot.GlobalSeqNum <= base.GlobalSeqNum+int32(len(delivered)) {
+		return base, delivered, nil
+	}
+
+	log.Warnf("checkInstalledSnapshot(%s): Delivery log ends at sequence msg %d, before the snapshot installed at %d. Resetting it.\n", e.config.NodeName, base.GlobalSeqNum+int32(len(delivered)), snapshot.GlobalSeqNum)
+	if err := durableLog.reset(ctx, snapshot); err != nil {
+		return SnapshotPoint{}, nil, fmt.Errorf("exception while resetting delivery log. %v", err)
+	}
+	return snapshot, nil, nil
+}
+
+// appliedSeqNum returns the sequence number of the last message the database
+// holds the write of. Without a record, every message restored is taken as
+// applied.
+func (e *Engine) appliedSeqNum(ctx context.Context, snapshot SnapshotPoint, delivered []message) (int32, error) {
+	applied, ok, err := e.config.AppliedSeqNum(ctx)
+	if err != nil {
+		return 0, fmt.Errorf("exception while reading applied sequence number. %v", err)
+	}
+	if !ok {
+		log.Warnf("appliedSeqNum(%s): No applied sequence number recorded. Taking every logged msg as applied.\n", e.config.NodeName)
+		return snapshot.GlobalSeqNum + int32(len(delivered)), nil
+	}
+	return applied, nil
+}
+
+// Propose broadcasts the write to the group and waits until this node
+// delivers it. It gives up after requestTimeout, or once ctx is done; the
+// write may still be delivered in that case. A write larger than
+// maxPayloadSize is refused, since peers couldn't reassemble it.
+func (e *Engine) Propose(ctx context.Context, op replication.Op, payload []byte) replication.Result {
+	if len(payload) > maxPayloadSize {
+		err := status.Errorf(codes.InvalidArgument, "write of %d bytes exceeds the limit of %d bytes", len(payload), maxPayloadSize)
+		log.Errorf("Propose: %v\n", err)
+		return replication.Result{Err: err}
+	}
+	requestID, resultChan := e.sequencer.submit(op, payload)
+	ctx, cancel := context.WithTimeout(ctx, requestTimeout)
+	defer cancel()
+	select {
+	case result := <-resultChan:
+		return result
+	case <-ctx.Done():
+	}
+	e.sequencer.abandon(requestID)
+	select {
+	case result := <-resultChan:
+		// Delivered just before we gave up.
+		return result
+	default:
+	}
+	err := status.Errorf(codes.DeadlineExceeded, "gave up waiting for request %s. it may or may not be applied. %v", requestID, ctx.Err())
+	log.Errorf("Propose: %v\n", err)
+	return replication.Result{Err: err}
+}
+
+// Barrier sends an empty request through the total order. Once this node
+// delivers it, it has applied every write sequenced before it.
+func (e *Engine) Barrier(ctx context.Context) error {
+	return e.Propose(ctx, barrierOp, nil).Err
+}
+
+// AtDeliveredSeqNum runs fn on the event loop, between two deliveries, and
+// returns the position in the total order it ran at. It's meant for the
+// service to start reading a snapshot for a peer. It fails while this node is
+// installing a snapshot itself.
+func (e *Engine) AtDeliveredSeqNum(ctx context.Context, fn func() error) (SnapshotPoint, error) {
+	return e.sequencer.atDeliveredSeqNum(ctx, fn)
+}
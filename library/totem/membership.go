@@ -0,0 +1,341 @@
+package totem
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Membership of the group.
+//
+// Every node heartbeats to every other. The first node, in peerNodeNames
+// order, that a node has heard from within failureTimeout is the
+// coordinator. When the live nodes differ from the members of the current
+// view, the coordinator proposes a new view to the live nodes. A member that
+// accepts the proposal stops sequencing and acks it with the number of
+// messages it has delivered. Once all of them ack, the coordinator installs
+// the view: the messages up to the highest of those numbers are kept, and
+// the ones after it rotate over the new members. Sequence messages carry
+// their view, so a suspected sequencer can't slip a message into the new
+// order.
+//
+// A view needs a majority of the configured nodes, so at most one view can
+// make progress. A node that restarts or was left out heartbeats with an
+// older view; the coordinator sends it the current view, or proposes one
+// that includes it, and the node fetches the messages it missed through
+// retransmits. A node doesn't sequence until it has either installed a view
+// or heard from a peer in its view that hasn't delivered more than it has:
+// until then, its view may be one the group has left, and its turn may be
+// one it took before it went down.
+
+const (
+	// heartbeatInterval is how often a node heartbeats to its peers.
+	heartbeatInterval = 500 * time.Millisecond
+
+	// failureTimeout is how long a node goes unheard before it's suspected.
+	failureTimeout = 3 * time.Second
+
+	// viewChangeTimeout is how long a view change may take before the
+	// coordinator gives up on it and proposes another.
+	viewChangeTimeout = 3 * time.Second
+
+	// maxCatchUpBatch is the most sequence messages a node asks a peer for
+	// on one heartbeat.
+	maxCatchUpBatch = 64
+)
+
+// view is a membership of the group. Sequence numbers after BaseSeqNum
+// rotate over Members.
+type view struct {
+	ID         int32
+	Members    []string
+	BaseSeqNum int32
+}
+
+func (v *view) hasMember(nodeName string) bool {
+	return containsNodeName(v.Members, nodeName)
+}
+
+// viewProposal is a view change this node coordinates.
+type viewProposal struct {
+	view      view
+	acks      map[string]int32
+	startedAt time.Time
+}
+
+func containsNodeName(nodeNames []string, nodeName string) bool {
+	for _, name := range nodeNames {
+		if name == nodeName {
+			return true
+		}
+	}
+	return false
+}
+
+func sameNodeNames(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	for i := range a {
+		if a[i] != b[i] {
+			return false
+		}
+	}
+	return true
+}
+
+// frozen reports whether this node acked a view that isn't installed yet.
+func (s *sequencer) frozen() bool {
+	return s.ackedViewID > s.view.ID
+}
+
+// aliveNodes returns the nodes heard from within failureTimeout, this one
+// included, in peerNodeNames order.
+func (s *sequencer) aliveNodes(now time.Time) []string {
+	var alive []string
+	for _, peerNodeName := range s.peerNodeNames {
+		if lastHeard, ok := s.lastHeard[peerNodeName]; peerNodeName == s.nodeName || (ok && now.Sub(lastHeard) < failureTimeout) {
+			alive = append(alive, peerNodeName)
+		}
+	}
+	return alive
+}
+
+func (s *sequencer) hasMajority(nodeNames []string) bool {
+	return len(nodeNames) > len(s.peerNodeNames)/2
+}
+
+// canSequence reports whether this node may send sequence messages: it's an
+// up to date member of a confirmed view, isn't installing a snapshot, and
+// hears from a majority.
+func (s *sequencer) canSequence(now time.Time) bool {
+	return s.viewConfirmed && !s.transferring && !s.frozen() && s.view.hasMember(s.nodeName) && s.globalCounter >= s.view.BaseSeqNum && s.hasMajority(s.aliveNodes(now))
+}
+
+// acceptsSequenceMsg reports whether a sequence message belongs to the order
+// this node follows: it's agreed on by an earlier view, or it's from the
+// current one.
+func (s *sequencer) acceptsSequenceMsg(msg *message) bool {
+	return msg.GlobalSeqNum <= s.view.BaseSeqNum || (msg.ViewID == s.view.ID && !s.frozen())
+}
+
+// checkIncarnation tracks the incarnation of the node a request comes from,
+// and reports whether the request is from its latest one. A restarted node
+// numbers its requests from 1 again.
+func (s *sequencer) checkIncarnation(ctx context.Context, msg *message) bool {
+	nodeName := msg.RequestNodeName
+	incarnation, ok := s.incarnations[nodeName]
+	if ok && msg.Incarnation < incarnation {
+		return false
+	}
+	if ok && msg.Incarnation == incarnation {
+		return true
+	}
+	s.incarnations[nodeName] = msg.Incarnation
+	if !ok {
+		return true
+	}
+
+	log.Infof("checkIncarnation(%s): %s restarted. Dropping its requests still out of order.\n", s.nodeName, nodeName)
+	s.lastLocalSeqBuffered[nodeName] = 0
+	for key, bufferedReqMsg := range s.outOfOrderBufferedRequestMsgs {
+		if bufferedReqMsg.RequestNodeName == nodeName {
+			delete(s.outOfOrderBufferedRequestMsgs, key)
+		}
+	}
+	for key, trackedMsg := range s.retransmitTracker {
+		if trackedMsg.MsgType == MsgType_Request && trackedMsg.RequestNodeName == nodeName {
+			delete(s.retransmitTracker, key)
+		}
+	}
+	return true
+}
+
+// tick heartbeats and acks to the peers, retransmits what is due, drops the
+// stable messages and starts a view change if one is due.
+func (s *sequencer) tick(ctx context.Context, now time.Time) {
+	heartbeat := &message{
+		MsgType:        MsgType_Heartbeat,
+		SenderNodeName: s.nodeName,
+		ViewID:         s.view.ID,
+		LocalSeqNum:    -1,
+		GlobalSeqNum:   s.globalCounter,
+		LogBaseSeqNum:  s.logBaseSeqNum,
+	}
+	for _, peerNodeName := range s.peerNodeNames {
+		if peerNodeName != s.nodeName {
+			s.sendMsgToNode(ctx, peerNodeName, heartbeat)
+		}
+	}
+	s.sendStabilityAck(ctx, now)
+	s.retransmitDue(ctx, now)
+	s.collectStableMsgs(ctx)
+	s.checkViewChange(ctx, now)
+}
+
+func (s *sequencer) handleHeartbeat(ctx context.Context, msg *message, now time.Time) {
+	s.lastHeard[msg.SenderNodeName] = now
+	// A peer that delivered more reveals sequence messages lost on the way
+	// here, which no later message would. They're fetched from it a batch
+	// at a time, or, if it no longer keeps them, through a state transfer.
+	if msg.GlobalSeqNum > s.globalCounter && msg.LogBaseSeqNum > s.globalCounter {
+		s.startStateTransfer(ctx, msg.SenderNodeName)
+	} else if msg.ViewID == s.view.ID && msg.GlobalSeqNum > s.globalCounter && !s.transferring {
+		to := msg.GlobalSeqNum
+		if to > s.globalCounter+maxCatchUpBatch {
+			to = s.globalCounter + maxCatchUpBatch
+		}
+		s.sendSequenceRetransmitToNode(ctx, msg.SenderNodeName, s.globalCounter+1, to)
+	}
+	if msg.ViewID == s.view.ID && msg.GlobalSeqNum <= s.globalCounter && !s.viewConfirmed {
+		log.Infof("handleHeartbeat(%s): %s confirmed view %d.\n", s.nodeName, msg.SenderNodeName, s.view.ID)
+		s.viewConfirmed = true
+	}
+	if msg.ViewID >= s.view.ID || !s.view.hasMember(msg.SenderNodeName) {
+		return
+	}
+	// A member that missed the install, or restarted since, gets it again.
+	if alive := s.aliveNodes(now); alive[0] == s.nodeName && !s.frozen() {
+		log.Infof("handleHeartbeat(%s): %s is in view %d. Sending it view %d.\n", s.nodeName, msg.SenderNodeName, msg.ViewID, s.view.ID)
+		s.sendMsgToNode(ctx, msg.SenderNodeName, s.viewInstallMsg(s.view))
+	}
+}
+
+// checkViewChange proposes a new view if this node is the coordinator and
+// the live nodes aren't the members of the current view, or a view change
+// got stuck.
+func (s *sequencer) checkViewChange(ctx context.Context, now time.Time) {
+	if s.proposal != nil && now.Sub(s.proposal.startedAt) > viewChangeTimeout {
+		log.Warnf("checkViewChange(%s): View change to %d timed out.\n", s.nodeName, s.proposal.view.ID)
+		s.proposal = nil
+	}
+	alive := s.aliveNodes(now)
+	if s.proposal != nil || alive[0] != s.nodeName || !s.hasMajority(alive) {
+		return
+	}
+	stuck := s.frozen() && now.Sub(s.ackedAt) > viewChangeTimeout
+	if sameNodeNames(alive, s.view.Members) && !stuck {
+		return
+	}
+
+	// View IDs are unique per coordinator: this node's are its rank modulo
+	// the number of nodes.
+	numNodes := int32(len(s.peerNodeNames))
+	rank := int32(0)
+	for i, peerNodeName := range s.peerNodeNames {
+		if peerNodeName == s.nodeName {
+			rank = int32(i)
+		}
+	}
+	viewID := s.maxSeenViewID + 1
+	for viewID%numNodes != rank {
+		viewID++
+	}
+	s.maxSeenViewID = viewID
+
+	log.Infof("checkViewChange(%s): Proposing view %d with members %v. Current view %d has %v.\n", s.nodeName, viewID, alive, s.view.ID, s.view.Members)
+	s.proposal = &viewProposal{
+		view:      view{ID: viewID, Members: alive},
+		acks:      map[string]int32{},
+		startedAt: now,
+	}
+	proposal := &message{
+		MsgType:        MsgType_ViewProposal,
+		SenderNodeName: s.nodeName,
+		ViewID:         viewID,
+		Members:        alive,
+		LocalSeqNum:    -1,
+		GlobalSeqNum:   -1,
+	}
+	for _, member := range alive {
+		s.sendMsgToNode(ctx, member, proposal)
+	}
+}
+
+func (s *sequencer) handleViewProposal(ctx context.Context, msg *message, now time.Time) {
+	if msg.ViewID > s.maxSeenViewID {
+		s.maxSeenViewID = msg.ViewID
+	}
+	if msg.ViewID <= s.view.ID || msg.ViewID < s.ackedViewID || !containsNodeName(msg.Members, s.nodeName) {
+		log.Infof("handleViewProposal(%s): Ignoring proposal of view %d from %s. view: %d, acked: %d\n", s.nodeName, msg.ViewID, msg.SenderNodeName, s.view.ID, s.ackedViewID)
+		return
+	}
+	if s.proposal != nil && s.proposal.view.ID < msg.ViewID {
+		s.proposal = nil
+	}
+	s.ackedViewID = msg.ViewID
+	s.ackedAt = now
+	s.sendMsgToNode(ctx, msg.SenderNodeName, &message{
+		MsgType:        MsgType_ViewAck,
+		SenderNodeName: s.nodeName,
+		ViewID:         msg.ViewID,
+		LocalSeqNum:    -1,
+		GlobalSeqNum:   s.globalCounter,
+	})
+}
+
+func (s *sequencer) handleViewAck(ctx context.Context, msg *message) {
+	if s.proposal == nil || msg.ViewID != s.proposal.view.ID || !s.proposal.view.hasMember(msg.SenderNodeName) {
+		return
+	}
+	s.proposal.acks[msg.SenderNodeName] = msg.GlobalSeqNum
+	if len(s.proposal.acks) < len(s.proposal.view.Members) {
+		return
+	}
+
+	newView := s.proposal.view
+	for _, delivered := range s.proposal.acks {
+		if delivered > newView.BaseSeqNum {
+			newView.BaseSeqNum = delivered
+		}
+	}
+	s.proposal = nil
+	install := s.viewInstallMsg(newView)
+	for _, member := range newView.Members {
+		s.sendMsgToNode(ctx, member, install)
+	}
+}
+
+func (s *sequencer) viewInstallMsg(v view) *message {
+	return &message{
+		MsgType:        MsgType_ViewInstall,
+		SenderNodeName: s.nodeName,
+		ViewID:         v.ID,
+		Members:        v.Members,
+		LocalSeqNum:    -1,
+		GlobalSeqNum:   v.BaseSeqNum,
+	}
+}
+
+func (s *sequencer) handleViewInstall(ctx context.Context, msg *message) {
+	if msg.ViewID <= s.view.ID || msg.ViewID < s.ackedViewID || !containsNodeName(msg.Members, s.nodeName) {
+		return
+	}
+	newView := view{ID: msg.ViewID, Members: msg.Members, BaseSeqNum: msg.GlobalSeqNum}
+	log.Infof("handleViewInstall(%s): Installing view %d with members %v after sequence msg %d.\n", s.nodeName, newView.ID, newView.Members, newView.BaseSeqNum)
+	s.view = newView
+	s.ackedViewID = newView.ID
+	s.viewConfirmed = true
+	if newView.ID > s.maxSeenViewID {
+		s.maxSeenViewID = newView.ID
+	}
+
+	// Sequence messages after the base weren't agreed on by the new view,
+	// and requests of nodes that left won't be retransmitted.
+	for key, bufferedSeqMsg := range s.outOfOrderBufferedSequenceMsgs {
+		if bufferedSeqMsg.GlobalSeqNum > newView.BaseSeqNum && bufferedSeqMsg.ViewID != newView.ID {
+			delete(s.outOfOrderBufferedSequenceMsgs, key)
+		}
+	}
+	for key, trackedMsg := range s.retransmitTracker {
+		if (trackedMsg.MsgType == MsgType_Sequence && trackedMsg.GlobalSeqNum > newView.BaseSeqNum) ||
+			(trackedMsg.MsgType == MsgType_Request && !newView.hasMember(trackedMsg.RequestNodeName)) {
+			delete(s.retransmitTracker, key)
+		}
+	}
+	if s.globalCounter < newView.BaseSeqNum {
+		log.Infof("handleViewInstall(%s): Fetching sequence msgs %d to %d.\n", s.nodeName, s.globalCounter+1, newView.BaseSeqNum)
+		s.sendSequenceRetransmitToPeers(ctx, &message{}, s.globalCounter+1, newView.BaseSeqNum)
+	}
+}
@@ -0,0 +1,165 @@
+package totem
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Retransmission in the group.
+//
+// A node that finds a gap in the messages it received asks for the missing
+// ones right away, and asks again on every tick that their retry is due.
+// Senders also resend on their own: a node rebroadcasts its requests until
+// it delivers them, and a sequencer its sequence message until it delivers
+// it, so that losing the last message of a burst, which no later message
+// reveals, doesn't stall the group. Retries back off exponentially, from
+// retransmitInitialBackoff up to retransmitMaxBackoff.
+//
+// A node asked for a message it no longer keeps answers with a NACK, an ACK
+// of type ACKType_Negavite, instead of staying silent. For a sequence
+// message, the NACK carries the node's log base, and the asker fetches a
+// snapshot. For a request, the request was delivered, and is stable, or is
+// from an earlier incarnation of the node; either way the asker stops
+// waiting for it.
+
+const (
+	// retransmitInitialBackoff is how long a node waits before sending a
+	// message, or asking for one, again.
+	retransmitInitialBackoff = time.Second
+
+	// retransmitMaxBackoff bounds the wait between two retries.
+	retransmitMaxBackoff = 16 * time.Second
+)
+
+// backoff schedules the retries of a message.
+type backoff struct {
+	next  time.Time
+	delay time.Duration
+}
+
+func newBackoff(now time.Time) backoff {
+	return backoff{next: now.Add(retransmitInitialBackoff), delay: retransmitInitialBackoff}
+}
+
+// due reports whether a retry is due at now and, if so, schedules the next
+// one twice as far out.
+func (b *backoff) due(now time.Time) bool {
+	if now.Before(b.next) {
+		return false
+	}
+	b.delay *= 2
+	if b.delay > retransmitMaxBackoff {
+		b.delay = retransmitMaxBackoff
+	}
+	b.next = now.Add(b.delay)
+	return true
+}
+
+// retransmitDue asks again for the missing messages, and resends the
+// undelivered requests and sequence messages of this node, whose retry is
+// due.
+func (s *sequencer) retransmitDue(ctx context.Context, now time.Time) {
+	if s.transferring {
+		return
+	}
+	for key, trackedMsg := range s.retransmitTracker {
+		if trackedMsg.MsgType == MsgType_Sequence && trackedMsg.GlobalSeqNum <= s.globalCounter {
+			delete(s.retransmitTracker, key)
+			continue
+		}
+		timer, ok := s.retransmitTimers[key]
+		if !ok {
+			s.retransmitTimers[key] = newBackoff(now)
+			continue
+		}
+		if !timer.due(now) {
+			continue
+		}
+		s.retransmitTimers[key] = timer
+		retransmitMsg := trackedMsg
+		retransmitMsg.MsgType = MsgType_Retransmit
+		retransmitMsg.RetransmitNodeName = s.nodeName
+		if trackedMsg.MsgType == MsgType_Sequence {
+			log.Infof("retransmitDue(%s): Asking again for Sequence msg: %d\n", s.nodeName, trackedMsg.GlobalSeqNum)
+			s.broadcastMsgToPeers(ctx, &retransmitMsg, "")
+		} else {
+			log.Infof("retransmitDue(%s): Asking again for Request msg: %s\n", s.nodeName, getRequestMsgKey(ctx, trackedMsg.RequestNodeName, trackedMsg.LocalSeqNum))
+			s.sendMsgToNode(ctx, trackedMsg.RequestNodeName, &retransmitMsg)
+		}
+	}
+	for key := range s.retransmitTimers {
+		if _, ok := s.retransmitTracker[key]; !ok {
+			delete(s.retransmitTimers, key)
+		}
+	}
+
+	resending := map[string]bool{}
+	for _, sentReqMsg := range s.sentRequestMsgs {
+		if s.isDelivered(&sentReqMsg) {
+			continue
+		}
+		s.resendDue(ctx, now, &sentReqMsg, resending)
+	}
+	for _, sentSeqMsg := range s.sentSequenceMsgs {
+		if sentSeqMsg.GlobalSeqNum <= s.globalCounter || sentSeqMsg.ViewID != s.view.ID {
+			continue
+		}
+		s.resendDue(ctx, now, &sentSeqMsg, resending)
+	}
+	for key := range s.resendTimers {
+		if !resending[key] {
+			delete(s.resendTimers, key)
+		}
+	}
+}
+
+// resendDue rebroadcasts a message this node sent, if its retry is due.
+func (s *sequencer) resendDue(ctx context.Context, now time.Time, msg *message, resending map[string]bool) {
+	key := getRetransmitMsgKey(ctx, msg)
+	resending[key] = true
+	timer, ok := s.resendTimers[key]
+	if !ok {
+		s.resendTimers[key] = newBackoff(now)
+		return
+	}
+	if !timer.due(now) {
+		return
+	}
+	s.resendTimers[key] = timer
+	log.Infof("resendDue(%s): Resending %s msg: %s\n", s.nodeName, msgTypeToStr[msg.MsgType], key)
+	if msg.MsgType == MsgType_Request {
+		s.broadcastMsgToPeers(ctx, msg, s.sequencerFor(s.globalCounter+1))
+	} else {
+		s.broadcastMsgToPeers(ctx, msg, "")
+	}
+}
+
+// sendNack tells the node asking for msg that this node no longer keeps it.
+func (s *sequencer) sendNack(ctx context.Context, msg *message) {
+	nack := &message{
+		MsgType:         MsgType_ACK,
+		ACKType:         ACKType_Negavite,
+		SenderNodeName:  s.nodeName,
+		RequestNodeName: msg.RequestNodeName,
+		LocalSeqNum:     msg.LocalSeqNum,
+		GlobalSeqNum:    msg.GlobalSeqNum,
+		Incarnation:     msg.Incarnation,
+		LogBaseSeqNum:   s.logBaseSeqNum,
+	}
+	s.sendMsgToNode(ctx, msg.RetransmitNodeName, nack)
+}
+
+// handleNack stops asking for a message its sender no longer keeps.
+func (s *sequencer) handleNack(ctx context.Context, msg *message) {
+	if msg.GlobalSeqNum != -1 {
+		log.Infof("handleNack(%s): %s no longer keeps Sequence msg: %d\n", s.nodeName, msg.SenderNodeName, msg.GlobalSeqNum)
+		if msg.LogBaseSeqNum > s.globalCounter {
+			s.startStateTransfer(ctx, msg.SenderNodeName)
+		}
+		return
+	}
+	log.Infof("handleNack(%s): %s no longer keeps Request msg: %s\n", s.nodeName, msg.SenderNodeName, getRequestMsgKey(ctx, msg.RequestNodeName, msg.LocalSeqNum))
+	s.removeMsgFromRetransmitTracker(ctx, &message{MsgType: MsgType_Request, RequestNodeName: msg.RequestNodeName, LocalSeqNum: msg.LocalSeqNum})
+}
@@ -0,0 +1,143 @@
+package totem
+
+import (
+	"context"
+	"time"
+
+	log "github.com/sirupsen/logrus"
+)
+
+// Stability of the group.
+//
+// Every node periodically acks to its peers the number of sequence messages
+// it has delivered. A message every member of the current view has delivered
+// is stable: no member will ask for it, or for its request, again. Stable
+// messages are dropped from deliveredLog, sentSequenceMsgs, sentRequestMsgs
+// and deliveredSequenceMsgs, and the log base moves up to the last of them.
+// A node outside the view that still misses some of them sees the log base
+// in heartbeats and catches up through a state transfer. Copies of stable
+// requests that arrive late are caught by deliveredRequests instead of
+// deliveredSequenceMsgs.
+//
+// The durable log is compacted to the stable messages once it holds
+// deliveryLogCompactThreshold of them. Its base then records the last request
+// of each node delivered up to there, from which a restarted node rebuilds
+// deliveredRequests along with the messages after it.
+
+const (
+	// stabilityAckInterval is how often a node acks what it has delivered.
+	stabilityAckInterval = 2 * time.Second
+)
+
+// stabilityAck is the number of messages an incarnation of a node has
+// delivered.
+type stabilityAck struct {
+	Incarnation  int64
+	GlobalSeqNum int32
+}
+
+// sendStabilityAck acks the messages delivered to the peers, at most once per
+// stabilityAckInterval.
+func (s *sequencer) sendStabilityAck(ctx context.Context, now time.Time) {
+	if now.Sub(s.lastStabilityAckAt) < stabilityAckInterval {
+		return
+	}
+	s.lastStabilityAckAt = now
+	ack := &message{
+		MsgType:        MsgType_ACK,
+		ACKType:        ACKType_Positive,
+		SenderNodeName: s.nodeName,
+		ViewID:         s.view.ID,
+		LocalSeqNum:    -1,
+		GlobalSeqNum:   s.globalCounter,
+		Incarnation:    s.incarnation,
+	}
+	for _, peerNodeName := range s.peerNodeNames {
+		if peerNodeName != s.nodeName {
+			s.sendMsgToNode(ctx, peerNodeName, ack)
+		}
+	}
+}
+
+// handleStabilityAck records the messages a peer has delivered. A restarted
+// peer's acks replace those of its earlier incarnation, even if lower.
+func (s *sequencer) handleStabilityAck(ctx context.Context, msg *message) {
+	if msg.ACKType != ACKType_Positive {
+		return
+	}
+	ack, ok := s.stabilityAcks[msg.SenderNodeName]
+	if ok && (msg.Incarnation < ack.Incarnation || (msg.Incarnation == ack.Incarnation && msg.GlobalSeqNum <= ack.GlobalSeqNum)) {
+		return
+	}
+	s.stabilityAcks[msg.SenderNodeName] = stabilityAck{Incarnation: msg.Incarnation, GlobalSeqNum: msg.GlobalSeqNum}
+}
+
+// stableSeqNum returns the last sequence number every member of the view has
+// delivered. Until every member has acked, nothing more is stable.
+func (s *sequencer) stableSeqNum() int32 {
+	stable := s.globalCounter
+	for _, member := range s.view.Members {
+		if member == s.nodeName {
+			continue
+		}
+		ack, ok := s.stabilityAcks[member]
+		if !ok {
+			return s.logBaseSeqNum
+		}
+		if ack.GlobalSeqNum < stable {
+			stable = ack.GlobalSeqNum
+		}
+	}
+	return stable
+}
+
+// collectStableMsgs drops the messages that became stable.
+func (s *sequencer) collectStableMsgs(ctx context.Context) {
+	if s.transferring {
+		return
+	}
+	stable := s.stableSeqNum()
+	if stable <= s.logBaseSeqNum {
+		return
+	}
+	for globalSeqNum := s.logBaseSeqNum + 1; globalSeqNum <= stable; globalSeqNum++ {
+		deliveredMsg, ok := s.deliveredLog[globalSeqNum]
+		if !ok {
+			continue
+		}
+		advanceRequestWatermark(s.logBaseRequests, &deliveredMsg)
+		delete(s.deliveredSequenceMsgs, deliveredMsg.ID)
+		if deliveredMsg.RequestNodeName == s.nodeName && deliveredMsg.Incarnation == s.incarnation {
+			delete(s.sentRequestMsgs, getRequestMsgKey(ctx, s.nodeName, deliveredMsg.LocalSeqNum))
+		}
+		delete(s.deliveredLog, globalSeqNum)
+	}
+	// Sequence messages sent for a view that didn't deliver them are dropped
+	// along with the ones that were.
+	for key, sentSeqMsg := range s.sentSequenceMsgs {
+		if sentSeqMsg.GlobalSeqNum <= stable {
+			delete(s.sentSequenceMsgs, key)
+		}
+	}
+	log.Infof("collectStableMsgs(%s): Sequence msgs up to %d are stable. Dropped those after %d.\n", s.nodeName, stable, s.logBaseSeqNum)
+	s.logBaseSeqNum = stable
+	s.compactDurableLog(ctx)
+}
+
+// compactDurableLog rewrites the durable log from the log base, once it holds
+// deliveryLogCompactThreshold messages before it. Every message up to the
+// base was applied: delivery waits for apply to return.
+func (s *sequencer) compactDurableLog(ctx context.Context) {
+	if s.durableLog == nil || s.logBaseSeqNum-s.durableLog.base < deliveryLogCompactThreshold {
+		return
+	}
+	var tail []message
+	for globalSeqNum := s.logBaseSeqNum + 1; globalSeqNum <= s.globalCounter; globalSeqNum++ {
+		tail = append(tail, s.deliveredLog[globalSeqNum])
+	}
+	base := SnapshotPoint{GlobalSeqNum: s.logBaseSeqNum, DeliveredRequests: s.logBaseRequests}
+	if err := s.durableLog.compact(ctx, base, tail); err != nil {
+		log.Fatalf("compactDurableLog(%s): Exception while compacting delivery log to %d. %v\n", s.nodeName, s.logBaseSeqNum, err)
+	}
+	log.Infof("compactDurableLog(%s): Compacted delivery log to the %d msgs after %d\n", s.nodeName, len(tail), s.logBaseSeqNum)
+}
@@ -0,0 +1,189 @@
+package totem
+
+import (
+	"context"
+	"fmt"
+
+	libProto "github.com/adarshsrinivasan/DS_S24/library/proto"
+	"github.com/adarshsrinivasan/DS_S24/library/replication"
+	log "github.com/sirupsen/logrus"
+)
+
+// State transfer of the group.
+//
+// Heartbeats carry the sender's log base: the sequence messages up to it are
+// no longer in its deliveredLog, so it can't retransmit them. A node that is
+// missing any of those can't catch up through retransmits. Instead it stops
+// delivering, has the service replace its database with a snapshot from the
+// peer (see SnapshotFetcher), and carries on from the sequence number of the
+// snapshot. Sequence messages that arrive meanwhile are buffered and
+// delivered afterwards, and the rest are fetched through the usual catch-up.
+// Requests submitted meanwhile are held back until then, so that they're
+// sequenced after the snapshot and this node applies them itself. The
+// snapshot also says which request of each node it ends with, so requests it
+// covers aren't sequenced again.
+
+// SnapshotPoint is the position in the total order a snapshot was taken at.
+type SnapshotPoint struct {
+	GlobalSeqNum      int32
+	DeliveredRequests map[string]RequestWatermark
+}
+
+// RequestWatermark is the last request delivered from a node.
+type RequestWatermark struct {
+	Incarnation int64 `json:"incarnation"`
+	LocalSeqNum int32 `json:"localSeqNum"`
+}
+
+func ConvertRequestWatermarksToProto(deliveredRequests map[string]RequestWatermark) []*libProto.RequestWatermark {
+	var watermarks []*libProto.RequestWatermark
+	for nodeName, delivered := range deliveredRequests {
+		watermarks = append(watermarks, &libProto.RequestWatermark{
+			NodeName:    nodeName,
+			Incarnation: delivered.Incarnation,
+			LocalSeqNum: delivered.LocalSeqNum,
+		})
+	}
+	return watermarks
+}
+
+func ConvertProtoRequestWatermarks(watermarks []*libProto.RequestWatermark) map[string]RequestWatermark {
+	deliveredRequests := map[string]RequestWatermark{}
+	for _, watermark := range watermarks {
+		deliveredRequests[watermark.NodeName] = RequestWatermark{
+			Incarnation: watermark.Incarnation,
+			LocalSeqNum: watermark.LocalSeqNum,
+		}
+	}
+	return deliveredRequests
+}
+
+// SnapshotFetcher replaces the local database with a snapshot from
+// peerNodeName covering at least minGlobalSeqNum messages, and returns where
+// it was taken.
+type SnapshotFetcher func(ctx context.Context, peerNodeName string, minGlobalSeqNum int32) (SnapshotPoint, error)
+
+// startStateTransfer fetches a snapshot from peerNodeName in the background,
+// unless a transfer is already running.
+func (s *sequencer) startStateTransfer(ctx context.Context, peerNodeName string) {
+	if s.transferring || s.fetchSnapshot == nil {
+		return
+	}
+	log.Infof("startStateTransfer(%s): %s no longer keeps sequence msg %d. Fetching a snapshot from it.\n", s.nodeName, peerNodeName, s.globalCounter+1)
+	s.transferring = true
+	minGlobalSeqNum := s.globalCounter + 1
+	go func() {
+		snapshot, err := s.fetchSnapshot(ctx, peerNodeName, minGlobalSeqNum)
+		call := func(ctx context.Context) {
+			s.finishStateTransfer(ctx, peerNodeName, snapshot, err)
+		}
+		select {
+		case s.calls <- call:
+		case <-s.stopped:
+		}
+	}()
+}
+
+// finishStateTransfer resumes delivery after the snapshot was installed. On
+// failure the transfer is retried on a later heartbeat.
+func (s *sequencer) finishStateTransfer(ctx context.Context, peerNodeName string, snapshot SnapshotPoint, err error) {
+	s.transferring = false
+	defer s.broadcastHeldRequestMsgs(ctx)
+	if err != nil {
+		log.Errorf("finishStateTransfer(%s): Exception while fetching a snapshot from %s. %v\n", s.nodeName, peerNodeName, err)
+		return
+	}
+	globalSeqNum := snapshot.GlobalSeqNum
+	log.Infof("finishStateTransfer(%s): Installed snapshot of %s at sequence msg %d. globalCounter was: %d\n", s.nodeName, peerNodeName, globalSeqNum, s.globalCounter)
+	if s.durableLog != nil {
+		if err := s.durableLog.reset(ctx, snapshot); err != nil {
+			log.Fatalf("finishStateTransfer(%s): Exception while resetting delivery log. %v\n", s.nodeName, err)
+		}
+	}
+	s.globalCounter = globalSeqNum
+	s.logBaseSeqNum = globalSeqNum
+	s.deliveredLog = map[int32]message{}
+	s.deliveredSequenceMsgs = map[string]bool{}
+	s.deliveredRequests = map[string]RequestWatermark{}
+	s.logBaseRequests = map[string]RequestWatermark{}
+	for nodeName, delivered := range snapshot.DeliveredRequests {
+		s.deliveredRequests[nodeName] = delivered
+		s.logBaseRequests[nodeName] = delivered
+	}
+
+	// Requests are buffered again from the first one after the snapshot, so
+	// that this node sequences them in order.
+	s.toBeDeliveredBufferedRequestMsgs = make([]message, 0)
+	s.resumeRequestBuffering(ctx)
+	for key, bufferedSeqMsg := range s.outOfOrderBufferedSequenceMsgs {
+		if bufferedSeqMsg.GlobalSeqNum <= globalSeqNum {
+			delete(s.outOfOrderBufferedSequenceMsgs, key)
+		}
+	}
+	for key, trackedMsg := range s.retransmitTracker {
+		if trackedMsg.MsgType == MsgType_Sequence && trackedMsg.GlobalSeqNum <= globalSeqNum {
+			delete(s.retransmitTracker, key)
+		}
+	}
+	s.deliverBufferedSequenceMsgs(ctx)
+
+	// Requests submitted before the transfer may have been applied in the
+	// snapshot, so their submitters can't be told how they went.
+	held := map[string]bool{}
+	for _, heldReqMsg := range s.heldRequestMsgs {
+		held[heldReqMsg.ID] = true
+	}
+	for requestID, responseChan := range s.responseTrackers {
+		if !held[requestID] {
+			responseChan <- replication.Result{Err: fmt.Errorf("outcome of request %s unknown: %s installed a snapshot while it was pending", requestID, s.nodeName)}
+			delete(s.responseTrackers, requestID)
+		}
+	}
+}
+
+// broadcastHeldRequestMsgs broadcasts the requests submitted during a state
+// transfer.
+func (s *sequencer) broadcastHeldRequestMsgs(ctx context.Context) {
+	for i := range s.heldRequestMsgs {
+		s.recordRequestSentMsg(ctx, &s.heldRequestMsgs[i])
+		s.broadcastMsgToPeers(ctx, &s.heldRequestMsgs[i], s.sequencerFor(s.globalCounter+1))
+	}
+	s.heldRequestMsgs = nil
+}
+
+// atDeliveredSeqNum runs fn on the event loop, between two deliveries, and
+// returns the position in the total order it ran at. It fails while this
+// node is installing a snapshot itself.
+func (s *sequencer) atDeliveredSeqNum(ctx context.Context, fn func() error) (SnapshotPoint, error) {
+	type result struct {
+		snapshot SnapshotPoint
+		err      error
+	}
+	done := make(chan result, 1)
+	call := func(ctx context.Context) {
+		if s.transferring {
+			done <- result{err: fmt.Errorf("%s is installing a snapshot", s.nodeName)}
+			return
+		}
+		snapshot := SnapshotPoint{GlobalSeqNum: s.globalCounter, DeliveredRequests: map[string]RequestWatermark{}}
+		for nodeName, delivered := range s.deliveredRequests {
+			snapshot.DeliveredRequests[nodeName] = delivered
+		}
+		done <- result{snapshot: snapshot, err: fn()}
+	}
+	select {
+	case s.calls <- call:
+	case <-s.stopped:
+		return SnapshotPoint{}, fmt.Errorf("sequencer of %s stopped", s.nodeName)
+	case <-ctx.Done():
+		return SnapshotPoint{}, ctx.Err()
+	}
+	select {
+	case r := <-done:
+		return r.snapshot, r.err
+	case <-s.stopped:
+		return SnapshotPoint{}, fmt.Errorf("sequencer of %s stopped", s.nodeName)
+	case <-ctx.Done():
+		return SnapshotPoint{}, ctx.Err()
+	}
+}
@@ -0,0 +1,702 @@
+// Package totem is a replication engine built on a rotating-sequencer atomic
+// broadcast, in the style of Totem: every node broadcasts its writes, the
+// nodes of the current view take turns assigning them global sequence
+// numbers, and every node applies them in that order. See Engine.
+package totem
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"github.com/adarshsrinivasan/DS_S24/library/common"
+	"github.com/adarshsrinivasan/DS_S24/library/replication"
+	log "github.com/sirupsen/logrus"
+)
+
+type msgType int
+
+const (
+	MsgType_None msgType = iota
+	MsgType_Request
+	MsgType_Sequence
+	MsgType_Retransmit
+	MsgType_ACK
+	MsgType_Heartbeat
+	MsgType_ViewProposal
+	MsgType_ViewAck
+	MsgType_ViewInstall
+)
+
+var msgTypeToStr = map[msgType]string{
+	MsgType_None:         "None",
+	MsgType_Request:      "Request",
+	MsgType_Sequence:     "Sequence",
+	MsgType_Retransmit:   "Retransmit",
+	MsgType_ACK:          "ACK",
+	MsgType_Heartbeat:    "Heartbeat",
+	MsgType_ViewProposal: "ViewProposal",
+	MsgType_ViewAck:      "ViewAck",
+	MsgType_ViewInstall:  "ViewInstall",
+}
+
+type ackType int
+
+const (
+	ACKType_None ackType = iota
+	ACKType_Positive
+	ACKType_Negavite
+)
+
+type message struct {
+	ID                 string         `json:"id"`
+	MsgType            msgType        `json:"msgType"`
+	OpsType            replication.Op `json:"opsType"`
+	Payload            []byte         `json:"payload"`
+	RequestNodeName    string         `json:"requestNodeName"`
+	SequenceNodeName   string         `json:"sequenceNodeName"`
+	RetransmitNodeName string         `json:"retransmitNodeName"`
+	LocalSeqNum        int32          `json:"localSeqNum"`
+	GlobalSeqNum       int32          `json:"globalSeqNum"`
+	ACKType            ackType        `json:"ackType"`
+	ViewID             int32          `json:"viewID"`
+	Members            []string       `json:"members"`
+	SenderNodeName     string         `json:"senderNodeName"`
+	Incarnation        int64          `json:"incarnation"`
+	LogBaseSeqNum      int32          `json:"logBaseSeqNum"`
+}
+
+func (m message) toString() string {
+	data, _ := json.Marshal(m)
+	return string(data)
+}
+
+const (
+	// sequencerQueueSize is how many received messages and submitted
+	// requests can wait for the event loop.
+	sequencerQueueSize = 1024
+)
+
+// transport carries messages between the nodes of the group. Delivery is
+// best effort: lost messages are recovered through retransmits.
+type transport interface {
+	send(ctx context.Context, receiverNodeName string, msg *message) error
+}
+
+// sequencer totally orders the write requests of the group. Every node
+// broadcasts its requests, and the node whose turn it is assigns the next
+// global sequence number to the oldest request it has buffered. All of its
+// state is owned by the goroutine running run; other goroutines reach it
+// through submit and receive.
+type sequencer struct {
+	nodeName      string
+	peerNodeNames []string
+	transport     transport
+
+	// deliver executes a request sequenced by the group. Its result is
+	// handed to the submitter when the request came from this node.
+	deliver func(ctx context.Context, msg *message) replication.Result
+
+	// durableLog records the delivered messages on disk; nil keeps them in
+	// memory only.
+	durableLog *deliveryLog
+
+	incoming chan *message
+	calls    chan func(ctx context.Context)
+	stopped  chan struct{}
+
+	// The fields below are only touched by the event loop.
+	sentRequestMsgs                  map[string]message
+	sentSequenceMsgs                 map[string]message
+	deliveredSequenceMsgs            map[string]bool
+	toBeDeliveredBufferedRequestMsgs []message
+	outOfOrderBufferedRequestMsgs    map[string]message
+	outOfOrderBufferedSequenceMsgs   map[string]message
+	retransmitTracker                map[string]message
+	lastLocalSeqBuffered             map[string]int32
+	responseTrackers                 map[string]chan replication.Result
+	localCounter, globalCounter      int32
+
+	// incarnation tells this run of the node apart from earlier ones, whose
+	// local sequence numbers it reuses.
+	incarnation  int64
+	incarnations map[string]int64
+
+	// deliveredRequests holds the last request of each node delivered.
+	// Requests are sequenced in the order their node sent them, so later
+	// copies of any request up to it are dropped.
+	deliveredRequests map[string]RequestWatermark
+
+	// deliveredLog keeps the delivered sequence messages, so that any node
+	// can answer retransmits for them. It starts after logBaseSeqNum: the
+	// messages up to it came in a snapshot or are stable.
+	deliveredLog  map[int32]message
+	logBaseSeqNum int32
+
+	// logBaseRequests holds the last request of each node delivered up to
+	// logBaseSeqNum, the base the durable log is compacted to.
+	logBaseRequests map[string]RequestWatermark
+
+	// Retransmission; see retransmit.go.
+	retransmitTimers map[string]backoff
+	resendTimers     map[string]backoff
+
+	// Stability; see stability.go.
+	stabilityAcks      map[string]stabilityAck
+	lastStabilityAckAt time.Time
+
+	// State transfer; see state-transfer.go.
+	fetchSnapshot   SnapshotFetcher
+	transferring    bool
+	heldRequestMsgs []message
+
+	// Membership; see membership.go.
+	view          view
+	ackedViewID   int32
+	ackedAt       time.Time
+	maxSeenViewID int32
+	proposal      *viewProposal
+	lastHeard     map[string]time.Time
+	viewConfirmed bool
+}
+
+func newSequencer(nodeName string, peerNodeNames []string, transport transport, durableLog *deliveryLog, fetchSnapshot SnapshotFetcher, deliver func(ctx context.Context, msg *message) replication.Result) *sequencer {
+	s := &sequencer{
+		nodeName:                         nodeName,
+		peerNodeNames:                    peerNodeNames,
+		transport:                        transport,
+		deliver:                          deliver,
+		durableLog:                       durableLog,
+		fetchSnapshot:                    fetchSnapshot,
+		incoming:                         make(chan *message, sequencerQueueSize),
+		calls:                            make(chan func(ctx context.Context), sequencerQueueSize),
+		stopped:                          make(chan struct{}),
+		sentRequestMsgs:                  map[string]message{},
+		sentSequenceMsgs:                 map[string]message{},
+		deliveredSequenceMsgs:            map[string]bool{},
+		toBeDeliveredBufferedRequestMsgs: make([]message, 0),
+		outOfOrderBufferedRequestMsgs:    map[string]message{},
+		outOfOrderBufferedSequenceMsgs:   map[string]message{},
+		retransmitTracker:                map[string]message{},
+		lastLocalSeqBuffered:             map[string]int32{},
+		responseTrackers:                 map[string]chan replication.Result{},
+		incarnation:                      time.Now().UnixNano(),
+		incarnations:                     map[string]int64{},
+		deliveredRequests:                map[string]RequestWatermark{},
+		deliveredLog:                     map[int32]message{},
+		logBaseRequests:                  map[string]RequestWatermark{},
+		retransmitTimers:                 map[string]backoff{},
+		resendTimers:                     map[string]backoff{},
+		stabilityAcks:                    map[string]stabilityAck{},
+		view:                             view{Members: peerNodeNames},
+		lastHeard:                        map[string]time.Time{},
+		viewConfirmed:                    len(peerNodeNames) == 1,
+	}
+	// Peers get failureTimeout to be heard from before they're suspected.
+	for _, peerNodeName := range peerNodeNames {
+		s.lastHeard[peerNodeName] = time.Now()
+	}
+	return s
+}
+
+// run is the event loop of the sequencer. It returns once ctx is done.
+func (s *sequencer) run(ctx context.Context) {
+	defer close(s.stopped)
+	ticker := time.NewTicker(heartbeatInterval)
+	defer ticker.Stop()
+	for {
+		select {
+		case msg := <-s.incoming:
+			s.handleReceivedMsg(ctx, msg)
+		case call := <-s.calls:
+			call(ctx)
+		case now := <-ticker.C:
+			s.tick(ctx, now)
+		case <-ctx.Done():
+			return
+		}
+		// Receiving a message may make it this node's turn to sequence, or
+		// answer the retransmits that held it back.
+		s.checkTurnAndSendSequenceToPeers(ctx)
+	}
+}
+
+// receive hands a message from a peer to the event loop.
+func (s *sequencer) receive(msg *message) {
+	select {
+	case s.incoming <- msg:
+	case <-s.stopped:
+	}
+}
+
+// submit broadcasts a request to the group. The returned channel receives the
+// result of the request once this node has delivered it. A request submitted
+// after the sequencer stopped is never answered.
+func (s *sequencer) submit(opsType replication.Op, payload []byte) (string, <-chan replication.Result) {
+	requestID := common.GenerateUUID()
+	responseChan := make(chan replication.Result, 1)
+	call := func(ctx context.Context) {
+		s.localCounter++
+		requestMsg := &message{
+			ID:              requestID,
+			MsgType:         MsgType_Request,
+			OpsType:         opsType,
+			Payload:         payload,
+			RequestNodeName: s.nodeName,
+			LocalSeqNum:     s.localCounter,
+			GlobalSeqNum:    -1,
+			Incarnation:     s.incarnation,
+		}
+		s.responseTrackers[requestID] = responseChan
+		if s.transferring {
+			s.heldRequestMsgs = append(s.heldRequestMsgs, *requestMsg)
+			return
+		}
+		s.recordRequestSentMsg(ctx, requestMsg)
+		s.broadcastMsgToPeers(ctx, requestMsg, s.sequencerFor(s.globalCounter+1))
+	}
+	select {
+	case s.calls <- call:
+	case <-s.stopped:
+	}
+	return requestID, responseChan
+}
+
+// abandon stops tracking the result of a request whose submitter gave up on
+// it.
+func (s *sequencer) abandon(requestID string) {
+	call := func(ctx context.Context) {
+		delete(s.responseTrackers, requestID)
+	}
+	select {
+	case s.calls <- call:
+	case <-s.stopped:
+	}
+}
+
+// sequencerFor returns the node whose turn it is to assign globalSeqNum in
+// the current view, or "" if an earlier view assigned it.
+func (s *sequencer) sequencerFor(globalSeqNum int32) string {
+	if globalSeqNum <= s.view.BaseSeqNum {
+		return ""
+	}
+	members := s.view.Members
+	return members[(globalSeqNum-s.view.BaseSeqNum-1)%int32(len(members))]
+}
+
+func getRequestMsgKey(ctx context.Context, requestNodeName string, localSeqNum int32) string {
+	return fmt.Sprintf("%s-%d", requestNodeName, localSeqNum)
+}
+
+func getSequenceMsgKey(ctx context.Context, globalSeqNum int32) string {
+	return fmt.Sprintf("%d", globalSeqNum)
+}
+
+// getRetransmitMsgKey identifies a message by its position in the order it's
+// tracked in, so a retransmitted copy matches the request for it.
+func getRetransmitMsgKey(ctx context.Context, msg *message) string {
+	key := ""
+	switch msg.MsgType {
+	case MsgType_Sequence:
+		key = fmt.Sprintf("%s-%d", msgTypeToStr[msg.MsgType], msg.GlobalSeqNum)
+	case MsgType_Request:
+		key = fmt.Sprintf("%s-%s-%d", msgTypeToStr[msg.MsgType], msg.RequestNodeName, msg.LocalSeqNum)
+	}
+	return key
+}
+
+func (s *sequencer) recordRequestSentMsg(ctx context.Context, msg *message) {
+	s.sentRequestMsgs[getRequestMsgKey(ctx, msg.RequestNodeName, msg.LocalSeqNum)] = *msg
+}
+
+func (s *sequencer) recordSequenceSentMsg(ctx context.Context, msg *message) {
+	s.sentSequenceMsgs[getSequenceMsgKey(ctx, msg.GlobalSeqNum)] = *msg
+}
+
+// isDelivered reports whether a request was delivered already.
+func (s *sequencer) isDelivered(msg *message) bool {
+	if _, ok := s.deliveredSequenceMsgs[msg.ID]; ok {
+		return true
+	}
+	delivered, ok := s.deliveredRequests[msg.RequestNodeName]
+	return ok && msg.Incarnation == delivered.Incarnation && msg.LocalSeqNum <= delivered.LocalSeqNum
+}
+
+func (s *sequencer) addRequestMsgToToBeDeliveredBuffer(ctx context.Context, msg *message) {
+	if s.isDelivered(msg) {
+		return
+	}
+	s.toBeDeliveredBufferedRequestMsgs = append(s.toBeDeliveredBufferedRequestMsgs, *msg)
+}
+
+func (s *sequencer) addRequestMsgToOutOfOrderBuffer(ctx context.Context, msg *message) {
+	s.outOfOrderBufferedRequestMsgs[getRequestMsgKey(ctx, msg.RequestNodeName, msg.LocalSeqNum)] = *msg
+}
+
+func (s *sequencer) addSequenceMsgToOutOfOrderBuffer(ctx context.Context, msg *message) {
+	s.outOfOrderBufferedSequenceMsgs[getSequenceMsgKey(ctx, msg.GlobalSeqNum)] = *msg
+}
+
+func (s *sequencer) addMsgToRetransmitTracker(ctx context.Context, msg *message) {
+	s.retransmitTracker[getRetransmitMsgKey(ctx, msg)] = *msg
+}
+
+func (s *sequencer) removeMsgFromRetransmitTracker(ctx context.Context, msg *message) {
+	delete(s.retransmitTracker, getRetransmitMsgKey(ctx, msg))
+}
+
+func (s *sequencer) removeRequestMsgFromToBeDeliveredBuffered(ctx context.Context, msg *message) {
+	for i := 0; i < len(s.toBeDeliveredBufferedRequestMsgs); i++ {
+		if s.toBeDeliveredBufferedRequestMsgs[i].ID == msg.ID {
+			s.toBeDeliveredBufferedRequestMsgs = append(s.toBeDeliveredBufferedRequestMsgs[:i], s.toBeDeliveredBufferedRequestMsgs[i+1:]...)
+			break
+		}
+	}
+}
+
+// broadcastMsgToPeers sends msg to every node of the group, this one
+// included, and to sendLastNodeName after all others.
+func (s *sequencer) broadcastMsgToPeers(ctx context.Context, msg *message, sendLastNodeName string) {
+	sendLast := false
+	for _, peerNodeName := range s.peerNodeNames {
+		if peerNodeName == sendLastNodeName {
+			sendLast = true
+			continue
+		}
+		log.Infof("broadcastMsgToPeers(%s): Sending the following msg to %s: %s\n", s.nodeName, peerNodeName, msg.toString())
+		s.sendMsgToNode(ctx, peerNodeName, msg)
+	}
+	if sendLast {
+		s.sendMsgToNode(ctx, sendLastNodeName, msg)
+	}
+}
+
+func (s *sequencer) sendMsgToNode(ctx context.Context, receiverNodeName string, msg *message) {
+	if err := s.transport.send(ctx, receiverNodeName, msg); err != nil {
+		log.Errorf("sendMsgToNode(%s): Exception while sending msg to %s. %v\n", s.nodeName, receiverNodeName, err)
+	}
+}
+
+func (s *sequencer) sendSequenceRetransmitToPeers(ctx context.Context, msg *message, from, to int32) {
+	if to < from {
+		return
+	}
+	retransmitMsg := *msg
+	retransmitMsg.LocalSeqNum = -1
+	retransmitMsg.RetransmitNodeName = s.nodeName
+	for i := from; i <= to; i++ {
+		retransmitMsg.MsgType = MsgType_Sequence
+		retransmitMsg.GlobalSeqNum = i
+		s.addMsgToRetransmitTracker(ctx, &retransmitMsg)
+		retransmitMsg.MsgType = MsgType_Retransmit
+		s.broadcastMsgToPeers(ctx, &retransmitMsg, "")
+	}
+}
+
+func (s *sequencer) sendSequenceRetransmitToNode(ctx context.Context, receiverNodeName string, from, to int32) {
+	retransmitMsg := message{LocalSeqNum: -1, RetransmitNodeName: s.nodeName}
+	for i := from; i <= to; i++ {
+		retransmitMsg.MsgType = MsgType_Sequence
+		retransmitMsg.GlobalSeqNum = i
+		s.addMsgToRetransmitTracker(ctx, &retransmitMsg)
+		retransmitMsg.MsgType = MsgType_Retransmit
+		s.sendMsgToNode(ctx, receiverNodeName, &retransmitMsg)
+	}
+}
+
+func (s *sequencer) sendRequestRetransmitToNode(ctx context.Context, msg *message, from, to int32) {
+	if to < from {
+		return
+	}
+	retransmitMsg := *msg
+	retransmitMsg.GlobalSeqNum = -1
+	retransmitMsg.RetransmitNodeName = s.nodeName
+	for i := from; i <= to; i++ {
+		retransmitMsg.MsgType = MsgType_Request
+		retransmitMsg.LocalSeqNum = i
+		s.addMsgToRetransmitTracker(ctx, &retransmitMsg)
+		retransmitMsg.MsgType = MsgType_Retransmit
+		s.sendMsgToNode(ctx, retransmitMsg.RequestNodeName, &retransmitMsg)
+	}
+}
+
+func (s *sequencer) checkTurnAndSendSequenceToPeers(ctx context.Context) {
+	for len(s.toBeDeliveredBufferedRequestMsgs) > 0 {
+		if s.isDelivered(&s.toBeDeliveredBufferedRequestMsgs[0]) {
+			s.toBeDeliveredBufferedRequestMsgs = s.toBeDeliveredBufferedRequestMsgs[1:]
+		} else {
+			break
+		}
+	}
+	if len(s.toBeDeliveredBufferedRequestMsgs) == 0 || !s.canSequence(time.Now()) {
+		return
+	}
+	nextGlobalSeqNum := s.globalCounter + 1
+	if nextSequencer := s.sequencerFor(nextGlobalSeqNum); nextSequencer != s.nodeName {
+		log.Infof("checkTurnAndSendSequenceToPeers(%s): Not my responsibility to send next sequence message. Responsibility of: %s", s.nodeName, nextSequencer)
+		return
+	}
+	if sentSeqMsg, ok := s.sentSequenceMsgs[getSequenceMsgKey(ctx, nextGlobalSeqNum)]; ok && sentSeqMsg.ViewID == s.view.ID {
+		// Already sent; waiting for it to be delivered.
+		return
+	}
+	if len(s.retransmitTracker) > 0 {
+		log.Infof("checkTurnAndSendSequenceToPeers(%s): Cannot deliver since I have %d retransmit requests pending. Rechecking once they are answered.\n", s.nodeName, len(s.retransmitTracker))
+		return
+	}
+
+	log.Infof("checkTurnAndSendSequenceToPeers(%s): Taking responsibility to send next sequence message.\n", s.nodeName)
+	nextMsg := s.toBeDeliveredBufferedRequestMsgs[0]
+	nextMsg.MsgType = MsgType_Sequence
+	nextMsg.SequenceNodeName = s.nodeName
+	nextMsg.GlobalSeqNum = nextGlobalSeqNum
+	nextMsg.ViewID = s.view.ID
+	s.recordSequenceSentMsg(ctx, &nextMsg)
+	s.broadcastMsgToPeers(ctx, &nextMsg, "")
+}
+
+func (s *sequencer) handleReceivedMsg(ctx context.Context, msg *message) {
+	s.removeMsgFromRetransmitTracker(ctx, msg)
+	switch msg.MsgType {
+	case MsgType_Sequence:
+		{
+			if !s.acceptsSequenceMsg(msg) {
+				log.Infof("handleReceivedMsg(%s): Dropping sequence msg %d of view %d. view: %d\n", s.nodeName, msg.GlobalSeqNum, msg.ViewID, s.view.ID)
+				return
+			}
+			if s.transferring {
+				// Delivered once the snapshot is installed, if it's after it.
+				if msg.GlobalSeqNum > s.globalCounter {
+					s.addSequenceMsgToOutOfOrderBuffer(ctx, msg)
+				}
+				return
+			}
+			if (s.globalCounter + 1) == msg.GlobalSeqNum {
+				s.deliverSequenceMsg(ctx, msg)
+				s.deliverBufferedSequenceMsgs(ctx)
+			} else if (s.globalCounter + 1) < msg.GlobalSeqNum {
+				s.addSequenceMsgToOutOfOrderBuffer(ctx, msg)
+				s.sendSequenceRetransmitToPeers(ctx, msg, (s.globalCounter + 1), (msg.GlobalSeqNum - 1))
+			} else {
+				log.Infof("handleReceivedMsg(%s): Received old sequence msg: %d. globalCounter: %d\n", s.nodeName, msg.GlobalSeqNum, s.globalCounter)
+			}
+		}
+	case MsgType_Request:
+		{
+			if !s.checkIncarnation(ctx, msg) {
+				log.Infof("handleReceivedMsg(%s): Dropping request msg %s of an earlier incarnation of %s\n", s.nodeName, msg.ID, msg.RequestNodeName)
+				return
+			}
+			if (s.lastLocalSeqBuffered[msg.RequestNodeName] + 1) == msg.LocalSeqNum {
+				s.addRequestMsgToToBeDeliveredBuffer(ctx, msg)
+				s.lastLocalSeqBuffered[msg.RequestNodeName]++
+				s.bufferOutOfOrderRequestMsgs(ctx, msg.RequestNodeName)
+			} else if (s.lastLocalSeqBuffered[msg.RequestNodeName] + 1) < msg.LocalSeqNum {
+				s.addRequestMsgToOutOfOrderBuffer(ctx, msg)
+				s.sendRequestRetransmitToNode(ctx, msg, (s.lastLocalSeqBuffered[msg.RequestNodeName] + 1), (msg.LocalSeqNum - 1))
+			} else {
+				log.Infof("handleReceivedMsg(%s): Received old request msg: %s. globalCounter: %d\n", s.nodeName, getRequestMsgKey(ctx, msg.RequestNodeName, msg.LocalSeqNum), s.globalCounter)
+			}
+		}
+	case MsgType_Retransmit:
+		{
+			if msg.GlobalSeqNum != -1 {
+				// Any node that delivered the message can retransmit it.
+				if deliveredMsg, ok := s.deliveredLog[msg.GlobalSeqNum]; ok {
+					log.Infof("handleReceivedMsg(%s): Retransmitting Sequence msg: %d\n", s.nodeName, msg.GlobalSeqNum)
+					s.sendMsgToNode(ctx, msg.RetransmitNodeName, &deliveredMsg)
+				} else if sentSeqMsg, ok := s.sentSequenceMsgs[getSequenceMsgKey(ctx, msg.GlobalSeqNum)]; ok && sentSeqMsg.ViewID == s.view.ID {
+					log.Infof("handleReceivedMsg(%s): Retransmitting Sequence msg: %d\n", s.nodeName, msg.GlobalSeqNum)
+					s.sendMsgToNode(ctx, msg.RetransmitNodeName, &sentSeqMsg)
+				} else if msg.GlobalSeqNum <= s.logBaseSeqNum {
+					s.sendNack(ctx, msg)
+				}
+			} else if msg.LocalSeqNum != -1 && msg.RequestNodeName == s.nodeName {
+				if sentReqMsg, ok := s.sentRequestMsgs[getRequestMsgKey(ctx, msg.RequestNodeName, msg.LocalSeqNum)]; ok {
+					log.Infof("handleReceivedMsg(%s): Retransmitting Request msg: %s-%d\n", s.nodeName, s.nodeName, msg.LocalSeqNum)
+					s.sendMsgToNode(ctx, msg.RetransmitNodeName, &sentReqMsg)
+				} else {
+					s.sendNack(ctx, msg)
+				}
+			}
+		}
+	case MsgType_ACK:
+		if msg.ACKType == ACKType_Negavite {
+			s.handleNack(ctx, msg)
+		} else {
+			s.handleStabilityAck(ctx, msg)
+		}
+	case MsgType_Heartbeat:
+		s.handleHeartbeat(ctx, msg, time.Now())
+	case MsgType_ViewProposal:
+		s.handleViewProposal(ctx, msg, time.Now())
+	case MsgType_ViewAck:
+		s.handleViewAck(ctx, msg)
+	case MsgType_ViewInstall:
+		s.handleViewInstall(ctx, msg)
+	default:
+		{
+			log.Errorf("handleReceivedMsg(%s): Invalid msg type: %d\n", s.nodeName, msg.MsgType)
+		}
+	}
+}
+
+// bufferOutOfOrderRequestMsgs moves the out of order requests of
+// requestNodeName that are now next in its order to the toBeDelivered buffer.
+func (s *sequencer) bufferOutOfOrderRequestMsgs(ctx context.Context, requestNodeName string) {
+	for {
+		key := getRequestMsgKey(ctx, requestNodeName, s.lastLocalSeqBuffered[requestNodeName]+1)
+		bufferedReqMsg, ok := s.outOfOrderBufferedRequestMsgs[key]
+		if !ok {
+			break
+		}
+		delete(s.outOfOrderBufferedRequestMsgs, key)
+		s.addRequestMsgToToBeDeliveredBuffer(ctx, &bufferedReqMsg)
+		s.lastLocalSeqBuffered[requestNodeName]++
+	}
+}
+
+// skipDeliveredRequestMsgs stops waiting for the requests of the node msg
+// came from that were delivered with it, up to msg itself. This node may
+// have missed them, and their sender may no longer keep them.
+func (s *sequencer) skipDeliveredRequestMsgs(ctx context.Context, msg *message) {
+	requestNodeName := msg.RequestNodeName
+	if !s.checkIncarnation(ctx, msg) || msg.LocalSeqNum <= s.lastLocalSeqBuffered[requestNodeName] {
+		return
+	}
+	s.lastLocalSeqBuffered[requestNodeName] = msg.LocalSeqNum
+	for key, bufferedReqMsg := range s.outOfOrderBufferedRequestMsgs {
+		if bufferedReqMsg.RequestNodeName == requestNodeName && bufferedReqMsg.LocalSeqNum <= msg.LocalSeqNum {
+			delete(s.outOfOrderBufferedRequestMsgs, key)
+		}
+	}
+	for key, trackedMsg := range s.retransmitTracker {
+		if trackedMsg.MsgType == MsgType_Request && trackedMsg.RequestNodeName == requestNodeName && trackedMsg.LocalSeqNum <= msg.LocalSeqNum {
+			delete(s.retransmitTracker, key)
+		}
+	}
+	s.bufferOutOfOrderRequestMsgs(ctx, requestNodeName)
+}
+
+// resumeRequestBuffering restarts the buffering of the requests of each node
+// from the one after its last delivered, once deliveredRequests was restored
+// or replaced by a snapshot. The requests buffered until then may no longer
+// be next in the order.
+func (s *sequencer) resumeRequestBuffering(ctx context.Context) {
+	for nodeName, delivered := range s.deliveredRequests {
+		if incarnation, ok := s.incarnations[nodeName]; !ok || incarnation < delivered.Incarnation {
+			s.incarnations[nodeName] = delivered.Incarnation
+		}
+	}
+	for nodeName, incarnation := range s.incarnations {
+		s.lastLocalSeqBuffered[nodeName] = 0
+		if delivered, ok := s.deliveredRequests[nodeName]; ok && delivered.Incarnation == incarnation {
+			s.lastLocalSeqBuffered[nodeName] = delivered.LocalSeqNum
+		}
+	}
+	for key, bufferedReqMsg := range s.outOfOrderBufferedRequestMsgs {
+		if bufferedReqMsg.Incarnation != s.incarnations[bufferedReqMsg.RequestNodeName] || s.isDelivered(&bufferedReqMsg) {
+			delete(s.outOfOrderBufferedRequestMsgs, key)
+		}
+	}
+	for key, trackedMsg := range s.retransmitTracker {
+		if trackedMsg.MsgType == MsgType_Request && (trackedMsg.Incarnation != s.incarnations[trackedMsg.RequestNodeName] || s.isDelivered(&trackedMsg)) {
+			delete(s.retransmitTracker, key)
+		}
+	}
+	for nodeName := range s.incarnations {
+		s.bufferOutOfOrderRequestMsgs(ctx, nodeName)
+	}
+}
+
+// deliverBufferedSequenceMsgs delivers the buffered sequence messages that
+// are next in the total order.
+func (s *sequencer) deliverBufferedSequenceMsgs(ctx context.Context) {
+	for {
+		bufferedSeqMsg, ok := s.outOfOrderBufferedSequenceMsgs[getSequenceMsgKey(ctx, s.globalCounter+1)]
+		if !ok {
+			break
+		}
+		delete(s.outOfOrderBufferedSequenceMsgs, getSequenceMsgKey(ctx, bufferedSeqMsg.GlobalSeqNum))
+		s.deliverSequenceMsg(ctx, &bufferedSeqMsg)
+	}
+}
+
+// deliverSequenceMsg delivers the next message of the total order.
+func (s *sequencer) deliverSequenceMsg(ctx context.Context, msg *message) {
+	s.removeRequestMsgFromToBeDeliveredBuffered(ctx, msg)
+	s.globalCounter++
+	s.deliveredSequenceMsgs[msg.ID] = true
+	s.deliveredLog[msg.GlobalSeqNum] = *msg
+	s.recordDeliveredRequest(msg)
+	s.skipDeliveredRequestMsgs(ctx, msg)
+	// The message is logged before it's applied, so a crash in between
+	// leaves it in the log, to be applied again on restart (see restore). A
+	// replica that can't log it stops instead of applying what it couldn't
+	// replay.
+	if s.durableLog != nil {
+		if err := s.durableLog.append(ctx, msg); err != nil {
+			log.Fatalf("deliverSequenceMsg(%s): Exception while logging Sequence msg: SeqNo.: %d. %v\n", s.nodeName, msg.GlobalSeqNum, err)
+		}
+	}
+	// A write is not retried when it fails: applying it is deterministic, so
+	// it fails the same way on every replica and the error is the answer to
+	// the client. The service stops the replica when it can't apply a write
+	// at all, and it's applied again from the log on restart.
+	result := s.deliver(ctx, msg)
+	if result.Err != nil {
+		log.Errorf("deliverSequenceMsg(%s): Exception while delivering Sequence msg: SeqNo.: %d, opsType: %d, Err: %v\n", s.nodeName, msg.GlobalSeqNum, msg.OpsType, result.Err)
+	}
+	if responseChan, ok := s.responseTrackers[msg.ID]; ok {
+		responseChan <- result
+		delete(s.responseTrackers, msg.ID)
+	}
+}
+
+// recordDeliveredRequest moves the last delivered request of the node msg
+// came from up to msg. It only depends on the total order, so every node
+// holds the same deliveredRequests after the same message.
+func (s *sequencer) recordDeliveredRequest(msg *message) {
+	advanceRequestWatermark(s.deliveredRequests, msg)
+}
+
+// advanceRequestWatermark moves the watermark of the node msg came from up to
+// msg.
+func advanceRequestWatermark(watermarks map[string]RequestWatermark, msg *message) {
+	delivered, ok := watermarks[msg.RequestNodeName]
+	if ok && (msg.Incarnation < delivered.Incarnation || (msg.Incarnation == delivered.Incarnation && msg.LocalSeqNum <= delivered.LocalSeqNum)) {
+		return
+	}
+	watermarks[msg.RequestNodeName] = RequestWatermark{Incarnation: msg.Incarnation, LocalSeqNum: msg.LocalSeqNum}
+}
+
+// restore resumes from the messages delivered by an earlier run of this node,
+// as read from its durable log: the ones up to snapshot applied through a
+// snapshot, then msgs. The writes of the messages up to applied are already
+// in the database, so they aren't executed again; the ones after it were
+// logged but not applied before the node went down, and are applied now, in
+// order. Whatever was delivered since is fetched from the peers. It must be
+// called before run.
+func (s *sequencer) restore(ctx context.Context, snapshot SnapshotPoint, msgs []message, applied int32) {
+	s.globalCounter = snapshot.GlobalSeqNum
+	s.logBaseSeqNum = snapshot.GlobalSeqNum
+	for nodeName, delivered := range snapshot.DeliveredRequests {
+		s.deliveredRequests[nodeName] = delivered
+		s.logBaseRequests[nodeName] = delivered
+	}
+	for _, msg := range msgs {
+		s.deliveredSequenceMsgs[msg.ID] = true
+		s.deliveredLog[msg.GlobalSeqNum] = msg
+		s.recordDeliveredRequest(&msg)
+		s.globalCounter = msg.GlobalSeqNum
+		if msg.GlobalSeqNum <= applied {
+			continue
+		}
+		log.Infof("restore(%s): Applying Sequence msg: SeqNo.: %d, logged but not applied before the restart\n", s.nodeName, msg.GlobalSeqNum)
+		if result := s.deliver(ctx, &msg); result.Err != nil {
+			log.Errorf("restore(%s): Exception while delivering Sequence msg: SeqNo.: %d, opsType: %d, Err: %v\n", s.nodeName, msg.GlobalSeqNum, msg.OpsType, result.Err)
+		}
+	}
+	s.resumeRequestBuffering(ctx)
+}
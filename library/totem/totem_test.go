@@ -0,0 +1,544 @@
+package totem
+
+import (
+	"context"
+	"fmt"
+	"math/rand"
+	"os"
+	"sync"
+	"testing"
+	"time"
+
+	libProto "github.com/adarshsrinivasan/DS_S24/library/proto"
+	"github.com/adarshsrinivasan/DS_S24/library/replication"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+const (
+	// deliveryTimeout bounds how long a test waits for the group to deliver
+	// everything submitted. Lost messages are only recovered after
+	// retransmitInitialBackoff, so it leaves room for a few retries.
+	deliveryTimeout = 30 * time.Second
+)
+
+func TestMain(m *testing.M) {
+	log.SetLevel(log.WarnLevel)
+	os.Exit(m.Run())
+}
+
+// memNetwork is an in-memory transport between the sequencers of a test
+// group. Messages go through the wire encoding, so receivers never share
+// them with senders, and are dropped with probability dropRate, when drop
+// returns true, or when the receiver's queue is full.
+type memNetwork struct {
+	mu         sync.Mutex
+	sequencers map[string]*sequencer
+	random     *rand.Rand
+	dropRate   float64
+	drop       func(receiverNodeName string, msg *message) bool
+}
+
+// memTransport is the transport of one node of a memNetwork.
+type memTransport struct {
+	network *memNetwork
+}
+
+func (t *memTransport) send(ctx context.Context, receiverNodeName string, msg *message) error {
+	data, err := marshallMsg(ctx, msg)
+	if err != nil {
+		return err
+	}
+	copied, err := unmarshallMsg(ctx, data)
+	if err != nil {
+		return err
+	}
+	t.network.mu.Lock()
+	receiver, found := t.network.sequencers[receiverNodeName]
+	drop := t.network.random.Float64() < t.network.dropRate
+	if t.network.drop != nil && t.network.drop(receiverNodeName, copied) {
+		drop = true
+	}
+	t.network.mu.Unlock()
+	if !found {
+		return fmt.Errorf("unknown node %s", receiverNodeName)
+	}
+	if drop {
+		return nil
+	}
+	// Never block the sender's event loop: the receiver may be sending to
+	// it at the same time.
+	select {
+	case receiver.incoming <- copied:
+	default:
+	}
+	return nil
+}
+
+// testGroup is a group of sequencers over a memNetwork, recording the
+// requests each node delivers.
+type testGroup struct {
+	nodeNames  []string
+	engines    map[string]*Engine
+	network    *memNetwork
+	cancel     context.CancelFunc
+	sequencers []*sequencer
+
+	mu        sync.Mutex
+	delivered map[string][]string
+	barriers  map[string]bool
+}
+
+func newTestGroup(t *testing.T, n int, dropRate float64) *testGroup {
+	t.Helper()
+	g := &testGroup{
+		engines:   map[string]*Engine{},
+		delivered: map[string][]string{},
+		barriers:  map[string]bool{},
+		network: &memNetwork{
+			sequencers: map[string]*sequencer{},
+			random:     rand.New(rand.NewSource(1)),
+			dropRate:   dropRate,
+		},
+	}
+	for i := 0; i < n; i++ {
+		g.nodeNames = append(g.nodeNames, fmt.Sprintf("node%d", i))
+	}
+	ctx, cancel := context.WithCancel(context.Background())
+	g.cancel = cancel
+	for _, nodeName := range g.nodeNames {
+		nodeName := nodeName
+		s := newSequencer(nodeName, g.nodeNames, &memTransport{network: g.network}, nil, nil, func(ctx context.Context, msg *message) replication.Result {
+			g.mu.Lock()
+			defer g.mu.Unlock()
+			g.delivered[nodeName] = append(g.delivered[nodeName], msg.ID)
+			if msg.OpsType == barrierOp {
+				g.barriers[msg.ID] = true
+			}
+			return replication.Result{}
+		})
+		g.network.sequencers[nodeName] = s
+		g.engines[nodeName] = &Engine{config: Config{NodeName: nodeName}, sequencer: s}
+		g.sequencers = append(g.sequencers, s)
+	}
+	for _, s := range g.sequencers {
+		go s.run(ctx)
+	}
+	t.Cleanup(g.stop)
+	return g
+}
+
+// stop stops every sequencer and waits for their event loops to return.
+func (g *testGroup) stop() {
+	g.cancel()
+	for _, s := range g.sequencers {
+		<-s.stopped
+	}
+}
+
+// deliveredBy returns a copy of the request IDs nodeName delivered, in order.
+func (g *testGroup) deliveredBy(nodeName string) []string {
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	return append([]string(nil), g.delivered[nodeName]...)
+}
+
+// waitDelivered waits for every node to deliver count requests and checks
+// that they all delivered the same ones in the same order, each once.
+func (g *testGroup) waitDelivered(t *testing.T, count int) {
+	t.Helper()
+	deadline := time.Now().Add(deliveryTimeout)
+	for _, nodeName := range g.nodeNames {
+		for len(g.deliveredBy(nodeName)) < count {
+			if time.Now().After(deadline) {
+				t.Fatalf("%s delivered %d requests; want %d", nodeName, len(g.deliveredBy(nodeName)), count)
+			}
+			time.Sleep(10 * time.Millisecond)
+		}
+	}
+	reference := g.deliveredBy(g.nodeNames[0])
+	if len(reference) != count {
+		t.Fatalf("%s delivered %d requests; want %d", g.nodeNames[0], len(reference), count)
+	}
+	seen := map[string]bool{}
+	for _, requestID := range reference {
+		if seen[requestID] {
+			t.Fatalf("%s delivered %s twice", g.nodeNames[0], requestID)
+		}
+		seen[requestID] = true
+	}
+	for _, nodeName := range g.nodeNames[1:] {
+		order := g.deliveredBy(nodeName)
+		if len(order) != count {
+			t.Fatalf("%s delivered %d requests; want %d", nodeName, len(order), count)
+		}
+		for i := range order {
+			if order[i] != reference[i] {
+				t.Fatalf("%s delivered %s at position %d, where %s delivered %s", nodeName, order[i], i, g.nodeNames[0], reference[i])
+			}
+		}
+	}
+}
+
+// proposeConcurrently proposes perGoroutine writes from each of goroutines
+// goroutines on every node at once, and fails the test if any of them
+// isn't delivered on the node it was proposed on. It returns the number of
+// writes proposed.
+func (g *testGroup) proposeConcurrently(t *testing.T, goroutines, perGoroutine int) int {
+	t.Helper()
+	var wg sync.WaitGroup
+	errs := make(chan error, len(g.nodeNames)*goroutines*perGoroutine)
+	for _, nodeName := range g.nodeNames {
+		for i := 0; i < goroutines; i++ {
+			wg.Add(1)
+			go func(engine *Engine, i int) {
+				defer wg.Done()
+				for j := 0; j < perGoroutine; j++ {
+					payload := []byte(fmt.Sprintf("%s-%d-%d", engine.config.NodeName, i, j))
+					if result := engine.Propose(context.Background(), 0, payload); result.Err != nil {
+						errs <- fmt.Errorf("%s: %v", payload, result.Err)
+					}
+				}
+			}(g.engines[nodeName], i)
+		}
+	}
+	wg.Wait()
+	close(errs)
+	for err := range errs {
+		t.Errorf("Propose: %v", err)
+	}
+	if t.Failed() {
+		t.FailNow()
+	}
+	return len(g.nodeNames) * goroutines * perGoroutine
+}
+
+func TestConcurrentProposalsAreTotallyOrdered(t *testing.T) {
+	g := newTestGroup(t, 3, 0)
+	count := g.proposeConcurrently(t, 4, 10)
+	g.waitDelivered(t, count)
+}
+
+func TestTotalOrderSurvivesMessageLoss(t *testing.T) {
+	g := newTestGroup(t, 3, 0.1)
+	count := g.proposeConcurrently(t, 2, 5)
+	g.waitDelivered(t, count)
+}
+
+func TestBarrierFollowsEarlierWrites(t *testing.T) {
+	g := newTestGroup(t, 3, 0)
+	count := g.proposeConcurrently(t, 2, 5)
+	engine := g.engines[g.nodeNames[1]]
+	if err := engine.Barrier(context.Background()); err != nil {
+		t.Fatalf("Barrier: %v", err)
+	}
+	// Every write was answered before the barrier was sent, so the barrier
+	// is delivered after all of them.
+	g.waitDelivered(t, count+1)
+	order := g.deliveredBy(g.nodeNames[0])
+	g.mu.Lock()
+	defer g.mu.Unlock()
+	if !g.barriers[order[count]] {
+		t.Fatalf("barrier delivered before position %d", count)
+	}
+}
+
+func TestDroppedFinalMsgIsRecovered(t *testing.T) {
+	for _, droppedType := range []msgType{MsgType_Request, MsgType_Sequence} {
+		t.Run(msgTypeToStr[droppedType], func(t *testing.T) {
+			g := newTestGroup(t, 3, 0)
+			count := g.proposeConcurrently(t, 1, 1)
+			g.waitDelivered(t, count)
+
+			// Every peer loses the first copy of the last write's request, or
+			// of its sequence message. No later write reveals the gap: the
+			// peers must ask for the msg once a heartbeat or sequence message
+			// shows it's missing, or get it resent.
+			dropped := map[string]bool{}
+			g.network.mu.Lock()
+			g.network.drop = func(receiverNodeName string, msg *message) bool {
+				senderNodeName := msg.RequestNodeName
+				if msg.MsgType == MsgType_Sequence {
+					senderNodeName = msg.SequenceNodeName
+				}
+				if msg.MsgType != droppedType || receiverNodeName == senderNodeName || dropped[receiverNodeName] {
+					return false
+				}
+				dropped[receiverNodeName] = true
+				return true
+			}
+			g.network.mu.Unlock()
+
+			engine := g.engines[g.nodeNames[0]]
+			if result := engine.Propose(context.Background(), 0, []byte("last")); result.Err != nil {
+				t.Fatalf("Propose: %v", result.Err)
+			}
+			g.waitDelivered(t, count+1)
+			g.network.mu.Lock()
+			defer g.network.mu.Unlock()
+			if len(dropped) != len(g.nodeNames)-1 {
+				t.Fatalf("dropped %s msgs to %v; want every peer of the sender", msgTypeToStr[droppedType], dropped)
+			}
+		})
+	}
+}
+
+func TestOversizedWriteIsRefused(t *testing.T) {
+	g := newTestGroup(t, 3, 0)
+	engine := g.engines[g.nodeNames[0]]
+	result := engine.Propose(context.Background(), 0, make([]byte, maxPayloadSize+1))
+	if status.Code(result.Err) != codes.InvalidArgument {
+		t.Fatalf("Propose: got %v; want %v", result.Err, codes.InvalidArgument)
+	}
+	// The refused write took no local sequence number, so later writes
+	// aren't held back waiting for it.
+	if result := engine.Propose(context.Background(), 0, []byte("next")); result.Err != nil {
+		t.Fatalf("Propose: %v", result.Err)
+	}
+	g.waitDelivered(t, 1)
+}
+
+func TestFragmentMsgBoundsTheFragmentCount(t *testing.T) {
+	// The largest write fits even in a retransmitted sequence message.
+	msg := &message{
+		ID:                 "b3a1c0de-0000-4000-8000-000000000000",
+		MsgType:            MsgType_Retransmit,
+		Payload:            make([]byte, maxPayloadSize),
+		RequestNodeName:    "node0",
+		SequenceNodeName:   "node1",
+		RetransmitNodeName: "node2",
+		LocalSeqNum:        1 << 30,
+		GlobalSeqNum:       1 << 30,
+		ViewID:             1 << 30,
+		Incarnation:        1 << 62,
+	}
+	data, err := marshallMsg(context.Background(), msg)
+	if err != nil {
+		t.Fatalf("marshallMsg: %v", err)
+	}
+	if _, err := fragmentMsg(nil, "node2", 1, data); err != nil {
+		t.Fatalf("fragmentMsg of the largest write: %v", err)
+	}
+
+	if _, err := fragmentMsg(nil, "node0", 1, make([]byte, maxFragmentCount*maxFragmentSize+1)); err == nil {
+		t.Fatalf("fragmentMsg succeeded with more than %d fragments", maxFragmentCount)
+	}
+}
+
+func TestReassembler(t *testing.T) {
+	type fragment struct {
+		messageID    uint64
+		index, count uint32
+		data         string
+		// after is the time since the first fragment.
+		after   time.Duration
+		want    string
+		wantErr bool
+	}
+	tests := []struct {
+		name        string
+		fragments   []fragment
+		wantPartial int
+	}{
+		{
+			name:      "single fragment",
+			fragments: []fragment{{messageID: 1, index: 0, count: 1, data: "abc", want: "abc"}},
+		},
+		{
+			name: "out of order",
+			fragments: []fragment{
+				{messageID: 1, index: 2, count: 3, data: "c"},
+				{messageID: 1, index: 0, count: 3, data: "a"},
+				{messageID: 1, index: 1, count: 3, data: "b", want: "abc"},
+			},
+		},
+		{
+			name: "duplicate",
+			fragments: []fragment{
+				{messageID: 1, index: 0, count: 2, data: "a"},
+				{messageID: 1, index: 0, count: 2, data: "a"},
+				{messageID: 1, index: 1, count: 2, data: "b", want: "ab"},
+			},
+		},
+		{
+			name: "interleaved msgs",
+			fragments: []fragment{
+				{messageID: 1, index: 0, count: 2, data: "a"},
+				{messageID: 2, index: 1, count: 2, data: "y"},
+				{messageID: 2, index: 0, count: 2, data: "x", want: "xy"},
+				{messageID: 1, index: 1, count: 2, data: "b", want: "ab"},
+			},
+		},
+		{
+			name: "mismatched count",
+			fragments: []fragment{
+				{messageID: 1, index: 0, count: 2, data: "a"},
+				{messageID: 1, index: 1, count: 3, data: "b", wantErr: true},
+			},
+			wantPartial: 1,
+		},
+		{
+			name:      "index out of range",
+			fragments: []fragment{{messageID: 1, index: 2, count: 2, data: "a", wantErr: true}},
+		},
+		{
+			name:      "zero count",
+			fragments: []fragment{{messageID: 1, index: 0, count: 0, data: "a", wantErr: true}},
+		},
+		{
+			name:      "too many fragments",
+			fragments: []fragment{{messageID: 1, index: 0, count: maxFragmentCount + 1, data: "a", wantErr: true}},
+		},
+		{
+			// The first fragment of msg 2 expires msg 1, so its second
+			// fragment starts over.
+			name: "expired",
+			fragments: []fragment{
+				{messageID: 1, index: 0, count: 2, data: "a"},
+				{messageID: 2, index: 0, count: 2, data: "x", after: reassemblyTimeout + time.Second},
+				{messageID: 1, index: 1, count: 2, data: "b", after: reassemblyTimeout + time.Second},
+			},
+			wantPartial: 2,
+		},
+	}
+	start := time.Now()
+	for _, test := range tests {
+		t.Run(test.name, func(t *testing.T) {
+			r := newReassembler()
+			for i, f := range test.fragments {
+				envelope := &libProto.TotemEnvelope{
+					SenderNodeName: "node0",
+					MessageID:      f.messageID,
+					FragmentIndex:  f.index,
+					FragmentCount:  f.count,
+					Data:           []byte(f.data),
+				}
+				got, err := r.add(envelope, start.Add(f.after))
+				if (err != nil) != f.wantErr {
+					t.Fatalf("fragment %d: got error %v; want error %v", i, err, f.wantErr)
+				}
+				if string(got) != f.want || (got == nil) != (f.want == "") {
+					t.Fatalf("fragment %d: got msg %q; want %q", i, got, f.want)
+				}
+			}
+			if len(r.partial) != test.wantPartial {
+				t.Fatalf("%d msgs partially received; want %d", len(r.partial), test.wantPartial)
+			}
+		})
+	}
+}
+
+// sequenceMsg returns the sequence message of a write at globalSeqNum.
+func sequenceMsg(globalSeqNum int32) *message {
+	return &message{
+		ID:               fmt.Sprintf("request%d", globalSeqNum),
+		MsgType:          MsgType_Sequence,
+		RequestNodeName:  "node0",
+		SequenceNodeName: "node0",
+		LocalSeqNum:      globalSeqNum,
+		GlobalSeqNum:     globalSeqNum,
+	}
+}
+
+// newLoggingSequencer returns a single node sequencer logging to dir, which
+// hands every message it delivers to deliver.
+func newLoggingSequencer(t *testing.T, dir string, deliver func(ctx context.Context, msg *message) replication.Result) (*sequencer, []message) {
+	t.Helper()
+	durableLog, _, logged, err := openDeliveryLog(context.Background(), dir)
+	if err != nil {
+		t.Fatalf("openDeliveryLog: %v", err)
+	}
+	t.Cleanup(func() { durableLog.close() })
+	network := &memNetwork{sequencers: map[string]*sequencer{}, random: rand.New(rand.NewSource(1))}
+	return newSequencer("node0", []string{"node0"}, &memTransport{network: network}, durableLog, nil, deliver), logged
+}
+
+func TestMsgIsLoggedBeforeItIsApplied(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, _ := newLoggingSequencer(t, dir, func(ctx context.Context, msg *message) replication.Result {
+		_, _, logged, err := openDeliveryLog(ctx, dir)
+		if err != nil {
+			t.Fatalf("openDeliveryLog: %v", err)
+		}
+		if len(logged) == 0 || logged[len(logged)-1].GlobalSeqNum != msg.GlobalSeqNum {
+			t.Fatalf("msg %d applied before it was logged; the log holds %d msgs", msg.GlobalSeqNum, len(logged))
+		}
+		return replication.Result{}
+	})
+	for globalSeqNum := int32(1); globalSeqNum <= 3; globalSeqNum++ {
+		s.deliverSequenceMsg(ctx, sequenceMsg(globalSeqNum))
+	}
+}
+
+func TestRestoreAppliesMsgsLoggedAfterApplied(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, _ := newLoggingSequencer(t, dir, func(ctx context.Context, msg *message) replication.Result {
+		return replication.Result{}
+	})
+	for globalSeqNum := int32(1); globalSeqNum <= 3; globalSeqNum++ {
+		s.deliverSequenceMsg(ctx, sequenceMsg(globalSeqNum))
+	}
+
+	// The node went down after logging msg 2 and 3, but before their writes
+	// were committed.
+	var applied []int32
+	restarted, logged := newLoggingSequencer(t, dir, func(ctx context.Context, msg *message) replication.Result {
+		applied = append(applied, msg.GlobalSeqNum)
+		return replication.Result{}
+	})
+	restarted.restore(ctx, SnapshotPoint{}, logged, 1)
+	if len(applied) != 2 || applied[0] != 2 || applied[1] != 3 {
+		t.Fatalf("restore applied msgs %v; want [2 3]", applied)
+	}
+	if restarted.globalCounter != 3 {
+		t.Fatalf("restored globalCounter %d; want 3", restarted.globalCounter)
+	}
+}
+
+func TestStartRequiresAppliedSeqNumWithStorageDir(t *testing.T) {
+	engine := NewEngine(Config{NodeName: "node0", StorageDir: t.TempDir()})
+	if err := engine.Start(context.Background(), nil); err == nil {
+		t.Fatalf("Start succeeded without AppliedSeqNum")
+	}
+}
+
+func TestStableMsgsAreCompactedOutOfTheDeliveryLog(t *testing.T) {
+	ctx := context.Background()
+	dir := t.TempDir()
+	s, _ := newLoggingSequencer(t, dir, func(ctx context.Context, msg *message) replication.Result {
+		return replication.Result{}
+	})
+	count := int32(deliveryLogCompactThreshold + 3)
+	for globalSeqNum := int32(1); globalSeqNum <= count; globalSeqNum++ {
+		s.deliverSequenceMsg(ctx, sequenceMsg(globalSeqNum))
+	}
+	// node1 misses the last two messages, which stay in the log.
+	s.view = view{Members: []string{"node0", "node1"}}
+	s.stabilityAcks["node1"] = stabilityAck{GlobalSeqNum: count - 2}
+	s.collectStableMsgs(ctx)
+
+	restarted, logged := newLoggingSequencer(t, dir, func(ctx context.Context, msg *message) replication.Result {
+		t.Fatalf("msg %d applied again", msg.GlobalSeqNum)
+		return replication.Result{}
+	})
+	if len(logged) != 2 || logged[0].GlobalSeqNum != count-1 || logged[1].GlobalSeqNum != count {
+		t.Fatalf("compacted log holds %d msgs; want msgs %d and %d", len(logged), count-1, count)
+	}
+	_, base, _, err := openDeliveryLog(ctx, dir)
+	if err != nil {
+		t.Fatalf("openDeliveryLog: %v", err)
+	}
+	if base.GlobalSeqNum != count-2 || base.DeliveredRequests["node0"].LocalSeqNum != count-2 {
+		t.Fatalf("compacted log base %+v; want sequence msg %d, with request %d of node0", base, count-2, count-2)
+	}
+	restarted.restore(ctx, base, logged, count)
+	if restarted.globalCounter != count {
+		t.Fatalf("restored globalCounter %d; want %d", restarted.globalCounter, count)
+	}
+	if got, want := restarted.deliveredRequests["node0"], s.deliveredRequests["node0"]; got != want {
+		t.Fatalf("restored watermark of node0 %+v; want %+v", got, want)
+	}
+}
@@ -0,0 +1,318 @@
+package totem
+
+import (
+	"context"
+	"encoding/binary"
+	"fmt"
+	"net"
+	"sync/atomic"
+	"time"
+
+	"github.com/adarshsrinivasan/DS_S24/library/peerauth"
+	libProto "github.com/adarshsrinivasan/DS_S24/library/proto"
+	"github.com/adarshsrinivasan/DS_S24/library/replication"
+	log "github.com/sirupsen/logrus"
+	"google.golang.org/protobuf/proto"
+)
+
+const (
+	// totemWireVersion is the version of the TotemEnvelope wire format.
+	// Envelopes of any other version are dropped. Version 2 tags sequence
+	// messages with their view; version 3 adds the log base to heartbeats;
+	// version 4 adds the MAC.
+	totemWireVersion = 4
+
+	// maxFragmentSize is the most message bytes carried by one envelope, so
+	// that a datagram fits in a 1500-byte Ethernet MTU.
+	maxFragmentSize = 1200
+
+	// maxFragmentCount bounds the size of a message a receiver reassembles.
+	maxFragmentCount = 4096
+
+	// maxPayloadSize is the largest write a node broadcasts. It leaves room
+	// in maxFragmentCount fragments for the other fields of the sequence and
+	// retransmit messages carrying the write.
+	maxPayloadSize = maxFragmentCount*maxFragmentSize - 4096
+
+	// reassemblyTimeout is how long a receiver keeps the fragments of a
+	// message that hasn't fully arrived.
+	reassemblyTimeout = 10 * time.Second
+
+	// maxDatagramSize is the largest UDP datagram.
+	maxDatagramSize = 65535
+)
+
+func marshallMsg(ctx context.Context, msg *message) ([]byte, error) {
+	return proto.Marshal(&libProto.TotemMessage{
+		ID:                 msg.ID,
+		MsgType:            int32(msg.MsgType),
+		OpsType:            int32(msg.OpsType),
+		Payload:            msg.Payload,
+		RequestNodeName:    msg.RequestNodeName,
+		SequenceNodeName:   msg.SequenceNodeName,
+		RetransmitNodeName: msg.RetransmitNodeName,
+		LocalSeqNum:        msg.LocalSeqNum,
+		GlobalSeqNum:       msg.GlobalSeqNum,
+		AckType:            int32(msg.ACKType),
+		ViewID:             msg.ViewID,
+		Members:            msg.Members,
+		SenderNodeName:     msg.SenderNodeName,
+		Incarnation:        msg.Incarnation,
+		LogBaseSeqNum:      msg.LogBaseSeqNum,
+	})
+}
+
+func unmarshallMsg(ctx context.Context, msgBytes []byte) (*message, error) {
+	protoMsg := &libProto.TotemMessage{}
+	if err := proto.Unmarshal(msgBytes, protoMsg); err != nil {
+		return nil, err
+	}
+	return &message{
+		ID:                 protoMsg.ID,
+		MsgType:            msgType(protoMsg.MsgType),
+		OpsType:            replication.Op(protoMsg.OpsType),
+		Payload:            protoMsg.Payload,
+		RequestNodeName:    protoMsg.RequestNodeName,
+		SequenceNodeName:   protoMsg.SequenceNodeName,
+		RetransmitNodeName: protoMsg.RetransmitNodeName,
+		LocalSeqNum:        protoMsg.LocalSeqNum,
+		GlobalSeqNum:       protoMsg.GlobalSeqNum,
+		ACKType:            ackType(protoMsg.AckType),
+		ViewID:             protoMsg.ViewID,
+		Members:            protoMsg.Members,
+		SenderNodeName:     protoMsg.SenderNodeName,
+		Incarnation:        protoMsg.Incarnation,
+		LogBaseSeqNum:      protoMsg.LogBaseSeqNum,
+	}, nil
+}
+
+// fragmentMsg splits a marshalled message into envelopes of at most
+// maxFragmentSize bytes each, signed with keyring. It fails if receivers
+// would drop the message for having more than maxFragmentCount fragments.
+func fragmentMsg(keyring *peerauth.Keyring, senderNodeName string, messageID uint64, data []byte) ([]*libProto.TotemEnvelope, error) {
+	fragmentCount := (len(data) + maxFragmentSize - 1) / maxFragmentSize
+	if fragmentCount == 0 {
+		fragmentCount = 1
+	}
+	if fragmentCount > maxFragmentCount {
+		return nil, fmt.Errorf("msg of %d bytes needs %d fragments. expected at most %d", len(data), fragmentCount, maxFragmentCount)
+	}
+	envelopes := make([]*libProto.TotemEnvelope, 0, fragmentCount)
+	for i := 0; i < fragmentCount; i++ {
+		end := (i + 1) * maxFragmentSize
+		if end > len(data) {
+			end = len(data)
+		}
+		envelope := &libProto.TotemEnvelope{
+			Version:        totemWireVersion,
+			SenderNodeName: senderNodeName,
+			MessageID:      messageID,
+			FragmentIndex:  uint32(i),
+			FragmentCount:  uint32(fragmentCount),
+			Data:           data[i*maxFragmentSize : end],
+		}
+		envelope.KeyID, envelope.Mac = keyring.Sign(envelopeMACParts(envelope)...)
+		envelopes = append(envelopes, envelope)
+	}
+	return envelopes, nil
+}
+
+// envelopeMACParts returns what the MAC of an envelope covers: every field
+// but the MAC and its key. Each fragment is signed, so forged fragments are
+// dropped before they reach the reassembler.
+func envelopeMACParts(envelope *libProto.TotemEnvelope) [][]byte {
+	header := make([]byte, 20)
+	binary.BigEndian.PutUint32(header[0:], envelope.Version)
+	binary.BigEndian.PutUint64(header[4:], envelope.MessageID)
+	binary.BigEndian.PutUint32(header[12:], envelope.FragmentIndex)
+	binary.BigEndian.PutUint32(header[16:], envelope.FragmentCount)
+	return [][]byte{header, []byte(envelope.SenderNodeName), envelope.Data}
+}
+
+// partialMsg is a message whose fragments are still arriving.
+type partialMsg struct {
+	fragments [][]byte
+	received  int
+	firstSeen time.Time
+}
+
+// reassembler puts fragmented messages back together. It's used by a single
+// goroutine.
+type reassembler struct {
+	partial map[string]*partialMsg
+}
+
+func newReassembler() *reassembler {
+	return &reassembler{partial: map[string]*partialMsg{}}
+}
+
+// add adds a fragment and returns the message it completes, or nil if
+// fragments are still missing. Duplicate fragments are ignored.
+func (r *reassembler) add(envelope *libProto.TotemEnvelope, now time.Time) ([]byte, error) {
+	count, index := envelope.FragmentCount, envelope.FragmentIndex
+	if count == 0 || count > maxFragmentCount || index >= count {
+		return nil, fmt.Errorf("invalid fragment %d/%d of msg %d from %s", index, count, envelope.MessageID, envelope.SenderNodeName)
+	}
+	if count == 1 {
+		return envelope.Data, nil
+	}
+
+	key := fmt.Sprintf("%s-%d", envelope.SenderNodeName, envelope.MessageID)
+	partial, ok := r.partial[key]
+	if !ok {
+		r.expire(now)
+		partial = &partialMsg{fragments: make([][]byte, count), firstSeen: now}
+		r.partial[key] = partial
+	}
+	if int(count) != len(partial.fragments) {
+		return nil, fmt.Errorf("fragment %d of msg %d from %s has count %d, expected %d", index, envelope.MessageID, envelope.SenderNodeName, count, len(partial.fragments))
+	}
+	if partial.fragments[index] != nil {
+		return nil, nil
+	}
+	partial.fragments[index] = envelope.Data
+	partial.received++
+	if partial.received < len(partial.fragments) {
+		return nil, nil
+	}
+
+	delete(r.partial, key)
+	var data []byte
+	for _, fragment := range partial.fragments {
+		data = append(data, fragment...)
+	}
+	return data, nil
+}
+
+// expire drops the messages whose fragments didn't all arrive within
+// reassemblyTimeout.
+func (r *reassembler) expire(now time.Time) {
+	for key, partial := range r.partial {
+		if now.Sub(partial.firstSeen) > reassemblyTimeout {
+			log.Warnf("reassembler: Dropping msg %s: %d of %d fragments arrived.\n", key, partial.received, len(partial.fragments))
+			delete(r.partial, key)
+		}
+	}
+}
+
+// udpTransport sends messages as UDP datagrams to the sync port of the peer,
+// fragmenting those larger than maxFragmentSize.
+type udpTransport struct {
+	nodeName      string
+	peerNodeNames []string
+	peerNodePorts []string
+	keyring       *peerauth.Keyring
+
+	// nextMessageID numbers the messages sent. It starts from the clock so
+	// that IDs aren't reused across restarts.
+	nextMessageID atomic.Uint64
+}
+
+func newUDPTransport(nodeName string, peerNodeNames, peerNodePorts []string, keyring *peerauth.Keyring) *udpTransport {
+	t := &udpTransport{nodeName: nodeName, peerNodeNames: peerNodeNames, peerNodePorts: peerNodePorts, keyring: keyring}
+	t.nextMessageID.Store(uint64(time.Now().UnixNano()))
+	return t
+}
+
+func (t *udpTransport) send(ctx context.Context, receiverNodeName string, msg *message) error {
+	receiverNodePort := ""
+	for i := 0; i < len(t.peerNodeNames); i++ {
+		if t.peerNodeNames[i] == receiverNodeName {
+			receiverNodePort = t.peerNodePorts[i]
+			break
+		}
+	}
+
+	data, err := marshallMsg(ctx, msg)
+	if err != nil {
+		return fmt.Errorf("exception while marshalling msg. %v", err)
+	}
+	envelopes, err := fragmentMsg(t.keyring, t.nodeName, t.nextMessageID.Add(1), data)
+	if err != nil {
+		return fmt.Errorf("exception while fragmenting msg. %v", err)
+	}
+
+	addr := net.JoinHostPort(receiverNodeName, receiverNodePort)
+	raddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return fmt.Errorf("exception while resolving addr %s. %v", addr, err)
+	}
+	conn, err := net.DialUDP("udp", nil, raddr)
+	if err != nil {
+		return fmt.Errorf("exception while dailing addr %s. %v", addr, err)
+	}
+	defer conn.Close()
+	for _, envelope := range envelopes {
+		datagram, err := proto.Marshal(envelope)
+		if err != nil {
+			return fmt.Errorf("exception while marshalling envelope. %v", err)
+		}
+		if _, err = conn.Write(datagram); err != nil {
+			return fmt.Errorf("exception while writing msg to addr %s. %v", addr, err)
+		}
+	}
+	return nil
+}
+
+// listenFromPeers opens the sync port at addr.
+func listenFromPeers(ctx context.Context, addr string) (*net.UDPConn, error) {
+	raddr, err := net.ResolveUDPAddr("udp", addr)
+	if err != nil {
+		return nil, fmt.Errorf("exception while resolving addr %s. %v", addr, err)
+	}
+	conn, err := net.ListenUDP("udp", raddr)
+	if err != nil {
+		return nil, fmt.Errorf("exception while listening on addr %s. %v", addr, err)
+	}
+	return conn, nil
+}
+
+// receiveFromPeers reads envelopes from the sync port, drops those not signed
+// with a key of keyring, reassembles the others and hands the messages to the
+// sequencer.
+func receiveFromPeers(ctx context.Context, s *sequencer, conn *net.UDPConn, keyring *peerauth.Keyring) {
+	nodeName, addr := s.nodeName, conn.LocalAddr()
+	log.Infof("receiveFromPeers(%s): listening on addr %s...\n", nodeName, addr)
+
+	reassembler := newReassembler()
+	responseBuf := make([]byte, maxDatagramSize)
+	for {
+		readLen, _, err := conn.ReadFrom(responseBuf)
+		if err != nil {
+			select {
+			case <-s.stopped:
+				return
+			default:
+			}
+			log.Errorf("receiveFromPeers(%s): exception while reading incoming msg on addr %s. %v\n", nodeName, addr, err)
+			continue
+		}
+		envelope := &libProto.TotemEnvelope{}
+		if err := proto.Unmarshal(responseBuf[:readLen], envelope); err != nil {
+			log.Errorf("receiveFromPeers(%s): exception while unmarshalling incoming envelope on addr %s. %v\n", nodeName, addr, err)
+			continue
+		}
+		if envelope.Version != totemWireVersion {
+			log.Errorf("receiveFromPeers(%s): Dropping envelope from %s with wire version %d. Expected %d.\n", nodeName, envelope.SenderNodeName, envelope.Version, totemWireVersion)
+			continue
+		}
+		if err := keyring.Verify(envelope.KeyID, envelope.Mac, envelopeMACParts(envelope)...); err != nil {
+			log.Errorf("receiveFromPeers(%s): Dropping envelope claiming to be from %s. %v\n", nodeName, envelope.SenderNodeName, err)
+			continue
+		}
+		data, err := reassembler.add(envelope, time.Now())
+		if err != nil {
+			log.Errorf("receiveFromPeers(%s): exception while reassembling incoming msg on addr %s. %v\n", nodeName, addr, err)
+			continue
+		}
+		if data == nil {
+			continue
+		}
+		if parsedMsg, err := unmarshallMsg(ctx, data); err != nil {
+			log.Errorf("receiveFromPeers(%s): exception while unmarshalling incoming msg on addr %s. %v\n", nodeName, addr, err)
+		} else {
+			log.Infof("receiveFromPeers(%s): Received: %v\n", nodeName, parsedMsg.toString())
+			s.receive(parsedMsg)
+		}
+	}
+}